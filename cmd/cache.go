@@ -32,10 +32,14 @@ func loadConfigAndCache() (*config.Config, *cache.OrgCache, string, error) {
 		return nil, nil, "", fmt.Errorf("failed to load config: %w", err)
 	}
 
-	token := cfg.GetGitHubToken()
-	if token == "" {
-		return nil, nil, "", fmt.Errorf("GITHUB_TOKEN not set in config or environment")
+	resolution, err := cfg.ResolveGitHubToken(config.DefaultGitHubHost)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("GitHub token not available: %w", err)
+	}
+	if verbose {
+		fmt.Printf("Using GitHub token from %s\n", resolution.Source)
 	}
+	token := resolution.Token
 
 	c := cache.New()
 
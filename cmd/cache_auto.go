@@ -38,11 +38,11 @@ var (
 func autoRefreshConfiguredCaches(cfg *config.Config, c *cache.OrgCache) error {
 	refreshTargets := make([]string, 0, len(cfg.GetOrganizations())+1)
 	for _, org := range cfg.GetOrganizations() {
-		if c.IsExpired(org) {
+		if c.IsExpired(org) || c.IsOutdated(org) {
 			refreshTargets = append(refreshTargets, org)
 		}
 	}
-	if c.IsExpired(cache.PersonalCacheKey) {
+	if c.IsExpired(cache.PersonalCacheKey) || c.IsOutdated(cache.PersonalCacheKey) {
 		refreshTargets = append(refreshTargets, cache.PersonalCacheKey)
 	}
 
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/kirksw/ezgit/internal/cache"
@@ -29,12 +30,16 @@ func runRefreshCache(cmd *cobra.Command, args []string) error {
 
 	client := github.NewClient(token)
 
-	if err := client.ValidateToken(); err != nil {
+	tokenInfo, err := client.InspectToken()
+	if err != nil {
 		return fmt.Errorf("invalid GitHub token: %w", err)
 	}
 
 	refreshPersonal := len(args) == 0
 	orgs := cfg.GetOrganizations()
+	if warning := tokenScopeWarning(tokenInfo, len(orgs) > 0); warning != "" {
+		fmt.Println(warning)
+	}
 
 	if len(args) == 1 {
 		if args[0] == cache.PersonalCacheKey {
@@ -111,6 +116,28 @@ func runRefreshCache(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// tokenScopeWarning explains which private repositories a token cannot see,
+// so a short cache is flagged up front instead of looking like missing repos.
+func tokenScopeWarning(info *github.TokenInfo, includeOrgs bool) string {
+	if info == nil {
+		return ""
+	}
+
+	required := []string{"repo"}
+	if includeOrgs {
+		required = append(required, "read:org")
+	}
+	missing := info.MissingScopes(required...)
+	if len(missing) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"Warning: GitHub token is missing scope(s) %s; private repositories will be missing from the cache",
+		strings.Join(missing, ", "),
+	)
+}
+
 func refreshReposIncrementally(
 	c *cache.OrgCache,
 	cacheKey string,
@@ -118,7 +145,9 @@ func refreshReposIncrementally(
 	fetchAll func() ([]github.Repo, error),
 	fetchCreatedAfter func(time.Time) ([]github.Repo, error),
 ) (added int, total int, err error) {
-	if fullRefresh {
+	// Repos cached in an older format lack fields that fetching only newer
+	// repos would never fill in.
+	if fullRefresh || c.IsOutdated(cacheKey) {
 		repos, err := fetchAll()
 		if err != nil {
 			return 0, 0, err
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -147,3 +150,58 @@ func TestRefreshReposIncrementallyFetchesWhenCacheExpired(t *testing.T) {
 		t.Fatalf("fetchAfterCalls = %d, want 1", fetchAfterCalls)
 	}
 }
+
+func TestTokenScopeWarning(t *testing.T) {
+	if got := tokenScopeWarning(&github.TokenInfo{}, true); got != "" {
+		t.Fatalf("tokenScopeWarning(unknown scopes) = %q, want empty", got)
+	}
+
+	info := &github.TokenInfo{ScopesKnown: true, Scopes: []string{"repo"}}
+	if got := tokenScopeWarning(info, false); got != "" {
+		t.Fatalf("tokenScopeWarning(repo, no orgs) = %q, want empty", got)
+	}
+	if got := tokenScopeWarning(info, true); !strings.Contains(got, "read:org") {
+		t.Fatalf("tokenScopeWarning(repo, orgs) = %q, want read:org warning", got)
+	}
+}
+
+func TestRefreshReposIncrementallyFetchesAllForOutdatedCacheFormat(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	c := cache.New()
+	c.SetTTL(time.Hour)
+	if err := c.Set("acme", []github.Repo{{FullName: "acme/existing", CreatedAt: time.Now().Add(-time.Hour)}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	// Metadata written before topics, archived and fork were cached.
+	metadata := fmt.Sprintf(`{"last_refreshed":%q,"ttl":%d}`, time.Now().Format(time.RFC3339Nano), time.Hour)
+	if err := os.WriteFile(filepath.Join(c.Dir(), "acme.meta.json"), []byte(metadata), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsOutdated("acme") {
+		t.Fatal("IsOutdated() = false for metadata without a format version")
+	}
+
+	_, total, err := refreshReposIncrementally(
+		c,
+		"acme",
+		false,
+		func() ([]github.Repo, error) {
+			return []github.Repo{{FullName: "acme/existing", Topics: []string{"go"}, Archived: true}}, nil
+		},
+		func(createdAfter time.Time) ([]github.Repo, error) {
+			return nil, errors.New("should not fetch incrementally")
+		},
+	)
+	if err != nil || total != 1 {
+		t.Fatalf("refreshReposIncrementally() = %d, %v; want a full refresh", total, err)
+	}
+	cached, err := c.Get("acme")
+	if err != nil || len(cached.Repos) != 1 || !cached.Repos[0].Archived || len(cached.Repos[0].Topics) != 1 {
+		t.Fatalf("Get() = %+v, %v; want the refreshed fields", cached, err)
+	}
+	if c.IsOutdated("acme") {
+		t.Fatal("IsOutdated() = true after a full refresh")
+	}
+}
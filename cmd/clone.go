@@ -49,8 +49,8 @@ type cloneWorktreePlan struct {
 
 var cloneCmd = &cobra.Command{
 	Use:   "clone <repo> [worktreename]",
-	Short: "Clone a GitHub repository",
-	Args:  cobra.RangeArgs(1, 2),
+	Short: "Clone a GitHub repository, or every cached repository of an org with --org",
+	Args:  cloneArgs,
 	RunE:  runClone,
 }
 
@@ -65,6 +65,11 @@ var (
 	worktree                bool
 	branch                  string
 	depth                   int
+	partialFilter           string
+	sparsePatterns          []string
+	recurseSubmodules       bool
+	shallowSubmodules       bool
+	lfsMode                 string
 	quiet                   bool
 	keyPath                 string
 	cloneDest               string
@@ -74,6 +79,7 @@ var (
 	skipExistingPrompt      bool // set internally to bypass existing repo action prompt
 	forcedClonePlan         *cloneWorktreePlan
 	loadRepoDefaultBranches = loadRepoDefaultBranchesFromCache
+	probeSSHAccess          = git.ProbeSSH
 	runZoxideAdd            = func(path string) error {
 		cmd := exec.Command("zoxide", "add", path)
 		_, err := cmd.CombinedOutput()
@@ -81,6 +87,11 @@ var (
 	}
 )
 
+var (
+	autoProtocolMu     sync.Mutex
+	autoProtocolByHost = make(map[string]string)
+)
+
 var (
 	defaultBranchLookupMu    sync.RWMutex
 	defaultBranchLookupHome  string
@@ -92,13 +103,23 @@ func init() {
 
 	addCloneFlags(rootCmd, false)
 	addCloneFlags(cloneCmd, true)
+
+	cloneCmd.Flags().StringVar(&cloneOrg, "org", "", "clone every cached repository of this org that is not cloned yet")
+	cloneCmd.Flags().StringArrayVar(&cloneFilters, "filter", nil, "with --org, only clone repos matching key:value terms (topic, language, name, visibility, fork)")
+	cloneCmd.Flags().BoolVar(&cloneExcludeArchived, "exclude-archived", false, "with --org, skip archived repositories")
+	cloneCmd.Flags().IntVarP(&cloneJobs, "jobs", "j", defaultWorktreeLookupConcurrency, "with --org, number of repositories to clone in parallel")
 }
 
 func addCloneFlags(cmd *cobra.Command, includeLayout bool) {
 	cmd.Flags().StringVarP(&branch, "branch", "b", "", "clone specific branch (non-worktree clones)")
 	cmd.Flags().IntVar(&depth, "depth", 0, "create a shallow clone with specified depth")
+	cmd.Flags().StringVar(&partialFilter, "partial", "", "create a partial clone with this filter: blob:none or tree:0")
+	cmd.Flags().StringSliceVar(&sparsePatterns, "sparse", nil, "sparse-checkout cone directories for the clone and every worktree created in it")
+	cmd.Flags().BoolVar(&recurseSubmodules, "recurse-submodules", false, "check out submodules in the clone and every worktree created in it")
+	cmd.Flags().BoolVar(&shallowSubmodules, "shallow-submodules", false, "like --recurse-submodules, with submodule history limited to depth 1")
+	cmd.Flags().StringVar(&lfsMode, "lfs", "", "Git LFS objects in the clone and every worktree: pull (download) or skip (keep pointer files)")
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "suppress output")
-	cmd.Flags().StringVar(&keyPath, "key-path", "", "SSH key path (default: ~/.ssh/id_rsa)")
+	cmd.Flags().StringVar(&keyPath, "key-path", "", "SSH key to pin for the clone (default: ssh agent, ~/.ssh/config or a default key)")
 	cmd.Flags().StringVarP(&cloneDest, "dest", "d", "", "destination directory")
 	if includeLayout {
 		cmd.Flags().BoolVarP(&worktree, "worktree", "w", false, "clone as bare metadata repo with default worktrees")
@@ -117,6 +138,64 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	return runCloneWithWorktreeAndBase(cfg, args[0], args[1], "")
 }
 
+// resolvePartialCloneOptions returns the partial clone filter and sparse
+// patterns for a repo. Flags win over the first matching [git].clone_rules
+// entry.
+func resolvePartialCloneOptions(cfg *config.Config, repoFullName, filter string, sparse []string) (string, []string, error) {
+	rule := cfg.RepoCloneRule(repoFullName)
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		filter = strings.TrimSpace(rule.Filter)
+	}
+	if err := git.ValidateCloneFilter(filter); err != nil {
+		return "", nil, err
+	}
+
+	if len(sparse) == 0 {
+		sparse = rule.Sparse
+	}
+	var patterns []string
+	for _, pattern := range sparse {
+		pattern = strings.Trim(strings.TrimSpace(pattern), "/")
+		if pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return filter, patterns, nil
+}
+
+// submodulesFlag returns the submodules mode selected by
+// --recurse-submodules/--shallow-submodules.
+func submodulesFlag() string {
+	switch {
+	case shallowSubmodules:
+		return git.SubmodulesShallow
+	case recurseSubmodules:
+		return git.SubmodulesRecursive
+	default:
+		return ""
+	}
+}
+
+// resolveCheckoutExtras returns the submodule and LFS modes for a repo.
+// Non-empty fields of extras (from flags or a manifest) win over the first
+// matching [git].clone_rules entry.
+func resolveCheckoutExtras(cfg *config.Config, repoFullName string, extras git.CheckoutExtras) (git.CheckoutExtras, error) {
+	rule := cfg.RepoCloneRule(repoFullName)
+	if strings.TrimSpace(extras.Submodules) == "" {
+		extras.Submodules = rule.Submodules
+	}
+	if strings.TrimSpace(extras.LFS) == "" {
+		extras.LFS = rule.LFS
+	}
+	extras.Submodules = strings.ToLower(strings.TrimSpace(extras.Submodules))
+	extras.LFS = strings.ToLower(strings.TrimSpace(extras.LFS))
+	if err := git.ValidateCheckoutExtras(extras); err != nil {
+		return git.CheckoutExtras{}, err
+	}
+	return extras, nil
+}
+
 func resolveClonePaths(dest string, asWorktree bool) (cloneTarget string, metadataPath string) {
 	if !asWorktree {
 		return dest, dest
@@ -165,6 +244,13 @@ func runClone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--feature and --feature-base require --worktree")
 	}
 
+	if strings.TrimSpace(cloneOrg) != "" {
+		if branch != "" || strings.TrimSpace(featureWorktree) != "" || cloneDest != "" {
+			return fmt.Errorf("--branch, --feature and --dest are not supported with --org")
+		}
+		return runBulkClone(cfg)
+	}
+
 	if len(args) == 2 {
 		worktreeName := args[1]
 		return runCloneWithWorktree(cfg, args[0], worktreeName)
@@ -173,6 +259,18 @@ func runClone(cmd *cobra.Command, args []string) error {
 	return runDirectClone(cfg, args[0], "", 0)
 }
 
+// cloneArgs requires a repository unless --org selects a bulk clone, so
+// `ezgit clone` never falls through to the TUI.
+func cloneArgs(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(cloneOrg) != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--org cannot be combined with a repository argument")
+		}
+		return nil
+	}
+	return cobra.RangeArgs(1, 2)(cmd, args)
+}
+
 func runFuzzyClone(cfg *config.Config, openMode bool) error {
 	c := cache.New()
 	allRepos, err := c.GetAllRepos()
@@ -345,21 +443,17 @@ func runDirectClone(cfg *config.Config, repoInput string, defaultBranch string,
 		return fmt.Errorf("--feature and --feature-base require --worktree")
 	}
 
-	repoURL, err := git.ParseRepoURL(repoInput)
+	repoURL, err := repoCloneURL(cfg, repoInput)
 	if err != nil {
 		return fmt.Errorf("invalid repo format: %w", err)
 	}
 
-	sshKey := keyPath
-	if sshKey == "" {
-		home, _ := os.UserHomeDir()
-		sshKey = filepath.Join(home, ".ssh", "id_rsa")
-	}
-
-	gitMgr := git.New()
+	fullName, _ := extractRepoFullName(repoInput)
+	gitMgr := newGitManager(cfg)
 
-	if err := gitMgr.ValidateSSHKey(sshKey); err != nil {
-		return fmt.Errorf("SSH key validation failed: %w", err)
+	sshKey, err := resolveSSHKey(cfg, gitMgr, keyPath, repoURL, fullName)
+	if err != nil {
+		return err
 	}
 
 	dest := cloneDest
@@ -387,9 +481,17 @@ func runDirectClone(cfg *config.Config, repoInput string, defaultBranch string,
 		}
 	}
 
+	cloneFilter, cloneSparse, err := resolvePartialCloneOptions(cfg, fullName, partialFilter, sparsePatterns)
+	if err != nil {
+		return err
+	}
+	cloneExtras, err := resolveCheckoutExtras(cfg, fullName, git.CheckoutExtras{Submodules: submodulesFlag(), LFS: lfsMode})
+	if err != nil {
+		return err
+	}
 	cloneDepth := depth
 	if !skipWorktreePrompt && !quiet && isInteractiveStdin() {
-		cloneDepth = resolveCloneDepthForLargeRepo(cfg, repoInput, repoSizeHintKB, cloneDepth, os.Stdin, os.Stdout)
+		cloneDepth, cloneFilter = resolveCloneOptionsForLargeRepo(cfg, repoInput, repoSizeHintKB, cloneDepth, cloneFilter, os.Stdin, os.Stdout)
 	}
 
 	cloneTarget, metadataPath := resolveClonePaths(dest, worktree)
@@ -433,10 +535,10 @@ func runDirectClone(cfg *config.Config, repoInput string, defaultBranch string,
 				return runOpenCommand(cfg, repoFullName, "")
 			case existingRepoActionConvert:
 				convertDefaultBranch := resolveDefaultBranch(repoInput, defaultBranch)
-				if err := runConvertPath(dest, convertDefaultBranch); err != nil {
+				if err := runConvertPath(cfg, dest, convertDefaultBranch); err != nil {
 					return err
 				}
-				registerRepoAndWorktreesWithZoxide(git.New(), dest, quiet)
+				registerRepoAndWorktreesWithZoxide(gitMgr, dest, quiet)
 				return nil
 			default:
 				return fmt.Errorf("cancelled")
@@ -454,7 +556,7 @@ func runDirectClone(cfg *config.Config, repoInput string, defaultBranch string,
 	}
 
 	if worktree {
-		if err := os.MkdirAll(dest, 0755); err != nil {
+		if err := mkdirAll(dest); err != nil {
 			return fmt.Errorf("failed to create destination directory: %w", err)
 		}
 	}
@@ -465,6 +567,11 @@ func runDirectClone(cfg *config.Config, repoInput string, defaultBranch string,
 		Depth:      cloneDepth,
 		Quiet:      quiet,
 		SSHKeyPath: sshKey,
+		Filter:     cloneFilter,
+		Sparse:     cloneSparse,
+		Config:     cloneConfig(cfg, repoURL),
+
+		CheckoutExtras: cloneExtras,
 	}
 
 	if !quiet && didClone {
@@ -623,7 +730,7 @@ func runCloneWithWorktreeAndBase(cfg *config.Config, repoInput string, worktreeN
 		return err
 	}
 
-	gitMgr := git.New()
+	gitMgr := newGitManager(cfg)
 
 	// Check if the repo is already cloned.
 	repoState, stateErr := detectExistingRepoState(dest)
@@ -648,7 +755,7 @@ func runCloneWithWorktreeAndBase(cfg *config.Config, repoInput string, worktreeN
 				return runOpenCommand(cfg, repoFullName, "")
 			case existingRepoActionConvert:
 				convertDefaultBranch := resolveDefaultBranch(repoInput, "")
-				if err := runConvertPath(dest, convertDefaultBranch); err != nil {
+				if err := runConvertPath(cfg, dest, convertDefaultBranch); err != nil {
 					return err
 				}
 				registerRepoAndWorktreesWithZoxide(gitMgr, dest, quiet)
@@ -792,6 +899,120 @@ func readLineTrimmed(in io.Reader) (string, error) {
 	return strings.TrimSpace(line), nil
 }
 
+// resolveCloneURL returns the clone URL of repoInput. owner/repo and
+// host/owner/repo inputs use the protocol configured for the repo on that
+// host. Full URLs are rewritten to it only when a protocol rule or
+// [git].protocol applies, and used as given otherwise or when they are not
+// plain host/owner/repo URLs.
+func resolveCloneURL(cfg *config.Config, repoInput string) (string, error) {
+	trimmed := strings.TrimSpace(repoInput)
+	ref, ok := git.ParseRepoRef(trimmed)
+	if !ok {
+		return git.ParseRepoURL(trimmed)
+	}
+	isURL := strings.Contains(trimmed, "://") || strings.HasPrefix(trimmed, "git@")
+	if isURL && cfg.ConfiguredRepoProtocol(ref.Host, ref.FullName) == "" {
+		return git.ParseRepoURL(trimmed)
+	}
+	protocol, err := cloneProtocol(cfg, ref.Host, ref.FullName)
+	if err != nil {
+		return "", err
+	}
+	return ref.URL(protocol == config.ProtocolHTTPS), nil
+}
+
+// cloneProtocol resolves the configured protocol for a repo; auto picks SSH
+// when it can authenticate to host and HTTPS otherwise.
+func cloneProtocol(cfg *config.Config, host, repoFullName string) (string, error) {
+	switch protocol := cfg.RepoProtocol(host, repoFullName); protocol {
+	case config.ProtocolSSH, config.ProtocolHTTPS:
+		return protocol, nil
+	case config.ProtocolAuto:
+		return autoCloneProtocol(host), nil
+	default:
+		return "", fmt.Errorf("unknown clone protocol %q (use ssh, https or auto)", protocol)
+	}
+}
+
+// autoCloneProtocol probes SSH once per host and invocation.
+func autoCloneProtocol(host string) string {
+	autoProtocolMu.Lock()
+	defer autoProtocolMu.Unlock()
+	if protocol, ok := autoProtocolByHost[host]; ok {
+		return protocol
+	}
+	protocol := config.ProtocolHTTPS
+	if err := probeSSHAccess(host); err == nil {
+		protocol = config.ProtocolSSH
+	}
+	autoProtocolByHost[host] = protocol
+	return protocol
+}
+
+// cloneConfig returns the git config stored in an HTTPS clone of repoURL:
+// ezgit as a credential helper when the host is a GitHub host ezgit has a
+// token for, so the token never ends up in the URL, and [git].proxy. Helpers
+// from the user's own config are kept, so git falls through to them when
+// ezgit has no answer, and other hosts never see a GitHub token.
+func cloneConfig(cfg *config.Config, repoURL string) []string {
+	host, ok := git.HTTPSHost(repoURL)
+	if !ok {
+		return nil
+	}
+	var settings []string
+	if cfg.IsGitHubHost(host) {
+		settings = append(settings, fmt.Sprintf("credential.https://%s.helper=%s", host, credentialHelperCommand()))
+	}
+	if proxy := strings.TrimSpace(cfg.Git.Proxy); proxy != "" {
+		settings = append(settings, "http.proxy="+proxy)
+	}
+	return settings
+}
+
+// cloneSSHKey returns the key to pin for cloning repoFullName from repoURL:
+// explicit (--key-path), else the first matching [git].ssh_keys entry. An
+// empty key leaves the choice to ssh (agent, ~/.ssh/config or a default key).
+func cloneSSHKey(cfg *config.Config, explicit, repoURL, repoFullName string) string {
+	if key := strings.TrimSpace(explicit); key != "" {
+		return key
+	}
+	host, ok := git.SSHHost(repoURL)
+	if !ok {
+		return ""
+	}
+	return cfg.RepoSSHKey(host, repoFullName)
+}
+
+// resolveSSHKey returns cloneSSHKey after validating it. Only a pinned key
+// is validated; without one it warns when ssh has no identity to offer.
+func resolveSSHKey(cfg *config.Config, gitMgr git.GitManager, explicit, repoURL, repoFullName string) (string, error) {
+	key := cloneSSHKey(cfg, explicit, repoURL, repoFullName)
+	if key == "" {
+		reportSSHIdentity(repoURL)
+		return "", nil
+	}
+	if err := gitMgr.ValidateSSHKey(key); err != nil {
+		return "", fmt.Errorf("SSH key validation failed: %w", err)
+	}
+	return key, nil
+}
+
+// reportSSHIdentity warns when ssh will find no identity for repoURL's host,
+// and with --verbose says which one it will use.
+func reportSSHIdentity(repoURL string) {
+	host, ok := git.SSHHost(repoURL)
+	if !ok || quiet {
+		return
+	}
+	identity, found := git.DiscoverSSHIdentity(host)
+	switch {
+	case !found:
+		fmt.Printf("Warning: no SSH agent, ~/.ssh/config identity or default key found for %s; use --key-path or [git].ssh_keys\n", host)
+	case verbose:
+		fmt.Printf("Using SSH identity from %s (%s)\n", identity.Source, identity.Path)
+	}
+}
+
 func registerRepoAndWorktreesWithZoxide(gitMgr git.GitManager, repoRoot string, quiet bool) {
 	paths := []string{repoRoot}
 	worktrees, err := gitMgr.ListWorktrees(repoRoot)
@@ -823,6 +1044,10 @@ func registerPathsWithZoxide(paths []string, quiet bool) {
 		}
 		seen[absPath] = struct{}{}
 
+		if dryRun {
+			planStep("zoxide add %s", utils.ShellQuote(absPath))
+			continue
+		}
 		if err := runZoxideAdd(absPath); err != nil && !quiet {
 			fmt.Printf("Warning: failed to add %s to zoxide: %v\n", absPath, err)
 		}
@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+)
+
+var (
+	cloneOrg             string
+	cloneFilters         []string
+	cloneExcludeArchived bool
+	cloneJobs            int
+
+	repoCloneURL = resolveCloneURL
+)
+
+type bulkCloneOptions struct {
+	Jobs          int
+	Depth         int
+	Filter        string
+	Sparse        []string
+	Extras        git.CheckoutExtras
+	SSHKeyPath    string
+	ForceWorktree bool
+	Progress      io.Writer
+}
+
+type bulkCloneResult struct {
+	FullName string
+	Layout   string
+	Err      error
+}
+
+// repoFilter reports whether a repo matches one --filter term.
+type repoFilter func(repo github.Repo) bool
+
+func runBulkClone(cfg *config.Config) error {
+	repos, err := collectCachedRepos(cache.New())
+	if err != nil {
+		return err
+	}
+	repos = filterReposByOrg(repos, cloneOrg)
+	if len(repos) == 0 {
+		return fmt.Errorf("no cached repositories for %s. Run 'ezgit cache refresh' to fetch repos", cloneOrg)
+	}
+
+	filters, err := parseRepoFilters(cloneFilters)
+	if err != nil {
+		return err
+	}
+	repos = selectReposForBulkClone(repos, filters, cloneExcludeArchived)
+
+	localRepos := make(map[string]bool)
+	for _, repo := range repos {
+		if state, err := detectExistingRepoState(getRepoPath(cfg, repo.FullName, false, repo.DefaultBranch)); err == nil && state != existingRepoMissing {
+			localRepos[repo.FullName] = true
+		}
+	}
+	var missing []github.Repo
+	for _, repo := range repos {
+		if !localRepos[repo.FullName] {
+			missing = append(missing, repo)
+		}
+	}
+
+	if !quiet {
+		fmt.Printf("%d repositories match, %d already cloned, %d to clone\n", len(repos), len(localRepos), len(missing))
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// Keys from [git].ssh_keys are resolved per repo; Clone validates them.
+	sshKey := strings.TrimSpace(keyPath)
+	gitMgr := newGitManager(cfg)
+	if sshKey != "" {
+		if err := gitMgr.ValidateSSHKey(sshKey); err != nil {
+			return fmt.Errorf("SSH key validation failed: %w", err)
+		}
+	} else if repoURL, err := repoCloneURL(cfg, missing[0].FullName); err == nil {
+		reportSSHIdentity(repoURL)
+	}
+
+	if err := git.ValidateCloneFilter(partialFilter); err != nil {
+		return err
+	}
+	if err := git.ValidateCheckoutExtras(git.CheckoutExtras{Submodules: submodulesFlag(), LFS: lfsMode}); err != nil {
+		return err
+	}
+	opts := bulkCloneOptions{
+		Jobs:          cloneJobs,
+		Depth:         depth,
+		Filter:        partialFilter,
+		Sparse:        sparsePatterns,
+		Extras:        git.CheckoutExtras{Submodules: submodulesFlag(), LFS: lfsMode},
+		SSHKeyPath:    sshKey,
+		ForceWorktree: worktree,
+	}
+	if !quiet {
+		opts.Progress = os.Stdout
+	}
+
+	results := cloneReposInParallel(cfg, gitMgr, missing, opts)
+
+	var failures []bulkCloneResult
+	for _, result := range results {
+		if result.Err != nil {
+			failures = append(failures, result)
+		}
+	}
+	if !quiet {
+		fmt.Printf("\nCloned %d, failed %d\n", len(results)-len(failures), len(failures))
+	}
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "  %s: %v\n", failure.FullName, failure.Err)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to clone %d repositories", len(failures))
+	}
+	return nil
+}
+
+// parseRepoFilters parses --filter terms. Each term is key:value with key one
+// of topic, language, name (glob on the repo name), visibility
+// (public/private) or fork (true/false). All terms must match.
+func parseRepoFilters(exprs []string) ([]repoFilter, error) {
+	var filters []repoFilter
+	for _, expr := range exprs {
+		for _, term := range strings.Fields(expr) {
+			key, value, ok := strings.Cut(term, ":")
+			value = strings.ToLower(strings.TrimSpace(value))
+			if !ok || value == "" {
+				return nil, fmt.Errorf("invalid filter %q: expected key:value", term)
+			}
+
+			switch strings.ToLower(key) {
+			case "topic":
+				filters = append(filters, func(repo github.Repo) bool {
+					for _, topic := range repo.Topics {
+						if strings.EqualFold(topic, value) {
+							return true
+						}
+					}
+					return false
+				})
+			case "language":
+				filters = append(filters, func(repo github.Repo) bool {
+					return strings.EqualFold(repo.Language, value)
+				})
+			case "name":
+				if _, err := path.Match(value, ""); err != nil {
+					return nil, fmt.Errorf("invalid filter %q: %w", term, err)
+				}
+				filters = append(filters, func(repo github.Repo) bool {
+					matched, _ := path.Match(value, strings.ToLower(repo.Name))
+					return matched
+				})
+			case "visibility":
+				if value != "public" && value != "private" {
+					return nil, fmt.Errorf("invalid filter %q: visibility must be public or private", term)
+				}
+				filters = append(filters, func(repo github.Repo) bool {
+					return repo.Private == (value == "private")
+				})
+			case "fork":
+				if value != "true" && value != "false" {
+					return nil, fmt.Errorf("invalid filter %q: fork must be true or false", term)
+				}
+				filters = append(filters, func(repo github.Repo) bool {
+					return repo.Fork == (value == "true")
+				})
+			default:
+				return nil, fmt.Errorf("unknown filter key %q (use topic, language, name, visibility or fork)", key)
+			}
+		}
+	}
+	return filters, nil
+}
+
+func selectReposForBulkClone(repos []github.Repo, filters []repoFilter, excludeArchived bool) []github.Repo {
+	var selected []github.Repo
+	for _, repo := range repos {
+		if excludeArchived && repo.Archived {
+			continue
+		}
+		matches := true
+		for _, filter := range filters {
+			if !filter(repo) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			selected = append(selected, repo)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].FullName < selected[j].FullName })
+	return selected
+}
+
+// bulkCloneLayout resolves the layout for one repo: --worktree forces the
+// worktree layout, otherwise the first matching [git].layout_rules entry
+// wins, falling back to a regular clone.
+func bulkCloneLayout(cfg *config.Config, repoFullName string, forceWorktree bool) (string, error) {
+	if forceWorktree {
+		return config.LayoutWorktree, nil
+	}
+	switch layout := cfg.RepoLayout(repoFullName); layout {
+	case "", config.LayoutRegular:
+		return config.LayoutRegular, nil
+	case config.LayoutWorktree:
+		return layout, nil
+	default:
+		return "", fmt.Errorf("unknown layout %q in [git].layout_rules", layout)
+	}
+}
+
+// cloneReposInParallel clones repos with bounded concurrency. It never stops
+// early: every repo gets a result, failed or not.
+func cloneReposInParallel(cfg *config.Config, gitMgr git.GitManager, repos []github.Repo, opts bulkCloneOptions) []bulkCloneResult {
+	workers := opts.Jobs
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan github.Repo)
+	var wg sync.WaitGroup
+	var resultMu sync.Mutex
+	results := make([]bulkCloneResult, 0, len(repos))
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range jobs {
+				result := cloneRepoForBulk(cfg, gitMgr, repo, opts)
+
+				resultMu.Lock()
+				results = append(results, result)
+				if opts.Progress != nil {
+					if result.Err != nil {
+						fmt.Fprintf(opts.Progress, "[%d/%d] ✗ %s: %s\n", len(results), len(repos), result.FullName, firstErrorLine(result.Err.Error()))
+					} else {
+						fmt.Fprintf(opts.Progress, "[%d/%d] ✓ %s (%s)\n", len(results), len(repos), result.FullName, result.Layout)
+					}
+				}
+				resultMu.Unlock()
+			}
+		}()
+	}
+
+	for _, repo := range repos {
+		jobs <- repo
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].FullName < results[j].FullName })
+	return results
+}
+
+// cloneRepoForBulk clones a single repo without prompts. Unlike
+// runDirectClone it only reads its arguments, so it is safe to run in
+// parallel. A failed clone leaves nothing behind at its destination, which
+// would otherwise count as cloned on the next run.
+func cloneRepoForBulk(cfg *config.Config, gitMgr git.GitManager, repo github.Repo, opts bulkCloneOptions) (result bulkCloneResult) {
+	result = bulkCloneResult{FullName: repo.FullName}
+
+	layout, err := bulkCloneLayout(cfg, repo.FullName, opts.ForceWorktree)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Layout = layout
+
+	repoURL, err := repoCloneURL(cfg, repo.FullName)
+	if err != nil {
+		result.Err = fmt.Errorf("invalid repo format: %w", err)
+		return result
+	}
+
+	dest := getRepoPath(cfg, repo.FullName, false, repo.DefaultBranch)
+	if _, err := os.Lstat(dest); os.IsNotExist(err) && !dryRun {
+		defer func() {
+			if result.Err != nil {
+				_ = os.RemoveAll(dest)
+			}
+		}()
+	}
+	asWorktree := layout == config.LayoutWorktree
+	cloneTarget, metadataPath := resolveClonePaths(dest, asWorktree)
+	if asWorktree {
+		if err := mkdirAll(dest); err != nil {
+			result.Err = fmt.Errorf("failed to create destination directory: %w", err)
+			return result
+		}
+	}
+
+	filter, sparse, err := resolvePartialCloneOptions(cfg, repo.FullName, opts.Filter, opts.Sparse)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	extras, err := resolveCheckoutExtras(cfg, repo.FullName, opts.Extras)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	cloneOpts := git.CloneOptions{
+		Bare:       asWorktree,
+		Depth:      opts.Depth,
+		Quiet:      true,
+		SSHKeyPath: cloneSSHKey(cfg, opts.SSHKeyPath, repoURL, repo.FullName),
+		Filter:     filter,
+		Sparse:     sparse,
+		Config:     cloneConfig(cfg, repoURL),
+
+		CheckoutExtras: extras,
+	}
+	if err := gitMgr.Clone(repoURL, cloneTarget, cloneOpts); err != nil {
+		result.Err = err
+		return result
+	}
+
+	if asWorktree {
+		defaultBranch := strings.TrimSpace(repo.DefaultBranch)
+		if defaultBranch == "" {
+			defaultBranch = "main"
+		}
+		if err := gitMgr.ConfigureBareRemote(metadataPath, defaultBranch); err != nil {
+			result.Err = fmt.Errorf("failed to configure bare remote: %w", err)
+			return result
+		}
+		if err := gitMgr.CreateWorktree(metadataPath, filepath.Join(dest, defaultBranch), defaultBranch); err != nil {
+			result.Err = fmt.Errorf("failed to create default worktree for branch %s: %w", defaultBranch, err)
+			return result
+		}
+		if err := gitMgr.CreateDetachedWorktree(metadataPath, filepath.Join(dest, "review"), defaultBranch); err != nil {
+			result.Err = fmt.Errorf("failed to create review worktree from branch %s: %w", defaultBranch, err)
+			return result
+		}
+	}
+
+	registerRepoAndWorktreesWithZoxide(gitMgr, dest, true)
+	return result
+}
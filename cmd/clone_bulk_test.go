@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+)
+
+func TestSelectReposForBulkClone(t *testing.T) {
+	repos := []github.Repo{
+		{Name: "api", FullName: "acme/api", Topics: []string{"backend", "go"}, Language: "Go"},
+		{Name: "web", FullName: "acme/web", Topics: []string{"frontend"}, Language: "TypeScript"},
+		{Name: "old-api", FullName: "acme/old-api", Topics: []string{"backend"}, Language: "Go", Archived: true},
+		{Name: "billing-api", FullName: "acme/billing-api", Topics: []string{"Backend"}, Language: "Go", Private: true, Fork: true},
+	}
+
+	names := func(repos []github.Repo) []string {
+		var result []string
+		for _, repo := range repos {
+			result = append(result, repo.FullName)
+		}
+		return result
+	}
+
+	cases := []struct {
+		filters         []string
+		excludeArchived bool
+		want            []string
+	}{
+		{nil, false, []string{"acme/api", "acme/billing-api", "acme/old-api", "acme/web"}},
+		{[]string{"topic:backend"}, true, []string{"acme/api", "acme/billing-api"}},
+		{[]string{"topic:backend language:go", "name:*-api"}, false, []string{"acme/billing-api", "acme/old-api"}},
+		{[]string{"visibility:public", "fork:false"}, true, []string{"acme/api", "acme/web"}},
+	}
+	for _, tc := range cases {
+		filters, err := parseRepoFilters(tc.filters)
+		if err != nil {
+			t.Fatalf("parseRepoFilters(%v) error = %v", tc.filters, err)
+		}
+		if got := names(selectReposForBulkClone(repos, filters, tc.excludeArchived)); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("filters %v exclude-archived=%v = %v, want %v", tc.filters, tc.excludeArchived, got, tc.want)
+		}
+	}
+}
+
+func TestParseRepoFiltersRejectsInvalidTerms(t *testing.T) {
+	for _, expr := range []string{"backend", "owner:acme", "visibility:internal", "fork:maybe", "name:[", "topic:"} {
+		if _, err := parseRepoFilters([]string{expr}); err == nil {
+			t.Fatalf("parseRepoFilters(%q) expected error", expr)
+		}
+	}
+}
+
+func TestBulkCloneLayout(t *testing.T) {
+	cfg := &config.Config{Git: config.GitConfig{LayoutRules: []config.LayoutRule{
+		{Match: "acme/mono", Layout: "worktree"},
+		{Match: "acme/broken", Layout: "sparse"},
+	}}}
+
+	if layout, _ := bulkCloneLayout(cfg, "acme/mono", false); layout != config.LayoutWorktree {
+		t.Fatalf("rule layout = %q, want worktree", layout)
+	}
+	if layout, _ := bulkCloneLayout(cfg, "acme/web", false); layout != config.LayoutRegular {
+		t.Fatalf("default layout = %q, want regular", layout)
+	}
+	if layout, _ := bulkCloneLayout(cfg, "acme/web", true); layout != config.LayoutWorktree {
+		t.Fatalf("--worktree layout = %q, want worktree", layout)
+	}
+	if _, err := bulkCloneLayout(cfg, "acme/broken", false); err == nil {
+		t.Fatal("expected error for unknown layout")
+	}
+}
+
+func TestCloneReposInParallelReportsFailuresWithoutAborting(t *testing.T) {
+	tmpDir := t.TempDir()
+	originsDir := filepath.Join(tmpDir, "origins")
+	cloneDir := filepath.Join(tmpDir, "src")
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	for _, name := range []string{"api", "mono"} {
+		originDir := filepath.Join(originsDir, name+".git")
+		seedDir := filepath.Join(tmpDir, "seed-"+name)
+		runGitCmd(t, "", "init", "--bare", originDir)
+		runGitCmd(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+		runGitCmd(t, "", "clone", originDir, seedDir)
+		runGitCmd(t, seedDir, "config", "user.email", "test@example.com")
+		runGitCmd(t, seedDir, "config", "user.name", "test")
+		if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGitCmd(t, seedDir, "add", "README.md")
+		runGitCmd(t, seedDir, "commit", "-m", "initial commit")
+		runGitCmd(t, seedDir, "push", "origin", "HEAD:main")
+	}
+
+	originalURL := repoCloneURL
+	originalZoxide := runZoxideAdd
+	t.Cleanup(func() {
+		repoCloneURL = originalURL
+		runZoxideAdd = originalZoxide
+	})
+	repoCloneURL = func(_ *config.Config, input string) (string, error) {
+		_, name, _ := strings.Cut(input, "/")
+		return filepath.Join(originsDir, name+".git"), nil
+	}
+	runZoxideAdd = func(path string) error { return nil }
+
+	cfg := &config.Config{Git: config.GitConfig{
+		CloneDir:    cloneDir,
+		LayoutRules: []config.LayoutRule{{Match: "acme/mono", Layout: "worktree"}, {Match: "acme/gone", Layout: "worktree"}},
+	}}
+	repos := []github.Repo{
+		{FullName: "acme/api", DefaultBranch: "main"},
+		{FullName: "acme/missing", DefaultBranch: "main"},
+		{FullName: "acme/mono", DefaultBranch: "main"},
+		{FullName: "acme/gone", DefaultBranch: "main"},
+	}
+
+	var progress bytes.Buffer
+	results := cloneReposInParallel(cfg, git.New(), repos, bulkCloneOptions{Jobs: 2, Progress: &progress})
+	if len(results) != 4 {
+		t.Fatalf("cloneReposInParallel() returned %d results, want 4", len(results))
+	}
+
+	got := make(map[string]string)
+	for _, result := range results {
+		got[result.FullName] = fmt.Sprintf("%s %v", result.Layout, result.Err != nil)
+	}
+	want := map[string]string{
+		"acme/api":     "regular false",
+		"acme/missing": "regular true",
+		"acme/mono":    "worktree false",
+		"acme/gone":    "worktree true",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+
+	if state, _ := detectExistingRepoState(filepath.Join(cloneDir, "acme", "api")); state != existingRepoRegular {
+		t.Fatalf("acme/api state = %v, want regular", state)
+	}
+	if state, _ := detectExistingRepoState(filepath.Join(cloneDir, "acme", "mono")); state != existingRepoWorktree {
+		t.Fatalf("acme/mono state = %v, want worktree", state)
+	}
+	for _, name := range []string{"missing", "gone"} {
+		if _, err := os.Lstat(filepath.Join(cloneDir, "acme", name)); !os.IsNotExist(err) {
+			t.Fatalf("failed clone of acme/%s left its destination behind: %v", name, err)
+		}
+	}
+	for _, wt := range []string{"main", "review"} {
+		if _, err := os.Stat(filepath.Join(cloneDir, "acme", "mono", wt, "README.md")); err != nil {
+			t.Fatalf("acme/mono %s worktree missing: %v", wt, err)
+		}
+	}
+
+	if lines := strings.Count(progress.String(), "\n"); lines != 4 || !strings.Contains(progress.String(), "✗ acme/missing") {
+		t.Fatalf("progress output:\n%s", progress.String())
+	}
+}
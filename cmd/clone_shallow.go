@@ -9,6 +9,7 @@ import (
 
 	"github.com/kirksw/ezgit/internal/cache"
 	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
 	"github.com/kirksw/ezgit/internal/github"
 )
 
@@ -23,26 +24,30 @@ func isInteractiveStdin() bool {
 	return (info.Mode() & os.ModeCharDevice) != 0
 }
 
-func resolveCloneDepthForLargeRepo(
+// resolveCloneOptionsForLargeRepo offers a shallow or partial clone when the
+// repo is larger than shallow_prompt_threshold_kb and returns the depth and
+// partial clone filter to use. An explicit depth or filter skips the prompt.
+func resolveCloneOptionsForLargeRepo(
 	cfg *config.Config,
 	repoInput string,
 	repoSizeHintKB int,
 	currentDepth int,
+	currentFilter string,
 	in io.Reader,
 	out io.Writer,
-) int {
-	if currentDepth > 0 {
-		return currentDepth
+) (int, string) {
+	if currentDepth > 0 || currentFilter != "" {
+		return currentDepth, currentFilter
 	}
 
 	thresholdKB := cfg.Git.ShallowPromptThresholdKB
 	if thresholdKB <= 0 {
-		return currentDepth
+		return currentDepth, currentFilter
 	}
 
 	repoFullName, ok := extractRepoFullName(repoInput)
 	if !ok {
-		return currentDepth
+		return currentDepth, currentFilter
 	}
 
 	repoSizeKB := repoSizeHintKB
@@ -59,15 +64,21 @@ func resolveCloneDepthForLargeRepo(
 	}
 
 	if repoSizeKB < thresholdKB {
-		return currentDepth
+		return currentDepth, currentFilter
 	}
 
-	useShallow, err := promptShallowCloneRecommendation(in, out, repoFullName, repoSizeKB, thresholdKB)
-	if err != nil || !useShallow {
-		return currentDepth
+	choice, err := promptLargeRepoCloneChoice(in, out, repoFullName, repoSizeKB, thresholdKB)
+	if err != nil {
+		return currentDepth, currentFilter
+	}
+	switch choice {
+	case largeRepoCloneShallow:
+		return recommendedShallowDepth, currentFilter
+	case largeRepoClonePartial:
+		return currentDepth, git.FilterBlobless
+	default:
+		return currentDepth, currentFilter
 	}
-
-	return recommendedShallowDepth
 }
 
 func lookupCachedRepoSizeKB(repoFullName string) int {
@@ -86,13 +97,21 @@ func lookupCachedRepoSizeKB(repoFullName string) int {
 	return 0
 }
 
-func promptShallowCloneRecommendation(
+type largeRepoCloneChoice int
+
+const (
+	largeRepoCloneFull largeRepoCloneChoice = iota
+	largeRepoCloneShallow
+	largeRepoClonePartial
+)
+
+func promptLargeRepoCloneChoice(
 	in io.Reader,
 	out io.Writer,
 	repoFullName string,
 	repoSizeKB int,
 	thresholdKB int,
-) (bool, error) {
+) (largeRepoCloneChoice, error) {
 	reader := bufio.NewReader(in)
 	repoSizeMB := float64(repoSizeKB) / 1024.0
 	thresholdMB := float64(thresholdKB) / 1024.0
@@ -100,31 +119,46 @@ func promptShallowCloneRecommendation(
 	for {
 		fmt.Fprintf(
 			out,
-			"The repo you are cloning (%s) is large (%.1f MB >= %.1f MB), so we recommend a shallow clone with --depth %d. Use shallow clone? [y/n]: ",
+			"The repo you are cloning (%s) is large (%.1f MB >= %.1f MB). Use a shallow clone (--depth %d), or a partial clone (--partial %s) that keeps full history and fetches file contents on demand? [y=shallow/p=partial/n]: ",
 			repoFullName,
 			repoSizeMB,
 			thresholdMB,
 			recommendedShallowDepth,
+			git.FilterBlobless,
 		)
 
 		input, err := reader.ReadString('\n')
-		if err != nil {
-			if err == io.EOF {
-				if answer, ok := parseYesNoRequired(input); ok {
-					return answer, nil
-				}
-			}
-			return false, err
+		if err != nil && (err != io.EOF || strings.TrimSpace(input) == "") {
+			return largeRepoCloneFull, err
 		}
 
-		if answer, ok := parseYesNoRequired(input); ok {
-			return answer, nil
+		if choice, ok := parseLargeRepoCloneChoice(input); ok {
+			return choice, nil
+		}
+		if err != nil {
+			return largeRepoCloneFull, err
 		}
 
-		fmt.Fprintln(out, "Please answer y or n.")
+		fmt.Fprintln(out, "Please answer y, p or n.")
 	}
 }
 
+func parseLargeRepoCloneChoice(input string) (largeRepoCloneChoice, bool) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "p", "partial":
+		return largeRepoClonePartial, true
+	case "s", "shallow":
+		return largeRepoCloneShallow, true
+	}
+	if yes, ok := parseYesNoRequired(input); ok {
+		if yes {
+			return largeRepoCloneShallow, true
+		}
+		return largeRepoCloneFull, true
+	}
+	return largeRepoCloneFull, false
+}
+
 func parseYesNoRequired(input string) (bool, bool) {
 	value := strings.ToLower(strings.TrimSpace(input))
 	switch value {
@@ -138,27 +172,9 @@ func parseYesNoRequired(input string) (bool, bool) {
 }
 
 func extractRepoFullName(input string) (string, bool) {
-	trimmed := strings.TrimSpace(input)
-	if trimmed == "" {
-		return "", false
-	}
-
-	switch {
-	case strings.HasPrefix(trimmed, "git@github.com:"):
-		trimmed = strings.TrimPrefix(trimmed, "git@github.com:")
-	case strings.HasPrefix(trimmed, "https://github.com/"):
-		trimmed = strings.TrimPrefix(trimmed, "https://github.com/")
-	case strings.HasPrefix(trimmed, "http://github.com/"):
-		trimmed = strings.TrimPrefix(trimmed, "http://github.com/")
-	}
-
-	trimmed = strings.TrimSuffix(trimmed, ".git")
-	trimmed = strings.TrimSuffix(trimmed, "/")
-
-	parts := strings.Split(trimmed, "/")
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+	ref, ok := git.ParseRepoRef(input)
+	if !ok {
 		return "", false
 	}
-
-	return parts[0] + "/" + parts[1], true
+	return ref.FullName, true
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
 	"github.com/kirksw/ezgit/internal/github"
 )
 
@@ -200,7 +201,7 @@ func TestSeedDefaultBranchLookupFromReposPreventsCacheReload(t *testing.T) {
 	}
 }
 
-func TestResolveCloneDepthForLargeRepo(t *testing.T) {
+func TestResolveCloneOptionsForLargeRepo(t *testing.T) {
 	cfg := &config.Config{
 		Git: config.GitConfig{
 			ShallowPromptThresholdKB: 1024,
@@ -209,50 +210,152 @@ func TestResolveCloneDepthForLargeRepo(t *testing.T) {
 
 	t.Run("uses shallow depth when user confirms", func(t *testing.T) {
 		var out bytes.Buffer
-		got := resolveCloneDepthForLargeRepo(
+		gotDepth, gotFilter := resolveCloneOptionsForLargeRepo(
 			cfg,
 			"facebook/react",
 			2048,
 			0,
+			"",
 			strings.NewReader("y\n"),
 			&out,
 		)
-		if got != recommendedShallowDepth {
-			t.Fatalf("resolveCloneDepthForLargeRepo() = %d, want %d", got, recommendedShallowDepth)
+		if gotDepth != recommendedShallowDepth || gotFilter != "" {
+			t.Fatalf("resolveCloneOptionsForLargeRepo() = (%d, %q), want (%d, \"\")", gotDepth, gotFilter, recommendedShallowDepth)
+		}
+	})
+
+	t.Run("uses blobless partial clone when user picks partial", func(t *testing.T) {
+		var out bytes.Buffer
+		gotDepth, gotFilter := resolveCloneOptionsForLargeRepo(
+			cfg,
+			"facebook/react",
+			2048,
+			0,
+			"",
+			strings.NewReader("maybe\np\n"),
+			&out,
+		)
+		if gotDepth != 0 || gotFilter != git.FilterBlobless {
+			t.Fatalf("resolveCloneOptionsForLargeRepo() = (%d, %q), want (0, %q)", gotDepth, gotFilter, git.FilterBlobless)
+		}
+		if !strings.Contains(out.String(), "Please answer y, p or n.") {
+			t.Fatalf("prompt output = %q, want retry message", out.String())
+		}
+	})
+
+	t.Run("keeps full clone when user declines", func(t *testing.T) {
+		var out bytes.Buffer
+		gotDepth, gotFilter := resolveCloneOptionsForLargeRepo(
+			cfg,
+			"facebook/react",
+			2048,
+			0,
+			"",
+			strings.NewReader("n"),
+			&out,
+		)
+		if gotDepth != 0 || gotFilter != "" {
+			t.Fatalf("resolveCloneOptionsForLargeRepo() = (%d, %q), want full clone", gotDepth, gotFilter)
 		}
 	})
 
 	t.Run("keeps full clone when below threshold", func(t *testing.T) {
 		var out bytes.Buffer
-		got := resolveCloneDepthForLargeRepo(
+		gotDepth, gotFilter := resolveCloneOptionsForLargeRepo(
 			cfg,
 			"facebook/react",
 			512,
 			0,
+			"",
 			strings.NewReader("y\n"),
 			&out,
 		)
-		if got != 0 {
-			t.Fatalf("resolveCloneDepthForLargeRepo() = %d, want 0", got)
+		if gotDepth != 0 || gotFilter != "" {
+			t.Fatalf("resolveCloneOptionsForLargeRepo() = (%d, %q), want (0, \"\")", gotDepth, gotFilter)
 		}
 	})
 
 	t.Run("keeps explicit depth", func(t *testing.T) {
 		var out bytes.Buffer
-		got := resolveCloneDepthForLargeRepo(
+		gotDepth, _ := resolveCloneOptionsForLargeRepo(
 			cfg,
 			"facebook/react",
 			2048,
 			3,
+			"",
+			strings.NewReader("y\n"),
+			&out,
+		)
+		if gotDepth != 3 {
+			t.Fatalf("resolveCloneOptionsForLargeRepo() depth = %d, want 3", gotDepth)
+		}
+	})
+
+	t.Run("keeps configured filter without prompting", func(t *testing.T) {
+		var out bytes.Buffer
+		gotDepth, gotFilter := resolveCloneOptionsForLargeRepo(
+			cfg,
+			"facebook/react",
+			2048,
+			0,
+			git.FilterTreeless,
 			strings.NewReader("y\n"),
 			&out,
 		)
-		if got != 3 {
-			t.Fatalf("resolveCloneDepthForLargeRepo() = %d, want 3", got)
+		if gotDepth != 0 || gotFilter != git.FilterTreeless || out.Len() != 0 {
+			t.Fatalf("resolveCloneOptionsForLargeRepo() = (%d, %q), output %q", gotDepth, gotFilter, out.String())
 		}
 	})
 }
 
+func TestResolvePartialCloneOptions(t *testing.T) {
+	cfg := &config.Config{Git: config.GitConfig{CloneRules: []config.CloneRule{
+		{Match: "acme/mono", Filter: "blob:none", Sparse: []string{"services/api/", " libs "}},
+	}}}
+
+	filter, sparse, err := resolvePartialCloneOptions(cfg, "acme/mono", "", nil)
+	if err != nil || filter != git.FilterBlobless || len(sparse) != 2 || sparse[0] != "services/api" || sparse[1] != "libs" {
+		t.Fatalf("resolvePartialCloneOptions(rule) = %q, %v, %v", filter, sparse, err)
+	}
+
+	filter, sparse, err = resolvePartialCloneOptions(cfg, "acme/mono", "tree:0", []string{"docs"})
+	if err != nil || filter != git.FilterTreeless || len(sparse) != 1 || sparse[0] != "docs" {
+		t.Fatalf("resolvePartialCloneOptions(flags) = %q, %v, %v", filter, sparse, err)
+	}
+
+	if filter, sparse, err = resolvePartialCloneOptions(cfg, "acme/web", "", nil); err != nil || filter != "" || sparse != nil {
+		t.Fatalf("resolvePartialCloneOptions(no rule) = %q, %v, %v", filter, sparse, err)
+	}
+
+	if _, _, err := resolvePartialCloneOptions(cfg, "acme/web", "blob:limit=1m", nil); err == nil {
+		t.Fatal("resolvePartialCloneOptions() expected error for unsupported filter")
+	}
+}
+
+func TestResolveCheckoutExtras(t *testing.T) {
+	cfg := &config.Config{Git: config.GitConfig{CloneRules: []config.CloneRule{
+		{Match: "acme/firmware", Submodules: "Shallow", LFS: "skip"},
+	}}}
+
+	extras, err := resolveCheckoutExtras(cfg, "acme/firmware", git.CheckoutExtras{})
+	if err != nil || extras != (git.CheckoutExtras{Submodules: git.SubmodulesShallow, LFS: git.LFSSkip}) {
+		t.Fatalf("resolveCheckoutExtras(rule) = %+v, %v", extras, err)
+	}
+
+	extras, err = resolveCheckoutExtras(cfg, "acme/firmware", git.CheckoutExtras{LFS: "pull"})
+	if err != nil || extras != (git.CheckoutExtras{Submodules: git.SubmodulesShallow, LFS: git.LFSPull}) {
+		t.Fatalf("resolveCheckoutExtras(flag) = %+v, %v", extras, err)
+	}
+
+	if extras, err = resolveCheckoutExtras(cfg, "acme/web", git.CheckoutExtras{}); err != nil || extras != (git.CheckoutExtras{}) {
+		t.Fatalf("resolveCheckoutExtras(no rule) = %+v, %v", extras, err)
+	}
+
+	if _, err := resolveCheckoutExtras(cfg, "acme/web", git.CheckoutExtras{Submodules: "all"}); err == nil {
+		t.Fatal("resolveCheckoutExtras() expected error for unsupported submodules mode")
+	}
+}
+
 func TestResolveClonePathsWorktree(t *testing.T) {
 	cloneTarget, metadataPath := resolveClonePaths("/tmp/example", true)
 	want := "/tmp/example/.git"
@@ -333,6 +436,115 @@ func TestResolveRepoPathsUsesCloneDir(t *testing.T) {
 	}
 }
 
+func TestCloneSSHKeyPrefersFlagThenConfig(t *testing.T) {
+	cfg := &config.Config{Git: config.GitConfig{SSHKeys: []config.SSHKeyRule{
+		{Match: "acme/*", Key: "/keys/acme"},
+	}}}
+
+	tests := []struct {
+		name     string
+		explicit string
+		url      string
+		repo     string
+		want     string
+	}{
+		{name: "flag wins", explicit: "/keys/flag", url: "git@github.com:acme/api.git", repo: "acme/api", want: "/keys/flag"},
+		{name: "config rule", url: "git@github.com:acme/api.git", repo: "acme/api", want: "/keys/acme"},
+		{name: "no rule leaves ssh to choose", url: "git@github.com:other/tool.git", repo: "other/tool", want: ""},
+		{name: "https never pins a key", url: "https://github.com/acme/api.git", repo: "acme/api", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cloneSSHKey(cfg, tt.explicit, tt.url, tt.repo); got != tt.want {
+				t.Fatalf("cloneSSHKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSSHKeySkipsValidationWithoutPinnedKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SSH_AUTH_SOCK", "")
+	key, err := resolveSSHKey(&config.Config{}, git.New(), "", "git@github.com:acme/api.git", "acme/api")
+	if err != nil || key != "" {
+		t.Fatalf("resolveSSHKey() = %q, %v; want no key and no error", key, err)
+	}
+
+	if _, err := resolveSSHKey(&config.Config{}, git.New(), "/missing/key", "git@github.com:acme/api.git", "acme/api"); err == nil {
+		t.Fatal("resolveSSHKey() accepted a missing explicit key")
+	}
+}
+
+func TestResolveCloneURLUsesConfiguredProtocol(t *testing.T) {
+	originalProbe := probeSSHAccess
+	t.Cleanup(func() {
+		probeSSHAccess = originalProbe
+		autoProtocolByHost = make(map[string]string)
+	})
+	probes := 0
+	probeSSHAccess = func(host string) error {
+		probes++
+		return fmt.Errorf("blocked")
+	}
+
+	cfg := &config.Config{Git: config.GitConfig{
+		Protocol: "auto",
+		ProtocolRules: []config.ProtocolRule{
+			{Match: "acme/*", Protocol: "https"},
+			{Match: "corp/*", Protocol: "ssh"},
+			{Match: "bad/*", Protocol: "ftp"},
+			{Host: "github.example.com", Protocol: "https"},
+		},
+	}}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "acme/api", want: "https://github.com/acme/api.git"},
+		{input: "corp/tool", want: "git@github.com:corp/tool.git"},
+		{input: "other/repo", want: "https://github.com/other/repo.git"},
+		{input: "other/again", want: "https://github.com/other/again.git"},
+		{input: "git@github.com:acme/api", want: "https://github.com/acme/api.git"},
+		{input: "github.example.com/team/tool", want: "https://github.example.com/team/tool.git"},
+		{input: "git@github.example.com:team/app.git", want: "https://github.example.com/team/app.git"},
+	}
+	for _, tt := range tests {
+		got, err := resolveCloneURL(cfg, tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveCloneURL(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+	if probes != 1 {
+		t.Fatalf("ssh probes = %d, want 1 (cached per host)", probes)
+	}
+	if _, err := resolveCloneURL(cfg, "bad/repo"); err == nil {
+		t.Fatal("resolveCloneURL() accepted an unknown protocol")
+	}
+
+	for _, input := range []string{"git@github.com:acme/api.git", "https://git.example.com:8443/acme/api.git"} {
+		if got, err := resolveCloneURL(&config.Config{}, input); err != nil || got != input {
+			t.Errorf("resolveCloneURL(%q) without a configured protocol = %q, %v; want it unchanged", input, got, err)
+		}
+	}
+}
+
+func TestCloneConfigSetsCredentialHelperForHTTPS(t *testing.T) {
+	cfg := &config.Config{Git: config.GitConfig{Proxy: "http://proxy.internal:3128"}}
+
+	got := cloneConfig(cfg, "https://github.com/acme/api.git")
+	if len(got) != 2 || !strings.HasPrefix(got[0], "credential.https://github.com.helper=!") || !strings.HasSuffix(got[0], " credential") ||
+		got[1] != "http.proxy=http://proxy.internal:3128" {
+		t.Fatalf("cloneConfig() = %q", got)
+	}
+	if got := cloneConfig(cfg, "https://gitlab.com/acme/api.git"); len(got) != 1 || got[0] != "http.proxy=http://proxy.internal:3128" {
+		t.Fatalf("cloneConfig(gitlab.com) = %q, want only the proxy", got)
+	}
+	if got := cloneConfig(cfg, "git@github.com:acme/api.git"); got != nil {
+		t.Fatalf("cloneConfig(ssh) = %q, want nil", got)
+	}
+}
+
 func TestAddWorktreeToRepoRejectsEmptyName(t *testing.T) {
 	err := addWorktreeToRepo(nil, "lunarway/hubble-cli", "/tmp/repo", "/tmp/repo/.git", "   ")
 	if err == nil {
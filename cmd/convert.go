@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/kirksw/ezgit/internal/config"
 	"github.com/kirksw/ezgit/internal/git"
 	"github.com/kirksw/ezgit/internal/ui"
 	"github.com/kirksw/ezgit/internal/utils"
@@ -14,8 +15,15 @@ import (
 var convertCmd = &cobra.Command{
 	Use:   "convert <path>",
 	Short: "Convert an existing repository to bare with worktrees",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runConvert,
+	Long: `Convert an existing repository to bare metadata in .git plus worktrees.
+
+The existing .git directory is kept, so stashes, hooks and local config carry
+over, and the working tree files (including uncommitted, untracked and ignored
+ones) move into a worktree for the checked-out branch. Conversion is refused
+when HEAD is detached, an operation such as a merge or rebase is in progress,
+or the repository has submodules.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvert,
 }
 
 var (
@@ -31,15 +39,19 @@ func init() {
 	convertCmd.Flags().StringSliceVarP(&worktrees, "worktree", "w", []string{}, "create worktree for specific branch")
 	convertCmd.Flags().BoolVar(&allWorktrees, "all-worktrees", false, "create worktree for all branches")
 	convertCmd.Flags().BoolVar(&noWorktrees, "no-worktrees", false, "skip worktree creation")
-	convertCmd.Flags().StringVar(&convertKeyPath, "key-path", "", "SSH key path")
+	convertCmd.Flags().StringVar(&convertKeyPath, "key-path", "", "SSH key to pin in the converted repo's core.sshCommand")
 }
 
 func runConvert(cmd *cobra.Command, args []string) error {
-	return runConvertPath(args[0], "")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return runConvertPath(cfg, args[0], "")
 }
 
-func runConvertPath(repoPath, repoDefaultBranch string) error {
-	gitMgr := git.New()
+func runConvertPath(cfg *config.Config, repoPath, repoDefaultBranch string) error {
+	gitMgr := newGitManager(cfg)
 
 	if err := utils.ValidatePath(repoPath); err != nil {
 		return fmt.Errorf("invalid path: %w", err)
@@ -51,6 +63,19 @@ func runConvertPath(repoPath, repoDefaultBranch string) error {
 	}
 	repoPath = absRepoPath
 
+	plan, err := gitMgr.PlanConvertToBare(repoPath)
+	if err != nil {
+		return fmt.Errorf("cannot convert %s: %w", repoPath, err)
+	}
+	if convertKeyPath != "" {
+		if err := gitMgr.ValidateSSHKey(convertKeyPath); err != nil {
+			return fmt.Errorf("SSH key validation failed: %w", err)
+		}
+	}
+	if dryRun {
+		return printConvertPlan(gitMgr, repoPath, repoDefaultBranch, plan)
+	}
+
 	createDefaultWorktree := true
 	createReviewWorktree := true
 	customWorktrees := make([]ui.CloneWorktreeSpec, 0)
@@ -86,14 +111,21 @@ func runConvertPath(repoPath, repoDefaultBranch string) error {
 	}
 
 	fmt.Println("✓ Successfully converted to bare repository")
+	fmt.Printf("✓ Working tree moved to %s\n", plan.WorktreePath)
+
+	bareMetadataPath := filepath.Join(repoPath, ".git")
+	if convertKeyPath != "" {
+		if err := gitMgr.ConfigureSSHKey(bareMetadataPath, convertKeyPath); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Pinned SSH key %s\n", convertKeyPath)
+	}
 
 	if noWorktrees {
 		fmt.Println("Worktree creation skipped (--no-worktrees)")
 		return nil
 	}
 
-	bareMetadataPath := filepath.Join(repoPath, ".git")
-
 	branches, err := gitMgr.ListBranches(bareMetadataPath)
 	if err != nil {
 		return fmt.Errorf("failed to list branches: %w", err)
@@ -129,6 +161,51 @@ func runConvertPath(repoPath, repoDefaultBranch string) error {
 	return nil
 }
 
+// printConvertPlan lists what runConvertPath would do without the interactive
+// worktree prompt, which would otherwise decide the extra worktrees.
+func printConvertPlan(gitMgr git.GitManager, repoPath, repoDefaultBranch string, plan git.ConvertPlan) error {
+	fmt.Printf("Would convert %s to a bare repository:\n", repoPath)
+	fmt.Printf("  keep %s (config, hooks, %d stash(es))\n", filepath.Join(repoPath, ".git"), plan.Stashes)
+	fmt.Printf("  add worktree %s for branch %s\n", plan.WorktreePath, plan.Branch)
+	for _, entry := range plan.Entries {
+		fmt.Printf("  move %s -> %s\n", entry, filepath.Join(plan.WorktreePath, entry))
+	}
+	if plan.Uncommitted {
+		fmt.Println("  uncommitted changes move with the files")
+	}
+	if convertKeyPath != "" {
+		fmt.Printf("  set core.sshCommand to %s\n", git.SSHCommand(convertKeyPath))
+	}
+
+	if noWorktrees {
+		return nil
+	}
+	branches, err := gitMgr.ListBranches(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to list branches: %w", err)
+	}
+	var extra []string
+	switch {
+	case allWorktrees:
+		extra = branches
+	case len(worktrees) > 0:
+		if err := utils.ValidateBranches(branches, worktrees); err != nil {
+			return fmt.Errorf("branch validation failed: %w", err)
+		}
+		extra = worktrees
+	default:
+		extra = []string{resolveConvertDefaultBranch(repoDefaultBranch, branches), "review"}
+	}
+	for _, name := range extra {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "origin/"))
+		if name == plan.Branch {
+			continue
+		}
+		fmt.Printf("  add worktree %s\n", utils.ParseWorktreePath(repoPath, name))
+	}
+	return nil
+}
+
 func createAllConvertWorktrees(gitMgr git.GitManager, bareMetadataPath, repoPath string, branches []string) error {
 	fmt.Printf("Creating worktrees for all %d branches...\n", len(branches))
 	for _, branch := range branches {
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
 )
 
 func runGitCmd(t *testing.T, dir string, args ...string) string {
@@ -108,3 +110,29 @@ func TestResolveConvertDefaultBranchUsesExplicitWhenPresent(t *testing.T) {
 		t.Fatalf("got %q, want %q", got, "trunk")
 	}
 }
+
+func TestConvertDryRunLeavesRepositoryUntouched(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "repo")
+	runGitCmd(t, "", "init", "-b", "main", repoDir)
+	runGitCmd(t, repoDir, "config", "user.email", "test@example.com")
+	runGitCmd(t, repoDir, "config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("failed to write README.md: %v", err)
+	}
+	runGitCmd(t, repoDir, "add", "README.md")
+	runGitCmd(t, repoDir, "commit", "-m", "initial")
+
+	oldDryRun := dryRun
+	t.Cleanup(func() { dryRun = oldDryRun })
+	dryRun = true
+
+	if err := runConvertPath(&config.Config{}, repoDir, "main"); err != nil {
+		t.Fatalf("runConvertPath() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "README.md")); err != nil {
+		t.Fatalf("dry run moved README.md: %v", err)
+	}
+	if got := strings.TrimSpace(runGitCmd(t, repoDir, "rev-parse", "--is-bare-repository")); got != "false" {
+		t.Fatalf("is-bare-repository = %q, want false", got)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// credentialHelperEnv is set while ezgit answers a credential request, so a
+// git-credential token source that calls back into git does not recurse.
+const credentialHelperEnv = "EZGIT_CREDENTIAL_HELPER"
+
+var credentialCmd = &cobra.Command{
+	Use:   "credential <get|store|erase>",
+	Short: "Git credential helper that answers with the resolved GitHub token",
+	Long: `Git credential helper for HTTPS clones made by ezgit. 'get' answers with the
+token resolved from [github] token_sources for the requested host; 'store' and
+'erase' are ignored because the token is never saved.`,
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE:   runCredential,
+}
+
+func init() {
+	rootCmd.AddCommand(credentialCmd)
+}
+
+func runCredential(cmd *cobra.Command, args []string) error {
+	if args[0] != "get" || os.Getenv(credentialHelperEnv) != "" {
+		return nil
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	os.Setenv(credentialHelperEnv, "1")
+	return writeCredential(cfg, os.Stdin, os.Stdout)
+}
+
+// writeCredential answers a git credential request read from in. Requests
+// for other protocols, hosts that are not GitHub hosts, or hosts without a
+// token get no answer so git moves on to its next helper or prompt.
+func writeCredential(cfg *config.Config, in io.Reader, out io.Writer) error {
+	request := make(map[string]string)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			request[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read credential request: %w", err)
+	}
+	if request["protocol"] != "https" || !cfg.IsGitHubHost(request["host"]) {
+		return nil
+	}
+
+	resolution, err := cfg.ResolveGitHubToken(request["host"])
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "ezgit credential: %v\n", err)
+		}
+		return nil
+	}
+	_, err = fmt.Fprintf(out, "username=x-access-token\npassword=%s\n", resolution.Token)
+	return err
+}
+
+// credentialHelperCommand is the credential.helper value that runs ezgit: by
+// name when it is on PATH, so upgrades that move the binary keep working,
+// else by absolute path.
+func credentialHelperCommand() string {
+	if _, err := exec.LookPath("ezgit"); err == nil {
+		return "!ezgit credential"
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "!ezgit credential"
+	}
+	return "!" + utils.ShellQuote(exe) + " credential"
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+)
+
+func TestWriteCredentialAnswersHTTPSRequests(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{
+		Token:        "gh-token",
+		TokenSources: []string{config.TokenSourceConfig},
+	}}
+
+	var out bytes.Buffer
+	if err := writeCredential(cfg, strings.NewReader("protocol=https\nhost=github.com\n\n"), &out); err != nil {
+		t.Fatalf("writeCredential() error = %v", err)
+	}
+	if want := "username=x-access-token\npassword=gh-token\n"; out.String() != want {
+		t.Fatalf("credential = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := writeCredential(cfg, strings.NewReader("protocol=ssh\nhost=github.com\n"), &out); err != nil {
+		t.Fatalf("writeCredential() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("credential for ssh = %q, want none", out.String())
+	}
+
+	out.Reset()
+	if err := writeCredential(cfg, strings.NewReader("protocol=https\nhost=gitlab.com\n"), &out); err != nil {
+		t.Fatalf("writeCredential() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("credential for gitlab.com = %q, want none", out.String())
+	}
+
+	out.Reset()
+	if err := writeCredential(&config.Config{GitHub: config.GitHubConfig{TokenSources: []string{config.TokenSourceConfig}}}, strings.NewReader("protocol=https\nhost=github.com\n"), &out); err != nil {
+		t.Fatalf("writeCredential() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("credential without token = %q, want none", out.String())
+	}
+}
@@ -19,14 +19,39 @@ var describeCmd = &cobra.Command{
 }
 
 type repoDescription struct {
-	FullName      string   `json:"full_name"`
-	DefaultBranch string   `json:"default_branch"`
-	Path          string   `json:"path"`
-	MetadataPath  string   `json:"metadata_path"`
-	Cloned        bool     `json:"cloned"`
-	Layout        string   `json:"layout"`
-	Worktree      bool     `json:"worktree"`
-	Worktrees     []string `json:"worktrees"`
+	FullName       string             `json:"full_name"`
+	DefaultBranch  string             `json:"default_branch"`
+	Path           string             `json:"path"`
+	MetadataPath   string             `json:"metadata_path"`
+	Cloned         bool               `json:"cloned"`
+	Layout         string             `json:"layout"`
+	Worktree       bool               `json:"worktree"`
+	Shallow        bool               `json:"shallow"`
+	Depth          int                `json:"depth,omitempty"`
+	Worktrees      []string           `json:"worktrees"`
+	WorktreeStatus []git.WorktreeInfo `json:"worktree_status,omitempty"`
+	Submodules     *git.SubmoduleInfo `json:"submodules,omitempty"`
+	LFS            *git.LFSInfo       `json:"lfs,omitempty"`
+}
+
+// shallowInfoReader is implemented by listers that can report clone depth;
+// describe includes it when available.
+type shallowInfoReader interface {
+	ShallowInfo(path string) (git.ShallowInfo, error)
+}
+
+// worktreeInfoLister is implemented by listers that can also report per-worktree
+// status; describe includes it when available.
+type worktreeInfoLister interface {
+	ListWorktreeInfo(path string) ([]git.WorktreeInfo, error)
+}
+
+// checkoutStatusReader is implemented by listers that can report submodule
+// and LFS state; describe includes it when available.
+type checkoutStatusReader interface {
+	RepoCheckoutExtras(path string) (git.CheckoutExtras, error)
+	SubmoduleStatus(worktreePath string) (git.SubmoduleInfo, error)
+	LFSStatus(worktreePath string) (git.LFSInfo, error)
 }
 
 func init() {
@@ -39,7 +64,7 @@ func runDescribe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	desc, err := describeRepo(cfg, args[0], git.New())
+	desc, err := describeRepo(cfg, args[0], newGitManager(cfg))
 	if err != nil {
 		return err
 	}
@@ -76,16 +101,84 @@ func describeRepo(cfg *config.Config, repoInput string, lister repoWorktreeListe
 	desc.Layout = repoLayout(state)
 	desc.Cloned = state == existingRepoRegular || state == existingRepoWorktree
 	desc.Worktree = state == existingRepoWorktree
+	if reader, ok := lister.(shallowInfoReader); ok && desc.Cloned {
+		metadataPath := repoPath
+		if desc.Worktree {
+			metadataPath = desc.MetadataPath
+		}
+		info, err := reader.ShallowInfo(metadataPath)
+		if err != nil {
+			return desc, err
+		}
+		desc.Shallow = info.Shallow
+		desc.Depth = info.Depth
+	}
 	if state == existingRepoWorktree && lister != nil {
 		worktrees, err := lister.ListWorktrees(repoPath)
 		if err != nil {
 			return desc, fmt.Errorf("failed to list worktrees: %w", err)
 		}
 		desc.Worktrees = sortedStrings(worktrees)
+
+		if infoLister, ok := lister.(worktreeInfoLister); ok {
+			infos, err := infoLister.ListWorktreeInfo(repoPath)
+			if err != nil {
+				return desc, fmt.Errorf("failed to read worktree status: %w", err)
+			}
+			sortWorktreeInfos(infos)
+			desc.WorktreeStatus = infos
+		}
+	}
+	if reader, ok := lister.(checkoutStatusReader); ok && desc.Cloned {
+		if err := describeCheckouts(&desc, reader); err != nil {
+			return desc, err
+		}
 	}
 	return desc, nil
 }
 
+// describeCheckouts sums submodule and LFS state over the repo's checkouts:
+// the clone itself, or every worktree of the worktree layout.
+func describeCheckouts(desc *repoDescription, reader checkoutStatusReader) error {
+	metadataPath := desc.Path
+	checkouts := []string{desc.Path}
+	if desc.Worktree {
+		metadataPath = desc.MetadataPath
+		checkouts = nil
+		for _, name := range desc.Worktrees {
+			checkouts = append(checkouts, filepath.Join(desc.Path, name))
+		}
+	}
+
+	extras, err := reader.RepoCheckoutExtras(metadataPath)
+	if err != nil {
+		return err
+	}
+	submodules := git.SubmoduleInfo{Mode: extras.Submodules}
+	lfs := git.LFSInfo{Mode: extras.LFS}
+	for _, checkout := range checkouts {
+		sub, err := reader.SubmoduleStatus(checkout)
+		if err != nil {
+			return err
+		}
+		submodules.Total += sub.Total
+		submodules.Initialized += sub.Initialized
+		submodules.OutOfDate += sub.OutOfDate
+
+		info, err := reader.LFSStatus(checkout)
+		if err != nil {
+			return err
+		}
+		lfs.Tracked = lfs.Tracked || info.Tracked
+		lfs.Installed = lfs.Installed || info.Installed
+		lfs.Files += info.Files
+		lfs.Pointers += info.Pointers
+	}
+	desc.Submodules = &submodules
+	desc.LFS = &lfs
+	return nil
+}
+
 func repoLayout(state existingRepoState) string {
 	switch state {
 	case existingRepoMissing:
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
 )
 
 func TestRepoLayout(t *testing.T) {
@@ -37,3 +38,19 @@ func TestDescribeRepoMissing(t *testing.T) {
 		t.Fatalf("Path = %q, want %q", desc.Path, wantPath)
 	}
 }
+
+func TestDescribeRepoReportsSubmodulesAndLFS(t *testing.T) {
+	cfg, repoDir, _ := setupWorktreeLayoutFixture(t)
+	runGitCmd(t, filepath.Join(repoDir, ".git"), "config", "ezgit.lfs", git.LFSSkip)
+
+	desc, err := describeRepo(cfg, "acme/widgets", git.New())
+	if err != nil {
+		t.Fatalf("describeRepo() error = %v", err)
+	}
+	if desc.Submodules == nil || *desc.Submodules != (git.SubmoduleInfo{}) {
+		t.Fatalf("Submodules = %+v, want none", desc.Submodules)
+	}
+	if desc.LFS == nil || desc.LFS.Mode != git.LFSSkip || desc.LFS.Tracked {
+		t.Fatalf("LFS = %+v, want skip mode with nothing tracked", desc.LFS)
+	}
+}
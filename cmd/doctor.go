@@ -0,0 +1,593 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment ezgit depends on",
+	Long: `Check the environment ezgit depends on: the git version, the tmux, sesh,
+zoxide and gh tools, GitHub token validity and scopes, SSH and HTTPS access
+to each configured host and the protocol auto picks there, the config file,
+the repository cache and clone_dir.
+
+Every problem comes with a suggested fix. Exits non-zero when a check fails;
+warnings only mean a feature is degraded.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+var doctorJSON bool
+
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// minGitMajor and minGitMinor are the oldest git that has every worktree
+// and sparse-checkout feature ezgit uses ('sparse-checkout set --cone').
+const (
+	minGitMajor = 2
+	minGitMinor = 25
+)
+
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// doctorEnv is everything the checks probe outside the config, so tests can
+// describe a machine without installing tools or reaching GitHub.
+type doctorEnv struct {
+	lookPath     func(file string) (string, error)
+	gitVersion   func() (string, error)
+	inspectToken func(host, token string) (*github.TokenInfo, error)
+	discoverSSH  func(host string) (git.SSHIdentity, bool)
+	probeSSH     func(host, keyPath string) error
+	probeHTTPS   func(repoURL string, config []string) error
+	cache        *cache.OrgCache
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "print the checks as JSON")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	env := doctorEnv{
+		lookPath:     exec.LookPath,
+		gitVersion:   installedGitVersion,
+		inspectToken: inspectGitHubToken,
+		discoverSSH:  git.DiscoverSSHIdentity,
+		probeSSH:     git.ProbeSSHKey,
+		probeHTTPS:   git.ProbeHTTPS,
+		cache:        cache.New(),
+	}
+
+	cfg, checks := doctorConfig(configPath)
+	checks = append(checks, runDoctorChecks(cfg, env)...)
+
+	if doctorJSON {
+		data, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode checks: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		writeDoctorChecks(os.Stdout, checks)
+	}
+
+	if failed := countDoctorChecks(checks, doctorFail); failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// doctorConfig loads the config like every other command, but reports a
+// missing or broken file as a check instead of stopping there.
+func doctorConfig(path string) (*config.Config, []doctorCheck) {
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return &config.Config{}, []doctorCheck{{
+				Name: "config", Status: doctorFail,
+				Detail: fmt.Sprintf("%s: %v", path, err),
+				Fix:    "pass an existing file to --config",
+			}}
+		}
+	}
+
+	found, err := config.FindConfigPath(path)
+	if err != nil {
+		return &config.Config{}, []doctorCheck{{
+			Name: "config", Status: doctorWarn,
+			Detail: "no config file found; using defaults",
+			Fix:    "create ~/.config/ezgit/config.toml (see the Config section of the README)",
+		}}
+	}
+
+	cfg, err := config.LoadFile(found)
+	if err != nil {
+		return &config.Config{}, []doctorCheck{{
+			Name: "config", Status: doctorFail,
+			Detail: fmt.Sprintf("%s: %v", found, err),
+			Fix:    "fix the TOML syntax in " + found,
+		}}
+	}
+
+	problems := cfg.Validate()
+	switch strings.TrimSpace(cfg.Git.Backend) {
+	case "", git.BackendExec, git.BackendNative:
+	default:
+		problems = append(problems, fmt.Errorf("[git].backend: unknown backend %q (use %s or %s)", cfg.Git.Backend, git.BackendExec, git.BackendNative))
+	}
+	for i, rule := range cfg.Git.CloneRules {
+		if err := git.ValidateCloneFilter(rule.Filter); err != nil {
+			problems = append(problems, fmt.Errorf("[git].clone_rules[%d]: %w", i, err))
+		}
+		extras := git.CheckoutExtras{Submodules: strings.ToLower(strings.TrimSpace(rule.Submodules)), LFS: strings.ToLower(strings.TrimSpace(rule.LFS))}
+		if err := git.ValidateCheckoutExtras(extras); err != nil {
+			problems = append(problems, fmt.Errorf("[git].clone_rules[%d]: %w", i, err))
+		}
+	}
+	if len(problems) == 0 {
+		return cfg, []doctorCheck{{Name: "config", Status: doctorOK, Detail: found}}
+	}
+
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = problem.Error()
+	}
+	return cfg, []doctorCheck{{
+		Name: "config", Status: doctorFail,
+		Detail: found + ": " + strings.Join(messages, "; "),
+		Fix:    "correct the listed settings in " + found,
+	}}
+}
+
+func runDoctorChecks(cfg *config.Config, env doctorEnv) []doctorCheck {
+	checks := []doctorCheck{doctorGitCheck(env)}
+	checks = append(checks, doctorCloneDirCheck(cfg))
+	checks = append(checks, doctorToolChecks(cfg, env)...)
+	checks = append(checks, doctorTokenChecks(cfg, env)...)
+	sshChecks := doctorSSHChecks(cfg, env)
+	checks = append(checks, sshChecks...)
+	checks = append(checks, doctorHTTPSChecks(cfg, env, sshChecks)...)
+	checks = append(checks, doctorCacheChecks(cfg, env.cache)...)
+	return checks
+}
+
+func doctorGitCheck(env doctorEnv) doctorCheck {
+	check := doctorCheck{Name: "git"}
+	output, err := env.gitVersion()
+	if err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("git not usable: %v", err)
+		check.Fix = fmt.Sprintf("install git %d.%d or newer", minGitMajor, minGitMinor)
+		return check
+	}
+
+	check.Detail = output
+	major, minor, ok := parseGitVersion(output)
+	switch {
+	case !ok:
+		check.Status = doctorWarn
+		check.Fix = fmt.Sprintf("could not parse the version; ezgit needs git %d.%d or newer", minGitMajor, minGitMinor)
+	case major < minGitMajor || (major == minGitMajor && minor < minGitMinor):
+		check.Status = doctorFail
+		check.Detail += fmt.Sprintf(" (worktree and sparse-checkout support needs %d.%d)", minGitMajor, minGitMinor)
+		check.Fix = fmt.Sprintf("upgrade git to %d.%d or newer", minGitMajor, minGitMinor)
+	default:
+		check.Status = doctorOK
+	}
+	return check
+}
+
+func installedGitVersion() (string, error) {
+	output, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseGitVersion reads major and minor from `git --version` output such as
+// "git version 2.39.3 (Apple Git-146)".
+func parseGitVersion(output string) (major, minor int, ok bool) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return 0, 0, false
+	}
+	if _, err := fmt.Sscanf(fields[2], "%d.%d", &major, &minor); err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+func doctorCloneDirCheck(cfg *config.Config) doctorCheck {
+	check := doctorCheck{Name: "clone_dir"}
+	dir := cfg.GetCloneDir()
+	if strings.TrimSpace(dir) == "" {
+		check.Status = doctorFail
+		check.Detail = "[git].clone_dir is not set; 'ezgit open' and the picker cannot find clones"
+		check.Fix = `set clone_dir under [git] in the config, e.g. clone_dir = "~/src"`
+		return check
+	}
+
+	check.Detail = dir
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		check.Status = doctorWarn
+		check.Detail += " does not exist yet"
+		check.Fix = "mkdir -p " + dir
+		return check
+	case err != nil:
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		check.Fix = "make " + dir + " readable or choose another clone_dir"
+		return check
+	case !info.IsDir():
+		check.Status = doctorFail
+		check.Detail += " is not a directory"
+		check.Fix = "point [git].clone_dir at a directory"
+		return check
+	}
+
+	if err := checkDirWritable(dir); err != nil {
+		check.Status = doctorFail
+		check.Detail += " is not writable: " + err.Error()
+		check.Fix = "chmod u+w " + dir + " or choose another clone_dir"
+		return check
+	}
+	check.Status = doctorOK
+	return check
+}
+
+func checkDirWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".ezgit-doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// doctorToolChecks covers the optional tools. A missing one only degrades
+// the features that use it, so it is a warning.
+func doctorToolChecks(cfg *config.Config, env doctorEnv) []doctorCheck {
+	type tool struct {
+		name   string
+		needed bool
+		usedBy string
+		fix    string
+	}
+
+	openCommand := cfg.Git.OpenCommand
+	if strings.TrimSpace(openCommand) == "" {
+		openCommand = defaultOpenCommandTemplate
+	}
+	pullsLFS := false
+	for _, rule := range cfg.Git.CloneRules {
+		if strings.EqualFold(strings.TrimSpace(rule.LFS), git.LFSPull) {
+			pullsLFS = true
+		}
+	}
+	usesGH := false
+	for _, host := range cfg.GitHubHosts() {
+		sources := cfg.GitHubHostSettings(host).TokenSources
+		for _, source := range sources {
+			if strings.TrimSpace(source) == config.TokenSourceGH {
+				usesGH = true
+			}
+		}
+	}
+
+	tools := []tool{
+		{name: "tmux", needed: true, usedBy: "'ezgit connect' and closing sessions in 'ezgit rm'", fix: "install tmux"},
+		{name: "sesh", needed: strings.Contains(openCommand, "sesh"), usedBy: "the open command", fix: "install sesh, or set [git].open_command to something else"},
+		{name: "zoxide", needed: true, usedBy: "registering new worktrees with zoxide", fix: "install zoxide"},
+		{name: "gh", needed: usesGH, usedBy: "the gh token source", fix: "install gh and run 'gh auth login', or drop gh from [github].token_sources"},
+		{name: "git-lfs", needed: pullsLFS, usedBy: `clone rules with lfs = "pull"`, fix: "install git-lfs, or set lfs = \"skip\" in those rules"},
+		{name: "ssh-keygen", needed: len(cfg.Git.SSHKeys) > 0, usedBy: "validating the keys in [git].ssh_keys", fix: "install OpenSSH"},
+	}
+
+	var checks []doctorCheck
+	for _, t := range tools {
+		if !t.needed {
+			continue
+		}
+		path, err := env.lookPath(t.name)
+		if err != nil {
+			checks = append(checks, doctorCheck{
+				Name: t.name, Status: doctorWarn,
+				Detail: "not found on PATH; " + t.usedBy + " will not work",
+				Fix:    t.fix,
+			})
+			continue
+		}
+		checks = append(checks, doctorCheck{Name: t.name, Status: doctorOK, Detail: path})
+	}
+	return checks
+}
+
+func inspectGitHubToken(host, token string) (*github.TokenInfo, error) {
+	if host != config.DefaultGitHubHost {
+		// The API client only talks to api.github.com.
+		return nil, nil
+	}
+	return github.NewClient(token).InspectToken()
+}
+
+func doctorTokenChecks(cfg *config.Config, env doctorEnv) []doctorCheck {
+	var checks []doctorCheck
+	for _, host := range cfg.GitHubHosts() {
+		check := doctorCheck{Name: "token " + host}
+		resolution, err := cfg.ResolveGitHubToken(host)
+		if err != nil {
+			check.Status = doctorFail
+			check.Detail = err.Error()
+			check.Fix = fmt.Sprintf("run 'gh auth login --hostname %s', or configure [github].token_sources", host)
+			checks = append(checks, check)
+			continue
+		}
+
+		info, err := env.inspectToken(host, resolution.Token)
+		if err != nil {
+			check.Status = doctorFail
+			check.Detail = fmt.Sprintf("token from %s was rejected: %v", resolution.Source, err)
+			check.Fix = "replace the token, or run 'gh auth refresh' if it comes from gh"
+			checks = append(checks, check)
+			continue
+		}
+		if info == nil {
+			check.Status = doctorOK
+			check.Detail = fmt.Sprintf("token from %s (not verified against the API)", resolution.Source)
+			checks = append(checks, check)
+			continue
+		}
+
+		check.Detail = fmt.Sprintf("token from %s for %s", resolution.Source, info.Login)
+		if info.ScopesKnown {
+			check.Detail += fmt.Sprintf(" (scopes: %s)", strings.Join(info.Scopes, ", "))
+		}
+		required := []string{"repo"}
+		if len(cfg.GetOrganizations()) > 0 {
+			required = append(required, "read:org")
+		}
+		if missing := info.MissingScopes(required...); len(missing) > 0 {
+			check.Status = doctorWarn
+			check.Detail += "; missing " + strings.Join(missing, ", ") + ", so private repositories are not listed"
+			check.Fix = fmt.Sprintf("grant %s to the token (for gh: gh auth refresh -h %s -s %s)", strings.Join(missing, ", "), host, strings.Join(missing, ","))
+		} else {
+			check.Status = doctorOK
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// doctorSSHHosts lists github.com and every host named in the config.
+func doctorSSHHosts(cfg *config.Config) []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	add := func(host string) {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+
+	for _, host := range cfg.GitHubHosts() {
+		add(host)
+	}
+	var others []string
+	for _, rule := range cfg.Git.SSHKeys {
+		others = append(others, rule.Host)
+	}
+	for _, rule := range cfg.Git.ProtocolRules {
+		others = append(others, rule.Host)
+	}
+	sort.Strings(others)
+	for _, host := range others {
+		add(host)
+	}
+	return hosts
+}
+
+// doctorSSHChecks tries an SSH login to each host. A failure only breaks
+// cloning when the host is cloned over ssh; with https or auto it is a
+// warning.
+func doctorSSHChecks(cfg *config.Config, env doctorEnv) []doctorCheck {
+	var checks []doctorCheck
+	for _, host := range doctorSSHHosts(cfg) {
+		check := doctorCheck{Name: "ssh " + host}
+		protocol := cfg.RepoProtocol(host, "")
+		keyPath := cfg.RepoSSHKey(host, "")
+
+		source := ""
+		if keyPath != "" {
+			source = "pinned key " + keyPath
+		} else if identity, ok := env.discoverSSH(host); ok {
+			source = fmt.Sprintf("%s %s", identity.Source, identity.Path)
+		}
+
+		err := env.probeSSH(host, keyPath)
+		if err == nil {
+			check.Status = doctorOK
+			check.Detail = "authenticated"
+			if source != "" {
+				check.Detail += " using " + source
+			}
+			checks = append(checks, check)
+			continue
+		}
+
+		check.Detail = err.Error()
+		check.Status = doctorWarn
+		if protocol == config.ProtocolSSH {
+			check.Status = doctorFail
+		}
+		check.Detail += fmt.Sprintf(" (protocol %s)", protocol)
+		if source == "" {
+			check.Fix = fmt.Sprintf("create a key with 'ssh-keygen -t ed25519' and add it to your account on %s, or set protocol = \"https\" under [git]", host)
+		} else {
+			check.Fix = fmt.Sprintf("add the public key of %s to your account on %s and test with 'ssh -T git@%s', or set protocol = \"https\" under [git]", source, host, host)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// doctorHTTPSChecks runs 'git ls-remote' over HTTPS to each host with the
+// credential helper and proxy an HTTPS clone gets, and reports the protocol
+// auto picks there given the SSH checks. A cached repo on the host tests the
+// token too; without one only reaching the host is checked.
+func doctorHTTPSChecks(cfg *config.Config, env doctorEnv, sshChecks []doctorCheck) []doctorCheck {
+	sshOK := make(map[string]bool, len(sshChecks))
+	for _, check := range sshChecks {
+		sshOK[strings.TrimPrefix(check.Name, "ssh ")] = check.Status == doctorOK
+	}
+
+	var checks []doctorCheck
+	for _, host := range doctorSSHHosts(cfg) {
+		check := doctorCheck{Name: "https " + host}
+		protocol := cfg.RepoProtocol(host, "")
+		auto := config.ProtocolHTTPS
+		if sshOK[host] {
+			auto = config.ProtocolSSH
+		}
+
+		repoURL := doctorHTTPSProbeURL(env.cache, host)
+		err := env.probeHTTPS(repoURL, cloneConfig(cfg, repoURL))
+		switch {
+		case err == nil:
+			check.Status = doctorOK
+			check.Detail = "ls-remote " + repoURL + " succeeded"
+		case strings.HasSuffix(repoURL, "/") && strings.Contains(err.Error(), "not found"):
+			check.Status = doctorOK
+			check.Detail = "host reachable; no cached repository to test the token with"
+		default:
+			check.Detail = err.Error()
+			check.Status = doctorWarn
+			if protocol == config.ProtocolHTTPS || (protocol == config.ProtocolAuto && auto == config.ProtocolHTTPS) {
+				check.Status = doctorFail
+			}
+			check.Fix = fmt.Sprintf("make sure a token for %s resolves (see its token check) and that the host is reachable without a proxy, or set protocol = \"ssh\" under [git]", host)
+			if proxy := strings.TrimSpace(cfg.Git.Proxy); proxy != "" {
+				check.Fix = fmt.Sprintf("check that %s reaches %s and that the token for %s is valid, or set protocol = \"ssh\" under [git]", proxy, host, host)
+			}
+		}
+		check.Detail += fmt.Sprintf(" (protocol %s; auto picks %s)", protocol, auto)
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// doctorHTTPSProbeURL returns the HTTPS clone URL of a cached repo on host,
+// else the host's root URL.
+func doctorHTTPSProbeURL(c *cache.OrgCache, host string) string {
+	if repos, err := c.GetAllRepos(); err == nil {
+		for _, repo := range repos {
+			if repoHost, ok := git.HTTPSHost(repo.URL); ok && strings.EqualFold(repoHost, host) {
+				return repo.URL
+			}
+		}
+	}
+	return "https://" + host + "/"
+}
+
+func doctorCacheChecks(cfg *config.Config, c *cache.OrgCache) []doctorCheck {
+	if err := checkDirWritable(c.Dir()); err != nil {
+		return []doctorCheck{{
+			Name: "cache", Status: doctorFail,
+			Detail: fmt.Sprintf("%s is not writable: %v", c.Dir(), err),
+			Fix:    "chmod u+w " + c.Dir(),
+		}}
+	}
+
+	cached, err := c.ListAll()
+	if err != nil {
+		return []doctorCheck{{Name: "cache", Status: doctorFail, Detail: err.Error(), Fix: "rm -rf " + c.Dir()}}
+	}
+
+	var checks []doctorCheck
+	for _, org := range cached {
+		if _, err := c.GetStale(org); err != nil {
+			checks = append(checks, doctorCheck{
+				Name: "cache " + org, Status: doctorFail,
+				Detail: err.Error(),
+				Fix:    fmt.Sprintf("ezgit cache invalidate %s && ezgit cache refresh %s", org, org),
+			})
+			continue
+		}
+		if c.IsExpired(org) {
+			checks = append(checks, doctorCheck{
+				Name: "cache " + org, Status: doctorWarn,
+				Detail: "expired; the picker skips its repositories",
+				Fix:    "ezgit cache refresh " + org,
+			})
+		}
+	}
+
+	isCached := make(map[string]bool, len(cached))
+	for _, org := range cached {
+		isCached[org] = true
+	}
+	for _, org := range cfg.GetOrganizations() {
+		if !isCached[org] {
+			checks = append(checks, doctorCheck{
+				Name: "cache " + org, Status: doctorWarn,
+				Detail: "configured but never cached",
+				Fix:    "ezgit cache refresh " + org,
+			})
+		}
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{
+			Name: "cache", Status: doctorOK,
+			Detail: fmt.Sprintf("%d entries cached, all fresh (%s)", len(cached), c.Dir()),
+		})
+	}
+	return checks
+}
+
+func writeDoctorChecks(w io.Writer, checks []doctorCheck) {
+	symbols := map[string]string{doctorOK: "✓", doctorWarn: "!", doctorFail: "✗"}
+	for _, check := range checks {
+		fmt.Fprintf(w, "%s %s: %s\n", symbols[check.Status], check.Name, check.Detail)
+		if check.Fix != "" {
+			fmt.Fprintf(w, "    fix: %s\n", check.Fix)
+		}
+	}
+	fmt.Fprintf(w, "\n%d checks, %d warnings, %d failed\n", len(checks), countDoctorChecks(checks, doctorWarn), countDoctorChecks(checks, doctorFail))
+}
+
+func countDoctorChecks(checks []doctorCheck, status string) int {
+	count := 0
+	for _, check := range checks {
+		if check.Status == status {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+)
+
+func fakeDoctorEnv(t *testing.T) doctorEnv {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	return doctorEnv{
+		lookPath:   func(file string) (string, error) { return "/usr/bin/" + file, nil },
+		gitVersion: func() (string, error) { return "git version 2.43.0", nil },
+		inspectToken: func(host, token string) (*github.TokenInfo, error) {
+			return &github.TokenInfo{Login: "octocat", Scopes: []string{"repo", "read:org"}, ScopesKnown: true}, nil
+		},
+		discoverSSH: func(host string) (git.SSHIdentity, bool) {
+			return git.SSHIdentity{Source: git.SSHSourceAgent, Path: "/tmp/agent.sock"}, true
+		},
+		probeSSH:   func(host, keyPath string) error { return nil },
+		probeHTTPS: func(repoURL string, config []string) error { return nil },
+		cache:      cache.New(),
+	}
+}
+
+func doctorStatuses(checks []doctorCheck) map[string]doctorCheck {
+	byName := make(map[string]doctorCheck, len(checks))
+	for _, check := range checks {
+		byName[check.Name] = check
+	}
+	return byName
+}
+
+func TestRunDoctorChecksHealthyEnvironment(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	cfg := &config.Config{
+		Organizations: config.OrganizationConfig{Orgs: []string{"acme"}},
+		GitHub:        config.GitHubConfig{Token: "tok", TokenSources: []string{config.TokenSourceConfig}},
+		Git:           config.GitConfig{CloneDir: t.TempDir(), Proxy: "http://proxy.internal:3128"},
+	}
+	if err := env.cache.Set("acme", []github.Repo{{FullName: "acme/api", URL: "https://github.com/acme/api.git"}}); err != nil {
+		t.Fatal(err)
+	}
+	var probed []string
+	env.probeHTTPS = func(repoURL string, config []string) error {
+		probed = append(probed, repoURL+" "+strings.Join(config, " "))
+		return nil
+	}
+
+	checks := runDoctorChecks(cfg, env)
+	for _, check := range checks {
+		if check.Status != doctorOK {
+			t.Errorf("check %s = %s (%s), want ok", check.Name, check.Status, check.Detail)
+		}
+	}
+	byName := doctorStatuses(checks)
+	if _, ok := byName["gh"]; ok {
+		t.Error("gh was checked although no token source uses it")
+	}
+	if got := byName["ssh github.com"].Detail; !strings.Contains(got, "agent /tmp/agent.sock") {
+		t.Errorf("ssh detail = %q, want the identity source", got)
+	}
+	if len(probed) != 1 || !strings.HasPrefix(probed[0], "https://github.com/acme/api.git credential.https://github.com.helper=") ||
+		!strings.HasSuffix(probed[0], " http.proxy=http://proxy.internal:3128") {
+		t.Errorf("https probes = %q, want the cached repo with the credential helper and proxy", probed)
+	}
+	if got := byName["https github.com"].Detail; !strings.Contains(got, "auto picks ssh") {
+		t.Errorf("https detail = %q, want auto to pick ssh", got)
+	}
+}
+
+func TestRunDoctorChecksReportsProblemsWithFixes(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	env.lookPath = func(file string) (string, error) {
+		if file == "tmux" {
+			return "", fmt.Errorf("not found")
+		}
+		return "/usr/bin/" + file, nil
+	}
+	env.gitVersion = func() (string, error) { return "git version 2.20.1", nil }
+	env.inspectToken = func(host, token string) (*github.TokenInfo, error) {
+		return &github.TokenInfo{Login: "octocat", Scopes: []string{"public_repo"}, ScopesKnown: true}, nil
+	}
+	env.probeSSH = func(host, keyPath string) error {
+		if host == "ghe.example.com" && keyPath != "/keys/ghe" {
+			t.Errorf("probe of %s used key %q, want the pinned key", host, keyPath)
+		}
+		return fmt.Errorf("ssh to %s failed: Permission denied (publickey)", host)
+	}
+	env.probeHTTPS = func(repoURL string, config []string) error {
+		if repoURL == "https://ghe.example.com/" {
+			return fmt.Errorf("git ls-remote %s failed: fatal: repository '%s' not found", repoURL, repoURL)
+		}
+		return fmt.Errorf("git ls-remote %s failed: Failed to connect to proxy", repoURL)
+	}
+
+	cfg := &config.Config{
+		Organizations: config.OrganizationConfig{Orgs: []string{"acme"}},
+		GitHub:        config.GitHubConfig{Token: "tok", TokenSources: []string{config.TokenSourceConfig}},
+		Git: config.GitConfig{
+			Protocol:      config.ProtocolAuto,
+			ProtocolRules: []config.ProtocolRule{{Host: "ghe.example.com", Protocol: config.ProtocolSSH}},
+			SSHKeys:       []config.SSHKeyRule{{Host: "ghe.example.com", Key: "/keys/ghe"}},
+		},
+	}
+
+	byName := doctorStatuses(runDoctorChecks(cfg, env))
+	want := map[string]string{
+		"git":                 doctorFail,
+		"clone_dir":           doctorFail,
+		"tmux":                doctorWarn,
+		"ssh-keygen":          doctorOK,
+		"token github.com":    doctorWarn,
+		"ssh github.com":      doctorWarn,
+		"ssh ghe.example.com": doctorFail,
+		"https github.com":    doctorFail,
+		"cache acme":          doctorWarn,
+	}
+	for name, status := range want {
+		check, ok := byName[name]
+		if !ok {
+			t.Errorf("missing check %s", name)
+			continue
+		}
+		if check.Status != status {
+			t.Errorf("check %s = %s (%s), want %s", name, check.Status, check.Detail, status)
+		}
+		if status != doctorOK && check.Fix == "" {
+			t.Errorf("check %s has no fix", name)
+		}
+	}
+	if fix := byName["token github.com"].Fix; !strings.Contains(fix, "gh auth refresh -h github.com -s repo,read:org") {
+		t.Errorf("token fix = %q", fix)
+	}
+	if got := byName["https github.com"].Detail; !strings.Contains(got, "protocol auto; auto picks https") {
+		t.Errorf("https github.com detail = %q, want the protocol auto picks", got)
+	}
+	if got := byName["https ghe.example.com"]; got.Status != doctorOK || !strings.Contains(got.Detail, "host reachable") {
+		t.Errorf("https ghe.example.com = %+v, want the host reachable", got)
+	}
+}
+
+func TestRunDoctorChecksAcceptsNonGitHubSSHGreetings(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	env.probeSSH = git.ProbeSSHKey
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+*git@gitlab.example.com*) echo "Welcome to GitLab, @octocat!"; exit 0 ;;
+esac
+echo "git@github.com: Permission denied (publickey)." >&2
+exit 255
+`
+	if err := os.WriteFile(filepath.Join(binDir, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Token: "tok", TokenSources: []string{config.TokenSourceConfig}},
+		Git: config.GitConfig{
+			CloneDir:      t.TempDir(),
+			ProtocolRules: []config.ProtocolRule{{Host: "gitlab.example.com", Protocol: config.ProtocolSSH}},
+		},
+	}
+	byName := doctorStatuses(runDoctorChecks(cfg, env))
+	if got := byName["ssh gitlab.example.com"]; got.Status != doctorOK {
+		t.Errorf("ssh gitlab.example.com = %+v, want ok for the GitLab greeting", got)
+	}
+	if got := byName["ssh github.com"]; got.Status == doctorOK || !strings.Contains(got.Detail, "Permission denied") {
+		t.Errorf("ssh github.com = %+v, want the rejected key reported", got)
+	}
+}
+
+func TestDoctorConfigReportsInvalidSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	content := `[git]
+backend = "turbo"
+protocol = "ftp"
+clone_rules = [{ match = "acme/*", filter = "blob:limit=1m" }]
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, checks := doctorConfig(configPath)
+	if len(checks) != 1 || checks[0].Status != doctorFail {
+		t.Fatalf("doctorConfig() = %+v, want one failed check", checks)
+	}
+	for _, want := range []string{"backend", "protocol", "clone_rules[0]"} {
+		if !strings.Contains(checks[0].Detail, want) {
+			t.Errorf("detail %q does not mention %s", checks[0].Detail, want)
+		}
+	}
+
+	if _, checks := doctorConfig(filepath.Join(t.TempDir(), "missing.toml")); checks[0].Status != doctorFail {
+		t.Fatalf("doctorConfig(missing) = %+v, want a failure", checks)
+	}
+}
+
+func TestWriteDoctorChecksShowsFixes(t *testing.T) {
+	var out bytes.Buffer
+	writeDoctorChecks(&out, []doctorCheck{
+		{Name: "git", Status: doctorOK, Detail: "git version 2.43.0"},
+		{Name: "tmux", Status: doctorWarn, Detail: "not found on PATH", Fix: "install tmux"},
+	})
+	want := "✓ git: git version 2.43.0\n! tmux: not found on PATH\n    fix: install tmux\n\n2 checks, 1 warnings, 0 failed\n"
+	if out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+	if major, minor, ok := parseGitVersion("git version 2.39.3 (Apple Git-146)"); !ok || major != 2 || minor != 39 {
+		t.Fatalf("parseGitVersion() = %d.%d, %v", major, minor, ok)
+	}
+}
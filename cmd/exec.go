@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command in every local repository",
+	Long: `Run a command in every local repository, in parallel.
+
+A single argument is run with bash -c, so pipes and variables work; several
+arguments are executed directly. The command runs in the repo (or worktree)
+directory with the same variables open_command gets: $org, $repo, $worktree,
+$absPath, $repoPath, $orgRepo, $repoFullName and their UPPER_SNAKE_CASE forms.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+var (
+	execOrg       string
+	execReposFrom string
+	execWorktree  string
+	execJobs      int
+	execPrefix    bool
+)
+
+type execResult struct {
+	FullName string
+	Path     string
+	Output   []byte
+	Skipped  string
+	Err      error
+	Duration time.Duration
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().StringVar(&execOrg, "org", "", "only run in repositories owned by this org or user")
+	execCmd.Flags().StringVar(&execReposFrom, "repos-from", "", "read owner/repo names from a file, one per line ('-' for stdin)")
+	execCmd.Flags().StringVar(&execWorktree, "worktree", "", "run in this worktree (default: the repo root, or the default branch worktree)")
+	execCmd.Flags().IntVarP(&execJobs, "jobs", "j", defaultWorktreeLookupConcurrency, "number of repositories to run in parallel")
+	execCmd.Flags().BoolVar(&execPrefix, "prefix", false, "stream output with a repo prefix instead of grouping it per repo")
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	if cmd.ArgsLenAtDash() != 0 {
+		return fmt.Errorf("put the command after '--', e.g. ezgit exec -- git status")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var repoNames []string
+	if execReposFrom != "" {
+		repoNames, err = readRepoList(execReposFrom)
+		if err != nil {
+			return err
+		}
+	} else {
+		repos, err := collectCachedRepos(cache.New())
+		if err != nil {
+			return err
+		}
+		repos = filterReposByOrg(repos, execOrg)
+		repoNames = sortedRepoNames(repos, utils.BuildLocalRepoMap(cfg.GetCloneDir(), repos), true)
+	}
+	if len(repoNames) == 0 {
+		return fmt.Errorf("no local repositories to run in")
+	}
+
+	results := execInRepos(cfg, repoNames, execWorktree, args, execJobs, os.Stdout, execPrefix)
+	if failed := writeExecSummary(os.Stdout, results); failed > 0 {
+		return fmt.Errorf("command failed in %d repositories", failed)
+	}
+	return nil
+}
+
+// readRepoList reads owner/repo names (or GitHub URLs), one per line.
+// Blank lines and # comments are ignored.
+func readRepoList(path string) ([]string, error) {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read repo list: %w", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var names []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, ok := extractRepoFullName(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid repo %q in %s: expected owner/repo", line, path)
+		}
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repo list: %w", err)
+	}
+	return names, nil
+}
+
+// execInRepos runs command in each repo with bounded concurrency. Output is
+// printed per repo as each one finishes, or streamed line by line with a repo
+// prefix when prefix is set.
+func execInRepos(cfg *config.Config, repoNames []string, worktreeName string, command []string, workers int, out io.Writer, prefix bool) []execResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	width := 0
+	for _, name := range repoNames {
+		width = max(width, len(name))
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	var outMu sync.Mutex
+	results := make([]execResult, 0, len(repoNames))
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				var result execResult
+				if prefix {
+					w := &linePrefixWriter{mu: &outMu, w: out, prefix: fmt.Sprintf("%-*s | ", width, name)}
+					result = execInRepo(cfg, name, worktreeName, command, w)
+					w.Flush()
+					switch {
+					case result.Skipped != "":
+						w.writeLine([]byte("skipped: " + result.Skipped + "\n"))
+					case result.Err != nil:
+						w.writeLine([]byte("failed: " + firstErrorLine(result.Err.Error()) + "\n"))
+					}
+				} else {
+					var buf bytes.Buffer
+					result = execInRepo(cfg, name, worktreeName, command, &buf)
+					result.Output = buf.Bytes()
+				}
+
+				outMu.Lock()
+				results = append(results, result)
+				if !prefix {
+					writeExecGroup(out, result)
+				}
+				outMu.Unlock()
+			}
+		}()
+	}
+
+	for _, name := range repoNames {
+		jobs <- name
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].FullName < results[j].FullName })
+	return results
+}
+
+func execInRepo(cfg *config.Config, repoFullName, worktreeName string, command []string, output io.Writer) execResult {
+	result := execResult{FullName: repoFullName}
+
+	ctx, skipped, err := resolveRepoWorkdir(cfg, repoFullName, worktreeName)
+	if err != nil || skipped != "" {
+		result.Skipped, result.Err = skipped, err
+		return result
+	}
+	result.Path = ctx.AbsPath
+
+	if dryRun {
+		planStep("in %s: %s", utils.ShellQuote(ctx.AbsPath), execCommandLine(command))
+		return result
+	}
+
+	var cmd *exec.Cmd
+	if len(command) == 1 {
+		cmd = exec.Command("bash", "-c", command[0])
+	} else {
+		cmd = exec.Command(command[0], command[1:]...)
+	}
+	cmd.Dir = ctx.AbsPath
+	cmd.Env = append(os.Environ(), ctx.env()...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	start := time.Now()
+	result.Err = cmd.Run()
+	result.Duration = time.Since(start)
+	return result
+}
+
+// execCommandLine formats command as it would run: a single argument is a
+// shell snippet, several are an argv.
+func execCommandLine(command []string) string {
+	if len(command) == 1 {
+		return "bash -c " + utils.ShellQuote(command[0])
+	}
+	return utils.ShellJoin(command...)
+}
+
+// resolveRepoWorkdir picks the directory to run in for a local repo: the
+// named worktree, else the repo root for regular clones and the default
+// branch worktree for the worktree layout. A non-empty skipped reason means
+// the repo has no such directory.
+func resolveRepoWorkdir(cfg *config.Config, repoFullName, worktreeName string) (ctx openCommandContext, skipped string, err error) {
+	repoRootPath := getRepoPath(cfg, repoFullName, false, "")
+	if repoRootPath == "" {
+		return ctx, "", fmt.Errorf("failed to resolve local path (is [git].clone_dir set?)")
+	}
+	state, err := detectExistingRepoState(repoRootPath)
+	if err != nil {
+		return ctx, "", err
+	}
+
+	switch state {
+	case existingRepoMissing:
+		return ctx, "not cloned", nil
+	case existingRepoNonRepo:
+		return ctx, "", fmt.Errorf("%s is not a git repository", repoRootPath)
+	case existingRepoRegular:
+		if worktreeName != "" {
+			return ctx, "regular clone, no worktree " + worktreeName, nil
+		}
+	case existingRepoWorktree:
+		if worktreeName == "" {
+			worktreeName = resolveDefaultBranch(repoFullName, "")
+		}
+	}
+
+	ctx, err = buildOpenCommandContext(cfg, repoFullName, worktreeName)
+	if err != nil {
+		return ctx, "", err
+	}
+	if info, err := os.Stat(ctx.AbsPath); err != nil || !info.IsDir() {
+		return ctx, "no worktree " + worktreeName, nil
+	}
+	return ctx, "", nil
+}
+
+func writeExecGroup(w io.Writer, result execResult) {
+	switch {
+	case result.Skipped != "":
+		fmt.Fprintf(w, "==> %s (skipped: %s)\n", result.FullName, result.Skipped)
+		return
+	case result.Err != nil:
+		fmt.Fprintf(w, "==> %s (failed: %s, %s)\n", result.FullName, firstErrorLine(result.Err.Error()), result.Duration.Round(time.Millisecond))
+	default:
+		fmt.Fprintf(w, "==> %s (ok, %s)\n", result.FullName, result.Duration.Round(time.Millisecond))
+	}
+	w.Write(result.Output)
+	if len(result.Output) > 0 && result.Output[len(result.Output)-1] != '\n' {
+		fmt.Fprintln(w)
+	}
+}
+
+// writeExecSummary prints the pass/fail counts and returns the number of
+// failed repos. Skipped repos do not count as failures.
+func writeExecSummary(w io.Writer, results []execResult) int {
+	passed, skipped := 0, 0
+	var failed []string
+	for _, result := range results {
+		switch {
+		case result.Skipped != "":
+			skipped++
+		case result.Err != nil:
+			failed = append(failed, result.FullName)
+		default:
+			passed++
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d passed, %d failed, %d skipped\n", passed, len(failed), skipped)
+	if len(failed) > 0 {
+		fmt.Fprintf(w, "Failed: %s\n", strings.Join(failed, ", "))
+	}
+	return len(failed)
+}
+
+// linePrefixWriter writes complete lines to w, each prefixed, holding mu so
+// lines from concurrent repos do not interleave.
+type linePrefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *linePrefixWriter) Write(data []byte) (int, error) {
+	p.buf = append(p.buf, data...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			return len(data), nil
+		}
+		p.writeLine(p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+}
+
+// Flush writes a trailing partial line, if any.
+func (p *linePrefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *linePrefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "%s%s", p.prefix, line)
+}
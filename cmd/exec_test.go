@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+)
+
+func TestExecInRepos(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cloneDir := t.TempDir()
+	runGitCmd(t, "", "init", "-b", "main", filepath.Join(cloneDir, "acme", "api"))
+	runGitCmd(t, "", "init", "--bare", filepath.Join(cloneDir, "acme", "mono", ".git"))
+	if err := os.MkdirAll(filepath.Join(cloneDir, "acme", "mono", "main"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	repos := []string{"acme/api", "acme/missing", "acme/mono"}
+	command := []string{`echo "$repoFullName:$worktree:$(basename "$PWD")"; test "$repo" != mono`}
+
+	var out bytes.Buffer
+	results := execInRepos(cfg, repos, "", command, 2, &out, false)
+
+	got := make(map[string]string)
+	for _, result := range results {
+		got[result.FullName] = strings.TrimSpace(string(result.Output))
+		switch {
+		case result.Skipped != "":
+			got[result.FullName] = "skipped: " + result.Skipped
+		case result.Err != nil:
+			got[result.FullName] += " (failed)"
+		}
+	}
+	want := map[string]string{
+		"acme/api":     "acme/api::api",
+		"acme/missing": "skipped: not cloned",
+		"acme/mono":    "acme/mono:main:main (failed)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("execInRepos() = %v, want %v", got, want)
+	}
+	if !strings.Contains(out.String(), "==> acme/api (ok, ") || !strings.Contains(out.String(), "==> acme/missing (skipped: not cloned)") {
+		t.Fatalf("grouped output:\n%s", out.String())
+	}
+
+	var summary bytes.Buffer
+	if failed := writeExecSummary(&summary, results); failed != 1 {
+		t.Fatalf("writeExecSummary() failed = %d, want 1", failed)
+	}
+	if !strings.Contains(summary.String(), "1 passed, 1 failed, 1 skipped\nFailed: acme/mono") {
+		t.Fatalf("summary:\n%s", summary.String())
+	}
+
+	out.Reset()
+	results = execInRepos(cfg, repos, "review", []string{"printf", "one\ntwo"}, 1, &out, true)
+	for _, result := range results {
+		if result.Skipped == "" {
+			t.Fatalf("--worktree review result = %+v, want skipped", result)
+		}
+	}
+
+	out.Reset()
+	execInRepos(cfg, []string{"acme/api"}, "", []string{"printf", "one\ntwo"}, 1, &out, true)
+	if out.String() != "acme/api | one\nacme/api | two\n" {
+		t.Fatalf("prefixed output = %q", out.String())
+	}
+}
+
+func TestReadRepoList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.txt")
+	content := "# services\nacme/api\n\ngit@github.com:acme/web.git  # frontend\nacme/api\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readRepoList(path)
+	if err != nil {
+		t.Fatalf("readRepoList() error = %v", err)
+	}
+	if want := []string{"acme/api", "acme/web"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("readRepoList() = %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("not-a-repo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readRepoList(path); err == nil {
+		t.Fatal("readRepoList() expected error for invalid entry")
+	}
+}
+
+func TestExecInReposOnlyPlansUnderDryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cloneDir := t.TempDir()
+	repoDir := filepath.Join(cloneDir, "acme", "api")
+	runGitCmd(t, "", "init", "-b", "main", repoDir)
+
+	oldDryRun, oldPlan := dryRun, dryRunGitMgr
+	t.Cleanup(func() { dryRun, dryRunGitMgr = oldDryRun, oldPlan })
+	dryRun, dryRunGitMgr = true, nil
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	var out bytes.Buffer
+	results := execInRepos(cfg, []string{"acme/api"}, "", []string{"touch ran"}, 1, &out, false)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("execInRepos() = %+v", results)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "ran")); !os.IsNotExist(err) {
+		t.Fatalf("command ran under --dry-run (err=%v)", err)
+	}
+	want := []string{"in " + repoDir + ": bash -c 'touch ran'"}
+	if got := dryRunGitMgr.Steps(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("planned steps = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+	"github.com/kirksw/ezgit/internal/ui"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search tracked files across local repositories",
+	Long: `Search tracked files across local repositories with git grep.
+
+Hits stream as owner/repo:path:line:text. With --pick, hits are shown in a
+picker and the selected one is opened with open_command, which also gets
+$file (absolute path) and $line.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGrep,
+}
+
+var (
+	grepOrg        string
+	grepLang       string
+	grepWorktree   string
+	grepPaths      []string
+	grepIgnoreCase bool
+	grepFixed      bool
+	grepJSON       bool
+	grepPick       bool
+	grepJobs       int
+)
+
+// grepHit is one match, flattened for --json output.
+type grepHit struct {
+	Repo     string `json:"repo"`
+	Worktree string `json:"worktree,omitempty"`
+	AbsPath  string `json:"-"`
+	git.GrepMatch
+}
+
+func (h grepHit) String() string {
+	return fmt.Sprintf("%s:%s:%d:%s", h.Repo, h.Path, h.Line, h.Text)
+}
+
+type grepFailure struct {
+	FullName string
+	Err      error
+}
+
+// repoGrepper is the subset of git.GitManager grep needs.
+type repoGrepper interface {
+	Grep(worktreePath string, opts git.GrepOptions, onMatch func(git.GrepMatch)) error
+}
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+
+	grepCmd.Flags().StringVar(&grepOrg, "org", "", "only search repositories owned by this org or user")
+	grepCmd.Flags().StringVar(&grepLang, "lang", "", "only search repositories whose primary language matches (from the cache)")
+	grepCmd.Flags().StringVar(&grepWorktree, "worktree", "", "search this worktree (default: the repo root, or the default branch worktree)")
+	grepCmd.Flags().StringArrayVar(&grepPaths, "path", nil, "limit the search to files matching a git pathspec, e.g. '*.go' (repeatable)")
+	grepCmd.Flags().BoolVarP(&grepIgnoreCase, "ignore-case", "i", false, "match case-insensitively")
+	grepCmd.Flags().BoolVarP(&grepFixed, "fixed-strings", "F", false, "treat the pattern as a literal string")
+	grepCmd.Flags().BoolVar(&grepJSON, "json", false, "print one JSON object per hit")
+	grepCmd.Flags().BoolVar(&grepPick, "pick", false, "pick a hit and open it with open_command")
+	grepCmd.Flags().IntVarP(&grepJobs, "jobs", "j", defaultWorktreeLookupConcurrency, "number of repositories to search in parallel")
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	if grepPick && grepJSON {
+		return fmt.Errorf("--pick and --json cannot be combined")
+	}
+	if grepPick && !isInteractiveStdin() {
+		return fmt.Errorf("--pick requires an interactive terminal")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := collectCachedRepos(cache.New())
+	if err != nil {
+		return err
+	}
+	repos = filterReposByLanguage(filterReposByOrg(repos, grepOrg), grepLang)
+	repoNames := sortedRepoNames(repos, utils.BuildLocalRepoMap(cfg.GetCloneDir(), repos), true)
+	if len(repoNames) == 0 {
+		return fmt.Errorf("no local repositories to search")
+	}
+
+	opts := git.GrepOptions{
+		Pattern:      args[0],
+		IgnoreCase:   grepIgnoreCase,
+		FixedStrings: grepFixed,
+		Pathspecs:    grepPaths,
+	}
+
+	var hits []grepHit
+	onHit := func(hit grepHit) {
+		if grepPick {
+			hits = append(hits, hit)
+		} else {
+			writeGrepHit(os.Stdout, hit, grepJSON)
+		}
+	}
+	failed := grepRepos(cfg, repoNames, grepWorktree, opts, newGitManager(cfg), grepJobs, onHit)
+	for _, failure := range failed {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", failure.FullName, firstErrorLine(failure.Err.Error()))
+	}
+
+	if grepPick {
+		if err := pickGrepHit(cfg, hits); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("search failed in %d repositories", len(failed))
+	}
+	return nil
+}
+
+func filterReposByLanguage(repos []github.Repo, language string) []github.Repo {
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return repos
+	}
+
+	var filtered []github.Repo
+	for _, repo := range repos {
+		if strings.EqualFold(repo.Language, language) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// grepRepos searches repos in parallel and calls onHit for every match as it
+// is found; calls are serialized so onHit needs no locking. It returns the
+// repos whose search failed. Repos without the requested worktree are
+// skipped silently.
+func grepRepos(cfg *config.Config, repoNames []string, worktreeName string, opts git.GrepOptions, grepper repoGrepper, workers int, onHit func(grepHit)) []grepFailure {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	var hitMu sync.Mutex
+	var failed []grepFailure
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				ctx, skipped, err := resolveRepoWorkdir(cfg, name, worktreeName)
+				if err == nil && skipped == "" {
+					err = grepper.Grep(ctx.AbsPath, opts, func(match git.GrepMatch) {
+						hitMu.Lock()
+						defer hitMu.Unlock()
+						onHit(grepHit{Repo: name, Worktree: ctx.Worktree, AbsPath: ctx.AbsPath, GrepMatch: match})
+					})
+				}
+				if err != nil {
+					hitMu.Lock()
+					failed = append(failed, grepFailure{FullName: name, Err: err})
+					hitMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, name := range repoNames {
+		jobs <- name
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(failed, func(i, j int) bool { return failed[i].FullName < failed[j].FullName })
+	return failed
+}
+
+func writeGrepHit(w io.Writer, hit grepHit, asJSON bool) {
+	if !asJSON {
+		fmt.Fprintln(w, hit.String())
+		return
+	}
+	data, err := json.Marshal(hit)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+// pickGrepHit lets the user choose a hit and opens its repo or worktree with
+// $file and $line set for the open command.
+func pickGrepHit(cfg *config.Config, hits []grepHit) error {
+	if len(hits) == 0 {
+		fmt.Println("No matches")
+		return nil
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Repo < hits[j].Repo })
+	lines := make([]string, len(hits))
+	byLine := make(map[string]grepHit, len(hits))
+	for i, hit := range hits {
+		lines[i] = strings.TrimSpace(truncateRunes(hit.String(), 200))
+		byLine[lines[i]] = hit
+	}
+
+	selected, cancelled, err := ui.RunPicker(lines, ui.PickerLabels{
+		Title:       fmt.Sprintf("Select match (%d)", len(hits)),
+		Placeholder: "Filter matches...",
+		Empty:       "No matches",
+		Action:      "open",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to select match: %w", err)
+	}
+	hit, ok := byLine[selected]
+	if cancelled || !ok {
+		return nil
+	}
+
+	file := filepath.Join(hit.AbsPath, filepath.FromSlash(hit.Path))
+	return runOpenCommand(cfg, hit.Repo, hit.Worktree,
+		fmt.Sprintf("file=%s", file),
+		fmt.Sprintf("line=%d", hit.Line),
+		fmt.Sprintf("FILE=%s", file),
+		fmt.Sprintf("LINE=%d", hit.Line),
+	)
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+type fakeGrepper map[string][]git.GrepMatch
+
+func (f fakeGrepper) Grep(worktreePath string, opts git.GrepOptions, onMatch func(git.GrepMatch)) error {
+	matches, ok := f[worktreePath]
+	if !ok {
+		return errors.New("fatal: broken repo\ndetails")
+	}
+	for _, match := range matches {
+		onMatch(match)
+	}
+	return nil
+}
+
+func TestGrepRepos(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cloneDir := t.TempDir()
+	apiPath := filepath.Join(cloneDir, "acme", "api")
+	monoMain := filepath.Join(cloneDir, "acme", "mono", "main")
+	runGitCmd(t, "", "init", "-b", "main", apiPath)
+	runGitCmd(t, "", "init", "-b", "main", filepath.Join(cloneDir, "acme", "broken"))
+	runGitCmd(t, "", "init", "--bare", filepath.Join(cloneDir, "acme", "mono", ".git"))
+	if err := os.MkdirAll(monoMain, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	grepper := fakeGrepper{
+		apiPath:  {{Path: "main.go", Line: 3, Text: "// TODO: wire up"}},
+		monoMain: {{Path: "svc/a.go", Line: 1, Text: "TODO"}, {Path: "svc/b.go", Line: 9, Text: "TODO again"}},
+	}
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+
+	var hits []string
+	var out bytes.Buffer
+	failed := grepRepos(cfg, []string{"acme/api", "acme/broken", "acme/missing", "acme/mono"}, "", git.GrepOptions{Pattern: "TODO"}, grepper, 3, func(hit grepHit) {
+		hits = append(hits, hit.Worktree+" "+hit.String())
+		if hit.Repo == "acme/mono" && hit.Line == 9 {
+			writeGrepHit(&out, hit, true)
+		}
+	})
+
+	if len(failed) != 1 || failed[0].FullName != "acme/broken" {
+		t.Fatalf("grepRepos() failed = %+v, want acme/broken", failed)
+	}
+	want := map[string]bool{
+		" acme/api:main.go:3:// TODO: wire up": true,
+		"main acme/mono:svc/a.go:1:TODO":       true,
+		"main acme/mono:svc/b.go:9:TODO again": true,
+	}
+	got := make(map[string]bool)
+	for _, hit := range hits {
+		got[hit] = true
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("grepRepos() hits = %v, want %v", hits, want)
+	}
+
+	if wantJSON := `{"repo":"acme/mono","worktree":"main","path":"svc/b.go","line":9,"text":"TODO again"}` + "\n"; out.String() != wantJSON {
+		t.Fatalf("JSON hit = %s, want %s", out.String(), wantJSON)
+	}
+}
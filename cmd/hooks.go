@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/utils"
+)
+
+// repoHooksDir is where a repo keeps its own hook scripts, one executable
+// per event name, e.g. .ezgit/hooks/post-worktree-create.
+const repoHooksDir = ".ezgit/hooks"
+
+// hookedGitManager runs lifecycle hooks around the GitManager calls that
+// create, convert and remove checkouts, and seeds new worktrees with the
+// configured untracked files, so every command that clones or adds worktrees
+// does both without re-deriving paths.
+type hookedGitManager struct {
+	git.GitManager
+	cfg *config.Config
+}
+
+// withHooks wraps gitMgr when cfg configures any hooks or worktree files.
+func withHooks(cfg *config.Config, gitMgr git.GitManager) git.GitManager {
+	if cfg == nil || (!cfg.HasHooks() && !cfg.HasWorktreeFiles()) {
+		return gitMgr
+	}
+	return &hookedGitManager{GitManager: gitMgr, cfg: cfg}
+}
+
+// Clone runs post-clone in the new clone. A bare clone has no checkout yet;
+// its worktrees get post-worktree-create as they are added.
+func (h *hookedGitManager) Clone(url, path string, opts git.CloneOptions) error {
+	if err := h.GitManager.Clone(url, path, opts); err != nil {
+		return err
+	}
+	root := path
+	if opts.Bare && filepath.Base(path) == ".git" {
+		root = filepath.Dir(path)
+	}
+	warnHookFailure(runHooks(h.cfg, config.HookPostClone, hookContext(h.cfg, root, root)))
+	return nil
+}
+
+func (h *hookedGitManager) ConvertToBare(path string) error {
+	plan, err := h.GitManager.PlanConvertToBare(path)
+	if err != nil {
+		return err
+	}
+	if err := h.GitManager.ConvertToBare(path); err != nil {
+		return err
+	}
+	warnHookFailure(runHooks(h.cfg, config.HookPostConvert, hookContext(h.cfg, path, plan.WorktreePath)))
+	return nil
+}
+
+func (h *hookedGitManager) CreateWorktree(barePath, worktreePath, branch string) error {
+	return h.createWorktree(barePath, worktreePath, func() error {
+		return h.GitManager.CreateWorktree(barePath, worktreePath, branch)
+	})
+}
+
+func (h *hookedGitManager) CreateDetachedWorktree(barePath, worktreePath, startPoint string) error {
+	return h.createWorktree(barePath, worktreePath, func() error {
+		return h.GitManager.CreateDetachedWorktree(barePath, worktreePath, startPoint)
+	})
+}
+
+func (h *hookedGitManager) CreateFeatureWorktree(barePath, worktreePath, featureBranch, baseBranch string) error {
+	return h.createWorktree(barePath, worktreePath, func() error {
+		return h.GitManager.CreateFeatureWorktree(barePath, worktreePath, featureBranch, baseBranch)
+	})
+}
+
+// createWorktree seeds the worktree and runs post-worktree-create only for
+// a worktree that did not exist before, since adding an existing worktree is
+// a no-op that every 'ezgit open' repeats. Seeding comes first so hooks such
+// as "npm ci" see the copied files.
+func (h *hookedGitManager) createWorktree(barePath, worktreePath string, create func() error) error {
+	_, statErr := os.Stat(worktreePath)
+	existed := statErr == nil
+	if err := create(); err != nil {
+		return err
+	}
+	if !existed {
+		ctx := hookContext(h.cfg, hookRepoRoot(barePath), worktreePath)
+		warnHookFailure(seedWorktree(h.cfg, h.GitManager, barePath, worktreePath, ctx.RepoFullName))
+		warnHookFailure(runHooks(h.cfg, config.HookPostWorktreeCreate, ctx))
+	}
+	return nil
+}
+
+// RemoveWorktree runs pre-remove first; a failing hook keeps the worktree.
+func (h *hookedGitManager) RemoveWorktree(barePath, worktreePath string, force bool) error {
+	if err := runHooks(h.cfg, config.HookPreRemove, hookContext(h.cfg, hookRepoRoot(barePath), worktreePath)); err != nil {
+		return fmt.Errorf("%w; worktree not removed", err)
+	}
+	return h.GitManager.RemoveWorktree(barePath, worktreePath, force)
+}
+
+// hookRepoRoot returns the repo directory for bare metadata at <root>/.git.
+func hookRepoRoot(barePath string) string {
+	if filepath.Base(filepath.Clean(barePath)) == ".git" {
+		return filepath.Dir(filepath.Clean(barePath))
+	}
+	return barePath
+}
+
+// hookContext builds the open-command variables for checkoutPath in the repo
+// at repoRoot. Repos under clone_dir are named <org>/<repo> from their path
+// there; others from their last two path elements.
+func hookContext(cfg *config.Config, repoRoot, checkoutPath string) openCommandContext {
+	root, err := filepath.Abs(repoRoot)
+	if err != nil {
+		root = filepath.Clean(repoRoot)
+	}
+	checkout, err := filepath.Abs(checkoutPath)
+	if err != nil {
+		checkout = filepath.Clean(checkoutPath)
+	}
+
+	org, repo := filepath.Base(filepath.Dir(root)), filepath.Base(root)
+	if cloneDir := cfg.GetCloneDir(); cloneDir != "" {
+		if absCloneDir, err := filepath.Abs(cloneDir); err == nil {
+			if rel, err := filepath.Rel(absCloneDir, root); err == nil {
+				if parts := strings.Split(filepath.ToSlash(rel), "/"); len(parts) == 2 && parts[0] != ".." {
+					org, repo = parts[0], parts[1]
+				}
+			}
+		}
+	}
+
+	worktree := ""
+	if rel, err := filepath.Rel(root, checkout); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		worktree = filepath.ToSlash(rel)
+	}
+	orgRepo := org + "/" + repo
+	repoPath := orgRepo
+	if worktree != "" {
+		repoPath = orgRepo + "/" + worktree
+	}
+	return openCommandContext{
+		Org:          org,
+		Repo:         repo,
+		Worktree:     worktree,
+		AbsPath:      checkout,
+		RepoPath:     repoPath,
+		OrgRepo:      orgRepo,
+		RepoFullName: orgRepo,
+	}
+}
+
+// runHooks runs the hooks for event in ctx.AbsPath: the configured commands,
+// then the repo's own .ezgit/hooks/<event> script when the repo is trusted.
+// Commands get the open-command variables plus EZGIT_HOOK. Their output goes
+// to stderr so commands with machine-readable stdout stay parseable.
+func runHooks(cfg *config.Config, event string, ctx openCommandContext) error {
+	commands := cfg.RepoHooks(event, ctx.RepoFullName)
+	script := filepath.Join(ctx.AbsPath, filepath.FromSlash(repoHooksDir), event)
+	if info, err := os.Stat(script); err == nil && !info.IsDir() {
+		if cfg.TrustsRepoHooks(ctx.RepoFullName) {
+			commands = append(commands, utils.ShellQuote(script))
+		} else if !quiet {
+			fmt.Fprintf(os.Stderr, "Skipping %s: add %q to [hooks].trusted_repos to run this repo's hooks\n", script, ctx.RepoFullName)
+		}
+	}
+
+	for _, command := range commands {
+		if dryRun {
+			planStep("%s hook in %s: %s", event, utils.ShellQuote(ctx.AbsPath), command)
+			continue
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Running %s hook: %s\n", event, command)
+		}
+		cmd := exec.Command("bash", "-c", command)
+		cmd.Dir = ctx.AbsPath
+		cmd.Env = append(append(os.Environ(), ctx.env()...), "EZGIT_HOOK="+event)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed in %s: %w", event, command, ctx.AbsPath, err)
+		}
+	}
+	return nil
+}
+
+// warnHookFailure reports a failed post-* hook. The operation it follows has
+// already happened, so it is not undone.
+func warnHookFailure(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
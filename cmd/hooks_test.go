@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+func readHookLog(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestHooksRunOnNewWorktreesOnly(t *testing.T) {
+	cfg, repoDir, _ := setupWorktreeLayoutFixture(t)
+	logPath := filepath.Join(t.TempDir(), "hooks.log")
+	cfg.Hooks = config.HooksConfig{
+		HookCommands: config.HookCommands{
+			PostWorktreeCreate: []string{`echo "global $EZGIT_HOOK $REPO_FULL_NAME $worktree $(pwd)" >> ` + logPath},
+		},
+		Rules: []config.HookRule{
+			{Match: "acme/*", HookCommands: config.HookCommands{PostWorktreeCreate: []string{"echo org >> " + logPath}}},
+			{Match: "other/*", HookCommands: config.HookCommands{PostWorktreeCreate: []string{"echo other >> " + logPath}}},
+		},
+	}
+
+	gitMgr := withHooks(cfg, git.New())
+	metadataPath := filepath.Join(repoDir, ".git")
+	featurePath := filepath.Join(repoDir, "feature-x")
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, featurePath, "feature-x", "main"); err != nil {
+		t.Fatalf("CreateFeatureWorktree() error = %v", err)
+	}
+	// Re-adding an existing worktree is a no-op and must not rerun hooks.
+	if err := gitMgr.CreateWorktree(metadataPath, filepath.Join(repoDir, "main"), "main"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	want := []string{
+		"global post-worktree-create acme/widgets feature-x " + featurePath,
+		"org",
+	}
+	if got := readHookLog(t, logPath); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("hook log = %q, want %q", got, want)
+	}
+
+	if _, ok := withHooks(&config.Config{}, git.New()).(*hookedGitManager); ok {
+		t.Fatal("withHooks() wrapped a manager without any hooks configured")
+	}
+}
+
+func TestPreRemoveHookRunsTrustedRepoScriptAndCanBlockRemoval(t *testing.T) {
+	cfg, repoDir, _ := setupWorktreeLayoutFixture(t)
+	logPath := filepath.Join(t.TempDir(), "hooks.log")
+	mainPath := filepath.Join(repoDir, "main")
+	metadataPath := filepath.Join(repoDir, ".git")
+
+	scriptDir := filepath.Join(mainPath, ".ezgit", "hooks")
+	if err := os.MkdirAll(scriptDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"repo $WORKTREE\" >> " + logPath + "\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(scriptDir, config.HookPreRemove), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Untrusted repos do not run their own scripts.
+	cfg.Hooks = config.HooksConfig{HookCommands: config.HookCommands{PreRemove: []string{"echo global >> " + logPath}}}
+	quiet = true
+	t.Cleanup(func() { quiet = false })
+	if err := withHooks(cfg, git.New()).RemoveWorktree(metadataPath, mainPath, true); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if got := readHookLog(t, logPath); len(got) != 1 || got[0] != "global" {
+		t.Fatalf("hook log = %q, want only the global hook", got)
+	}
+
+	gitMgr := git.New()
+	if err := gitMgr.CreateWorktree(metadataPath, mainPath, "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(scriptDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(scriptDir, config.HookPreRemove), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Hooks.TrustedRepos = []string{"acme/*"}
+	err := withHooks(cfg, gitMgr).RemoveWorktree(metadataPath, mainPath, true)
+	if err == nil || !strings.Contains(err.Error(), "worktree not removed") {
+		t.Fatalf("RemoveWorktree() error = %v, want the failing repo hook to block removal", err)
+	}
+	if _, statErr := os.Stat(mainPath); statErr != nil {
+		t.Fatalf("worktree removed despite failing pre-remove hook: %v", statErr)
+	}
+	if got := readHookLog(t, logPath); len(got) != 3 || got[2] != "repo main" {
+		t.Fatalf("hook log = %q, want the trusted repo script to run", got)
+	}
+}
+
+func TestHookContextNamesReposOutsideCloneDir(t *testing.T) {
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: "/src"}}
+
+	ctx := hookContext(cfg, "/src/acme/widgets", "/src/acme/widgets/feature/x")
+	if ctx.RepoFullName != "acme/widgets" || ctx.Worktree != "feature/x" || ctx.RepoPath != "acme/widgets/feature/x" {
+		t.Fatalf("hookContext(in clone_dir) = %+v", ctx)
+	}
+
+	ctx = hookContext(cfg, "/work/team/tool", "/work/team/tool")
+	if ctx.RepoFullName != "team/tool" || ctx.Worktree != "" || ctx.AbsPath != "/work/team/tool" {
+		t.Fatalf("hookContext(outside clone_dir) = %+v", ctx)
+	}
+}
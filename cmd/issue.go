@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var issueCmd = &cobra.Command{
+	Use:   "issue <repo> <number>",
+	Short: "Create a worktree for an issue, named from a branch template",
+	Long: `Create a worktree for a GitHub issue and open it.
+
+The branch name comes from [git].issue_branch_template (default
+"{number}-{title_slug}"). Placeholders: {user} (your GitHub login),
+{number}, {title_slug}, {owner} and {repo}. The branch is created from the
+default branch; if it already exists on origin it is checked out instead.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runIssue,
+}
+
+var (
+	issueNoOpen bool
+	issuePrint  bool
+)
+
+const maxTitleSlugLength = 50
+
+var branchTemplatePlaceholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+func init() {
+	rootCmd.AddCommand(issueCmd)
+
+	issueCmd.Flags().BoolVar(&issueNoOpen, "no-open", false, "create the worktree without running open_command")
+	issueCmd.Flags().BoolVar(&issuePrint, "print", false, "only print the branch name")
+}
+
+func runIssue(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repoFullName, ok := extractRepoFullName(args[0])
+	if !ok {
+		return fmt.Errorf("invalid repo format: %s", args[0])
+	}
+	number, err := parseGitHubNumber(args[1], "/issues/", "issue")
+	if err != nil {
+		return err
+	}
+
+	client := github.NewClient(cfg.GetGitHubToken())
+	issue, err := client.GetIssue(repoFullName, number)
+	if err != nil {
+		return err
+	}
+	if issue.IsPullRequest() {
+		return fmt.Errorf("#%d is a pull request; use 'ezgit pr %s %d'", number, repoFullName, number)
+	}
+
+	template := cfg.GetIssueBranchTemplate()
+	login := ""
+	if strings.Contains(template, "{user}") {
+		info, err := client.InspectToken()
+		if err != nil {
+			return fmt.Errorf("{user} in issue_branch_template needs a GitHub token: %w", err)
+		}
+		login = info.Login
+	}
+
+	owner, repoName, _ := strings.Cut(repoFullName, "/")
+	branch, err := renderBranchTemplate(template, map[string]string{
+		"user":       login,
+		"number":     strconv.Itoa(number),
+		"title_slug": slugifyTitle(issue.Title, maxTitleSlugLength),
+		"owner":      owner,
+		"repo":       repoName,
+	})
+	if err != nil {
+		return err
+	}
+	if issuePrint {
+		fmt.Println(branch)
+		return nil
+	}
+
+	fmt.Printf("#%d %s\n", issue.Number, issue.Title)
+	defaultBranch := resolveDefaultBranch(repoFullName, "")
+	worktreePath, err := createIssueWorktree(cfg, newGitManager(cfg), repoFullName, defaultBranch, branch)
+	if err != nil {
+		return err
+	}
+	registerPathsWithZoxide([]string{worktreePath}, quiet)
+
+	if issueNoOpen {
+		fmt.Printf("Worktree %s ready at %s\n", branch, worktreePath)
+		return nil
+	}
+	return runOpenCommand(cfg, repoFullName, branch)
+}
+
+// createIssueWorktree makes sure the repo uses the worktree layout and adds
+// a worktree for branch. An existing worktree is reused as is.
+func createIssueWorktree(cfg *config.Config, gitMgr git.GitManager, repoFullName, defaultBranch, branch string) (string, error) {
+	featureBranch, baseBranch, err := resolveFeatureWorktreeConfig(defaultBranch, branch, "")
+	if err != nil {
+		return "", err
+	}
+
+	repoRootPath, err := ensureWorktreeLayout(cfg, gitMgr, repoFullName, defaultBranch)
+	if err != nil {
+		return "", err
+	}
+
+	worktreePath := filepath.Join(repoRootPath, featureBranch)
+	worktrees, err := gitMgr.ListWorktrees(repoRootPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if containsString(worktrees, featureBranch) {
+		return worktreePath, nil
+	}
+
+	metadataPath := filepath.Join(repoRootPath, ".git")
+	// Fetch first so a branch someone already pushed for the issue is found.
+	if err := gitMgr.FetchPrune(metadataPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", firstErrorLine(err.Error()))
+	}
+	if gitMgr.RefExists(metadataPath, "refs/remotes/origin/"+featureBranch) {
+		err = gitMgr.CreateWorktree(metadataPath, worktreePath, featureBranch)
+	} else {
+		err = gitMgr.CreateFeatureWorktree(metadataPath, worktreePath, featureBranch, baseBranch)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create worktree %s: %w", featureBranch, err)
+	}
+	return worktreePath, nil
+}
+
+// renderBranchTemplate fills {placeholders} in template from vars. Unknown
+// placeholders and placeholders that render empty are errors, so a typo in
+// the config does not silently produce odd branch names.
+func renderBranchTemplate(template string, vars map[string]string) (string, error) {
+	var renderErr error
+	branch := branchTemplatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		value, ok := vars[strings.Trim(placeholder, "{}")]
+		if renderErr == nil {
+			if !ok {
+				renderErr = fmt.Errorf("unknown placeholder %s in issue_branch_template", placeholder)
+			} else if value == "" {
+				renderErr = fmt.Errorf("placeholder %s in issue_branch_template is empty", placeholder)
+			}
+		}
+		return value
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	branch = strings.TrimSpace(branch)
+	if err := validateBranchName(branch); err != nil {
+		return "", err
+	}
+	return branch, nil
+}
+
+// validateBranchName rejects names git or the worktree layout cannot use.
+func validateBranchName(branch string) error {
+	switch {
+	case branch == "":
+		return fmt.Errorf("branch name is empty")
+	case strings.ContainsAny(branch, " \t~^:?*[\\"),
+		strings.Contains(branch, ".."),
+		strings.Contains(branch, "//"),
+		strings.Contains(branch, "@{"),
+		strings.HasPrefix(branch, "-"),
+		strings.HasPrefix(branch, "/"),
+		strings.HasSuffix(branch, "/"),
+		strings.HasSuffix(branch, "."),
+		strings.HasSuffix(branch, ".lock"):
+		return fmt.Errorf("invalid branch name: %q", branch)
+	}
+	return nil
+}
+
+// slugifyTitle lowercases title and joins its ASCII letters and digits with
+// dashes, cutting at a word boundary so the slug is at most limit bytes.
+func slugifyTitle(title string, limit int) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pendingDash = false
+		} else {
+			pendingDash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) <= limit {
+		return slug
+	}
+	if slug[limit] == '-' {
+		return slug[:limit]
+	}
+	slug = slug[:limit]
+	if idx := strings.LastIndex(slug, "-"); idx > 0 {
+		slug = slug[:idx]
+	}
+	return slug
+}
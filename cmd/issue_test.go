@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+func TestSlugifyTitle(t *testing.T) {
+	tests := map[string]string{
+		"Login fails on Safari":                 "login-fails-on-safari",
+		"  [Bug] Crash: nil *Config in v1.2!  ": "bug-crash-nil-config-in-v1-2",
+		"Ünïcode only":                          "n-code-only",
+		"???":                                   "",
+		"Make the repository picker remember the last scope that was selected": "make-the-repository-picker-remember-the-last-scope",
+		"Make the repository picker remember the last scoped selection":        "make-the-repository-picker-remember-the-last",
+	}
+	for title, want := range tests {
+		if got := slugifyTitle(title, maxTitleSlugLength); got != want {
+			t.Errorf("slugifyTitle(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestRenderBranchTemplate(t *testing.T) {
+	vars := map[string]string{"user": "octocat", "number": "12", "title_slug": "login-fails", "owner": "acme", "repo": "api"}
+
+	got, err := renderBranchTemplate("{user}/{number}-{title_slug}", vars)
+	if err != nil || got != "octocat/12-login-fails" {
+		t.Fatalf("renderBranchTemplate() = %q, %v", got, err)
+	}
+
+	for _, template := range []string{"{usr}/{number}", "{number}-{title_slug}/", "{number} {title_slug}"} {
+		if _, err := renderBranchTemplate(template, vars); err == nil {
+			t.Errorf("renderBranchTemplate(%q) expected error", template)
+		}
+	}
+
+	vars["title_slug"] = ""
+	if _, err := renderBranchTemplate("{number}-{title_slug}", vars); err == nil {
+		t.Error("renderBranchTemplate() expected error for empty slug")
+	}
+}
+
+func TestCreateIssueWorktree(t *testing.T) {
+	cfg, repoDir, push := setupWorktreeLayoutFixture(t)
+	push("claimed.txt", "octocat/13-claimed")
+
+	gitMgr := git.New()
+	path, err := createIssueWorktree(cfg, gitMgr, "acme/widgets", "main", "octocat/12-login-fails")
+	if err != nil {
+		t.Fatalf("createIssueWorktree() error = %v", err)
+	}
+	if path != filepath.Join(repoDir, "octocat", "12-login-fails") {
+		t.Fatalf("createIssueWorktree() path = %q", path)
+	}
+	if branch := strings.TrimSpace(runGitCmd(t, path, "rev-parse", "--abbrev-ref", "HEAD")); branch != "octocat/12-login-fails" {
+		t.Fatalf("worktree branch = %q", branch)
+	}
+
+	if again, err := createIssueWorktree(cfg, gitMgr, "acme/widgets", "main", "octocat/12-login-fails"); err != nil || again != path {
+		t.Fatalf("createIssueWorktree() second run = %q, %v", again, err)
+	}
+
+	// A branch someone already pushed is checked out rather than recreated.
+	claimed, err := createIssueWorktree(cfg, gitMgr, "acme/widgets", "main", "octocat/13-claimed")
+	if err != nil {
+		t.Fatalf("createIssueWorktree(existing remote branch) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(claimed, "claimed.txt")); err != nil {
+		t.Fatalf("remote branch not checked out: %v", err)
+	}
+
+	if _, err := createIssueWorktree(cfg, gitMgr, "acme/widgets", "main", "review"); err == nil {
+		t.Fatal("createIssueWorktree(review) expected error")
+	}
+}
@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/kirksw/ezgit/internal/cache"
 	"github.com/kirksw/ezgit/internal/config"
@@ -38,12 +44,18 @@ var listWorktreesCmd = &cobra.Command{
 	RunE:  runListWorktrees,
 }
 
-var listReposLocalOnly bool
+var (
+	listReposLocalOnly bool
+	listWorktreesLong  bool
+	listWorktreesJSON  bool
+)
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.AddCommand(listOrgsCmd, listReposCmd, listWorktreesCmd)
 	listReposCmd.Flags().BoolVar(&listReposLocalOnly, "local", false, "only list repos already cloned under clone_dir")
+	listWorktreesCmd.Flags().BoolVarP(&listWorktreesLong, "long", "l", false, "show branch, status and last commit for each worktree")
+	listWorktreesCmd.Flags().BoolVar(&listWorktreesJSON, "json", false, "print worktree status as JSON")
 }
 
 func runListOrgs(cmd *cobra.Command, args []string) error {
@@ -127,7 +139,26 @@ func runListWorktrees(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("destination exists but is not a git repository: %s", repoPath)
 	}
 
-	worktrees, err := git.New().ListWorktrees(repoPath)
+	gitMgr := newGitManager(cfg)
+	if listWorktreesLong || listWorktreesJSON {
+		infos, err := gitMgr.ListWorktreeInfo(repoPath)
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		sortWorktreeInfos(infos)
+		if listWorktreesJSON {
+			data, err := json.MarshalIndent(infos, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode worktrees: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		writeWorktreeTable(os.Stdout, infos, time.Now())
+		return nil
+	}
+
+	worktrees, err := gitMgr.ListWorktrees(repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
@@ -137,6 +168,59 @@ func runListWorktrees(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func sortWorktreeInfos(infos []git.WorktreeInfo) {
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+}
+
+func writeWorktreeTable(w io.Writer, infos []git.WorktreeInfo, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, worktreeRef(info), worktreeStatusSummary(info), worktreeLastCommit(info, now))
+	}
+	tw.Flush()
+}
+
+// worktreeRef is the branch name, or the short HEAD for detached worktrees.
+func worktreeRef(info git.WorktreeInfo) string {
+	if info.Branch != "" {
+		return info.Branch
+	}
+	if info.ShortHead() != "" {
+		return "(detached " + info.ShortHead() + ")"
+	}
+	return "(detached)"
+}
+
+// worktreeStatusSummary renders the compact status shown in `list worktrees
+// --long` and the picker: dirty/untracked counts, ahead/behind and flags.
+func worktreeStatusSummary(info git.WorktreeInfo) string {
+	badges := info.Badges()
+	if len(badges) == 0 {
+		return "clean"
+	}
+	return strings.Join(badges, " ")
+}
+
+func worktreeLastCommit(info git.WorktreeInfo, now time.Time) string {
+	if info.CommitDate == nil {
+		return info.Subject
+	}
+	return fmt.Sprintf("%s (%s)", info.Subject, humanizeAge(now.Sub(*info.CommitDate)))
+}
+
+func humanizeAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
+
 func sortedRepoNames(repos []github.Repo, localRepos map[string]bool, localOnly bool) []string {
 	names := make([]string, 0, len(repos))
 	for _, repo := range repos {
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/kirksw/ezgit/internal/git"
 	"github.com/kirksw/ezgit/internal/github"
 )
 
@@ -27,7 +31,7 @@ func TestSortedStringsCopiesAndSorts(t *testing.T) {
 }
 
 func TestAgentCommandsAreRegistered(t *testing.T) {
-	for _, path := range [][]string{{"list", "orgs"}, {"list", "repos"}, {"list", "worktrees"}, {"describe"}, {"clone"}, {"add"}, {"open"}} {
+	for _, path := range [][]string{{"list", "orgs"}, {"list", "repos"}, {"list", "worktrees"}, {"describe"}, {"clone"}, {"add"}, {"open"}, {"rm"}, {"prune"}, {"status"}, {"sync"}, {"exec"}, {"grep"}, {"pr"}, {"issue"}, {"unshallow"}, {"deepen"}, {"unconvert"}, {"undo"}, {"history"}, {"workspace", "plan"}, {"workspace", "apply"}} {
 		cmd := rootCmd
 		for _, name := range path {
 			next, _, err := cmd.Find([]string{name})
@@ -38,3 +42,47 @@ func TestAgentCommandsAreRegistered(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteWorktreeTable(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	committed := now.Add(-3 * time.Hour)
+	infos := []git.WorktreeInfo{
+		{Name: "main", Branch: "main", Subject: "initial", CommitDate: &committed},
+		{Name: "review", Head: "0123456789abcdef", Detached: true, Locked: true},
+		{Name: "feature", Branch: "feature", Dirty: 1, Untracked: 2, Ahead: 3, Behind: 4},
+	}
+
+	var buf bytes.Buffer
+	writeWorktreeTable(&buf, infos, now)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+
+	wants := [][]string{
+		{"main", "clean", "initial (3h ago)"},
+		{"review", "(detached 0123456)", "locked"},
+		{"feature", "~1 ?2 ↑3 ↓4"},
+	}
+	for i, want := range wants {
+		for _, fragment := range want {
+			if !strings.Contains(lines[i], fragment) {
+				t.Fatalf("line %d = %q, missing %q", i, lines[i], fragment)
+			}
+		}
+	}
+}
+
+func TestHumanizeAge(t *testing.T) {
+	cases := map[time.Duration]string{
+		10 * time.Second: "just now",
+		5 * time.Minute:  "5m ago",
+		2 * time.Hour:    "2h ago",
+		72 * time.Hour:   "3d ago",
+	}
+	for age, want := range cases {
+		if got := humanizeAge(age); got != want {
+			t.Fatalf("humanizeAge(%v) = %q, want %q", age, got, want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var mvCmd = &cobra.Command{
+	Use:   "mv <repo> <worktree> <new-name>",
+	Short: "Rename or move a worktree of a repository",
+	Long: `Move a worktree to <repo>/<new-name> with 'git worktree move'. With
+--rename-branch its branch is renamed to <new-name> too. zoxide entries under
+the old path are re-added under the new one and tmux sessions opened for the
+worktree are renamed so the picker keeps showing it as open.`,
+	Args: cobra.ExactArgs(3),
+	RunE: runMove,
+}
+
+var (
+	moveRenameBranch bool
+
+	listZoxideEntries = func() ([]string, error) {
+		output, err := exec.Command("zoxide", "query", "--list").Output()
+		if err != nil {
+			return nil, err
+		}
+		return strings.Split(strings.TrimSpace(string(output)), "\n"), nil
+	}
+	renameTmuxSession = func(session, newName string) error {
+		cmd := exec.Command("tmux", "rename-session", "-t", session, newName)
+		_, err := cmd.CombinedOutput()
+		return err
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(mvCmd)
+
+	mvCmd.Flags().BoolVar(&moveRenameBranch, "rename-branch", false, "also rename the worktree's branch to the new name")
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	gitMgr := newGitManager(cfg)
+	wt, err := resolveLocalWorktree(cfg, gitMgr, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	moved, err := moveLocalWorktree(gitMgr, wt, args[2], moveRenameBranch)
+	if err != nil {
+		return err
+	}
+	if !quiet {
+		fmt.Printf("✓ Moved worktree %s to %s\n", wt.Path, moved.Path)
+		if moved.Branch != wt.Branch {
+			fmt.Printf("✓ Renamed branch %s to %s\n", wt.Branch, moved.Branch)
+		}
+	}
+	return nil
+}
+
+// moveLocalWorktree moves wt to newName under its repo, renames its branch
+// when renameBranch is set and carries its zoxide entries and tmux sessions
+// over. Everything is checked before anything moves.
+func moveLocalWorktree(gitMgr git.GitManager, wt localWorktree, newName string, renameBranch bool) (localWorktree, error) {
+	newName = strings.Trim(strings.TrimSpace(newName), "/")
+	if newName == "" {
+		return wt, fmt.Errorf("new worktree name cannot be empty")
+	}
+	if newName == wt.Name {
+		return wt, fmt.Errorf("worktree %q already has that name", wt.Name)
+	}
+	for _, part := range strings.Split(newName, "/") {
+		if part == "." || part == ".." || part == ".git" {
+			return wt, fmt.Errorf("invalid worktree name %q", newName)
+		}
+	}
+
+	moved := wt
+	moved.Name = newName
+	moved.Path = resolveOpenTargetPath(wt.RepoPath, newName)
+	if _, err := os.Lstat(moved.Path); err == nil {
+		return wt, fmt.Errorf("destination already exists: %s", moved.Path)
+	}
+	if renameBranch {
+		if wt.Branch == "" {
+			return wt, fmt.Errorf("worktree %q is detached; there is no branch to rename", wt.Name)
+		}
+		if err := validateBranchName(newName); err != nil {
+			return wt, err
+		}
+		if gitMgr.RefExists(wt.MetadataPath, "refs/heads/"+newName) {
+			return wt, fmt.Errorf("branch %q already exists", newName)
+		}
+		moved.Branch = newName
+	}
+
+	if err := gitMgr.MoveWorktree(wt.MetadataPath, wt.Path, moved.Path); err != nil {
+		return wt, err
+	}
+	if renameBranch {
+		if err := gitMgr.RenameBranch(wt.MetadataPath, wt.Branch, moved.Branch); err != nil {
+			moved.Branch = wt.Branch
+			return moved, fmt.Errorf("moved worktree to %s but %w", moved.Path, err)
+		}
+	}
+
+	moveWorktreeIntegrations(wt, moved)
+	return moved, nil
+}
+
+// moveWorktreeIntegrations re-adds zoxide entries at or below the old path
+// under the new one and renames tmux sessions opened for the worktree. Both
+// are best-effort.
+func moveWorktreeIntegrations(wt, moved localWorktree) {
+	oldPath, errOld := filepath.Abs(wt.Path)
+	newPath, errNew := filepath.Abs(moved.Path)
+	if errOld == nil && errNew == nil {
+		entries := []string{oldPath}
+		if listed, err := listZoxideEntries(); err == nil {
+			for _, entry := range listed {
+				if strings.HasPrefix(entry, oldPath+string(filepath.Separator)) {
+					entries = append(entries, entry)
+				}
+			}
+		}
+		for _, entry := range entries {
+			target := newPath + strings.TrimPrefix(entry, oldPath)
+			if dryRun {
+				planStep("zoxide remove %s && zoxide add %s", utils.ShellQuote(entry), utils.ShellQuote(target))
+				continue
+			}
+			// zoxide refuses to remove paths it does not know, which is fine.
+			_ = runZoxideRemove(entry)
+			if err := runZoxideAdd(target); err != nil && verbose {
+				fmt.Printf("Warning: failed to add %s to zoxide: %v\n", target, err)
+			}
+		}
+	}
+
+	sessions, err := listTmuxSessions()
+	if err != nil {
+		return
+	}
+	for _, session := range worktreeSessions(sessions, wt.RepoFullName, wt.Name) {
+		newSession := strings.TrimSuffix(session, wt.Name) + moved.Name
+		if dryRun {
+			planStep("tmux rename-session -t %s %s", utils.ShellQuote(session), utils.ShellQuote(newSession))
+			continue
+		}
+		if err := renameTmuxSession(session, newSession); err != nil && !quiet {
+			fmt.Printf("Warning: failed to rename tmux session %s: %v\n", session, err)
+		}
+	}
+}
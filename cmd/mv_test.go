@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+func TestMoveLocalWorktreeRenamesBranchAndZoxideEntries(t *testing.T) {
+	cfg, repoDir, _ := setupWorktreeLayoutFixture(t)
+	metadataPath := filepath.Join(repoDir, ".git")
+	gitMgr := git.New()
+	oldPath := filepath.Join(repoDir, "scratch")
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, oldPath, "scratch", "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	var removed, added []string
+	origList, origRemove, origAdd := listZoxideEntries, runZoxideRemove, runZoxideAdd
+	t.Cleanup(func() { listZoxideEntries, runZoxideRemove, runZoxideAdd = origList, origRemove, origAdd })
+	listZoxideEntries = func() ([]string, error) {
+		return []string{"/elsewhere", oldPath + "/web", oldPath + "-two"}, nil
+	}
+	runZoxideRemove = func(path string) error { removed = append(removed, path); return nil }
+	runZoxideAdd = func(path string) error { added = append(added, path); return nil }
+
+	wt, err := resolveLocalWorktree(cfg, gitMgr, "acme/widgets", "scratch")
+	if err != nil {
+		t.Fatalf("resolveLocalWorktree() error = %v", err)
+	}
+	if _, err := moveLocalWorktree(gitMgr, wt, "main", false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("moveLocalWorktree(onto main) error = %v, want destination exists", err)
+	}
+
+	if _, err := moveLocalWorktree(gitMgr, wt, "bad..name", true); err == nil || !strings.Contains(err.Error(), "invalid branch name") {
+		t.Fatalf("moveLocalWorktree(bad..name) error = %v, want invalid branch name", err)
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Fatalf("worktree moved despite an invalid branch name: %v", err)
+	}
+
+	moved, err := moveLocalWorktree(gitMgr, wt, "feature/login", true)
+	if err != nil {
+		t.Fatalf("moveLocalWorktree() error = %v", err)
+	}
+	newPath := filepath.Join(repoDir, "feature", "login")
+	if moved.Path != newPath || moved.Branch != "feature/login" {
+		t.Fatalf("moved = %+v", moved)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Fatalf("old worktree path still exists (err=%v)", err)
+	}
+	if got := strings.TrimSpace(runGitCmd(t, newPath, "rev-parse", "--abbrev-ref", "HEAD")); got != "feature/login" {
+		t.Fatalf("moved worktree HEAD = %q, want feature/login", got)
+	}
+	if gitMgr.RefExists(metadataPath, "refs/heads/scratch") {
+		t.Fatal("old branch still exists after --rename-branch")
+	}
+
+	wantRemoved := []string{oldPath, oldPath + "/web"}
+	wantAdded := []string{newPath, newPath + "/web"}
+	if strings.Join(removed, ",") != strings.Join(wantRemoved, ",") || strings.Join(added, ",") != strings.Join(wantAdded, ",") {
+		t.Fatalf("zoxide removed %q added %q, want %q and %q", removed, added, wantRemoved, wantAdded)
+	}
+}
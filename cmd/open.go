@@ -108,14 +108,14 @@ func ensureOpenWorktree(cfg *config.Config, repoFullName, worktreeName string) e
 	case existingRepoMissing:
 		return cloneRepoWithWorktrees(cfg, repoFullName, defaultBranch, worktreeName)
 	case existingRepoRegular:
-		if err := runConvertPath(repoRootPath, defaultBranch); err != nil {
+		if err := runConvertPath(cfg, repoRootPath, defaultBranch); err != nil {
 			return err
 		}
 	case existingRepoNonRepo:
 		return fmt.Errorf("destination exists but is not a git repository: %s", repoRootPath)
 	}
 
-	return ensureWorktreeExists(git.New(), repoRootPath, repoFullName, defaultBranch, worktreeName)
+	return ensureWorktreeExists(newGitManager(cfg), repoRootPath, repoFullName, defaultBranch, worktreeName)
 }
 
 func cloneRepoWithWorktrees(cfg *config.Config, repoFullName, defaultBranch, worktreeName string) error {
@@ -188,7 +188,7 @@ func runOpenRepoSelection(
 
 	repoPath := getRepoPath(cfg, repo.FullName, false, repo.DefaultBranch)
 
-	gitMgr := git.New()
+	gitMgr := newGitManager(cfg)
 	selectedWorktree, cancelled, err := selectOrCreateWorktreeForOpen(gitMgr, repoPath, repo, localOnly, localRepos, selectedWorktree)
 	if err != nil {
 		return err
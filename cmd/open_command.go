@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/utils"
 )
 
 const defaultOpenCommandTemplate = `sesh connect "$absPath"`
@@ -64,15 +65,39 @@ func buildOpenCommandContext(cfg *config.Config, repoFullName string, selectedWo
 	}, nil
 }
 
-func runOpenCommand(cfg *config.Config, repoFullName string, selectedWorktree string) error {
+// runOpenCommand runs the configured open command for a repo or worktree.
+// extraEnv adds caller-specific variables, such as the file and line of a
+// grep hit.
+func runOpenCommand(cfg *config.Config, repoFullName string, selectedWorktree string, extraEnv ...string) error {
 	ctx, err := buildOpenCommandContext(cfg, repoFullName, selectedWorktree)
 	if err != nil {
 		return err
 	}
 
+	if err := runHooks(cfg, config.HookPreOpen, ctx); err != nil {
+		return err
+	}
+
 	command := resolveOpenCommandTemplate(cfg)
+	if dryRun {
+		planStep("absPath=%s bash -c %s", utils.ShellQuote(ctx.AbsPath), utils.ShellQuote(command))
+		return nil
+	}
 	cmd := exec.Command("bash", "-c", command)
-	cmd.Env = append(os.Environ(),
+	cmd.Env = append(append(os.Environ(), ctx.env()...), extraEnv...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("open command failed: %w", err)
+	}
+	return nil
+}
+
+// env returns the variables exported to open commands, in both camelCase and
+// UPPER_SNAKE_CASE.
+func (ctx openCommandContext) env() []string {
+	return []string{
 		fmt.Sprintf("org=%s", ctx.Org),
 		fmt.Sprintf("repo=%s", ctx.Repo),
 		fmt.Sprintf("worktree=%s", ctx.Worktree),
@@ -87,12 +112,5 @@ func runOpenCommand(cfg *config.Config, repoFullName string, selectedWorktree st
 		fmt.Sprintf("REPO_PATH=%s", ctx.RepoPath),
 		fmt.Sprintf("ORG_REPO=%s", ctx.OrgRepo),
 		fmt.Sprintf("REPO_FULL_NAME=%s", ctx.RepoFullName),
-	)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("open command failed: %w", err)
 	}
-	return nil
 }
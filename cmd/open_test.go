@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kirksw/ezgit/internal/config"
@@ -130,3 +133,34 @@ func TestBuildOpenWorktreeLoaderPropagatesListerError(t *testing.T) {
 		t.Fatalf("error = %v, want boom", err)
 	}
 }
+
+func TestEnsureOpenWorktreeDryRunRecordsPlan(t *testing.T) {
+	cfg, repoDir, _ := setupWorktreeLayoutFixture(t)
+	oldDryRun, oldPlan := dryRun, dryRunGitMgr
+	t.Cleanup(func() { dryRun, dryRunGitMgr = oldDryRun, oldPlan })
+	dryRun, dryRunGitMgr = true, nil
+
+	if err := ensureOpenWorktree(cfg, "acme/widgets", "feature-x"); err != nil {
+		t.Fatalf("ensureOpenWorktree() error = %v", err)
+	}
+	if err := runOpenCommand(cfg, "acme/widgets", "feature-x"); err != nil {
+		t.Fatalf("runOpenCommand() error = %v", err)
+	}
+
+	featurePath := filepath.Join(repoDir, "feature-x")
+	want := []string{
+		"git -C " + filepath.Join(repoDir, ".git") + " worktree add -b feature-x " + featurePath + " main",
+		"zoxide add " + repoDir,
+		"zoxide add " + featurePath,
+		"absPath=" + featurePath + ` bash -c 'sesh connect "$absPath"'`,
+	}
+	if got := dryRunGitMgr.Steps(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("planned steps =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if _, err := os.Stat(featurePath); !os.IsNotExist(err) {
+		t.Fatalf("dry run created %s", featurePath)
+	}
+	if got := strings.TrimSpace(runGitCmd(t, repoDir, "branch", "--list", "feature-x")); got != "" {
+		t.Fatalf("dry run created branch feature-x: %q", got)
+	}
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+	"github.com/kirksw/ezgit/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr <repo> [number]",
+	Short: "Check out a pull request as a pr-N worktree",
+	Long: `Check out a pull request as a pr-N worktree and open it.
+
+The pull request head is fetched from refs/pull/N/head, so pull requests
+opened from forks work too. An existing pr-N worktree is moved to the latest
+head unless it has uncommitted changes. Without a number, open pull requests
+are listed in a picker.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runPR,
+}
+
+var prNoOpen bool
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+
+	prCmd.Flags().BoolVar(&prNoOpen, "no-open", false, "check out the worktree without running open_command")
+}
+
+func runPR(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repoFullName, ok := extractRepoFullName(args[0])
+	if !ok {
+		return fmt.Errorf("invalid repo format: %s", args[0])
+	}
+
+	client := github.NewClient(cfg.GetGitHubToken())
+	number := 0
+	if len(args) == 2 {
+		number, err = parsePullRequestNumber(args[1])
+		if err != nil {
+			return err
+		}
+	} else {
+		var cancelled bool
+		number, cancelled, err = pickPullRequest(client, repoFullName)
+		if err != nil || cancelled {
+			return err
+		}
+	}
+
+	return openPullRequest(cfg, client, repoFullName, number, prNoOpen)
+}
+
+// runPullRequestSelection is the picker's "check out pull request" action.
+func runPullRequestSelection(cfg *config.Config, repoFullName string) error {
+	client := github.NewClient(cfg.GetGitHubToken())
+	number, cancelled, err := pickPullRequest(client, repoFullName)
+	if err != nil || cancelled {
+		return err
+	}
+	return openPullRequest(cfg, client, repoFullName, number, noOpen)
+}
+
+// parsePullRequestNumber accepts 12, #12 or a pull request URL.
+func parsePullRequestNumber(input string) (int, error) {
+	return parseGitHubNumber(input, "/pull/", "pull request")
+}
+
+// parseGitHubNumber parses an issue or pull request number given as 12, #12
+// or a URL containing segment (e.g. "/pull/").
+func parseGitHubNumber(input, segment, kind string) (int, error) {
+	value := strings.TrimSpace(input)
+	if idx := strings.Index(value, segment); idx >= 0 {
+		value, _, _ = strings.Cut(value[idx+len(segment):], "/")
+	}
+	value = strings.TrimPrefix(value, "#")
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid %s number: %s", kind, input)
+	}
+	return number, nil
+}
+
+func pickPullRequest(client *github.GitHubClient, repoFullName string) (int, bool, error) {
+	if !isInteractiveStdin() {
+		return 0, false, fmt.Errorf("specify a pull request number or run in an interactive terminal")
+	}
+
+	pulls, err := client.FetchOpenPullRequests(repoFullName)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(pulls) == 0 {
+		fmt.Printf("No open pull requests in %s\n", repoFullName)
+		return 0, true, nil
+	}
+
+	lines := make([]string, len(pulls))
+	byLine := make(map[string]int, len(pulls))
+	for i := range pulls {
+		lines[i] = pullRequestLine(&pulls[i])
+		byLine[lines[i]] = pulls[i].Number
+	}
+
+	selected, cancelled, err := ui.RunPicker(lines, ui.PickerLabels{
+		Title:       fmt.Sprintf("Select pull request (%d)", len(pulls)),
+		Placeholder: "Filter pull requests...",
+		Empty:       "No pull requests",
+		Action:      "check out",
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to select pull request: %w", err)
+	}
+	number, ok := byLine[selected]
+	if cancelled || !ok {
+		return 0, true, nil
+	}
+	return number, false, nil
+}
+
+func pullRequestLine(pr *github.PullRequest) string {
+	line := fmt.Sprintf("#%d %s (%s, %s)", pr.Number, truncateRunes(pr.Title, 80), pr.User.Login, pr.Head.Label)
+	if pr.IsFork() {
+		line += " [fork]"
+	}
+	if pr.Draft {
+		line += " [draft]"
+	}
+	return line
+}
+
+// openPullRequest prints the pull request, checks it out and opens the
+// worktree. Metadata is informational: if the API is unreachable the
+// checkout still goes ahead.
+func openPullRequest(cfg *config.Config, client *github.GitHubClient, repoFullName string, number int, skipOpen bool) error {
+	explicitDefault := ""
+	pr, err := client.GetPullRequest(repoFullName, number)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		fmt.Printf("#%d %s\n", pr.Number, pr.Title)
+		head := pr.Head.Label
+		if pr.IsFork() {
+			head += " (fork)"
+		}
+		fmt.Printf("  author: %s\n  head:   %s\n", pr.User.Login, head)
+		if pr.Base.Repo != nil {
+			explicitDefault = pr.Base.Repo.DefaultBranch
+		}
+	}
+
+	defaultBranch := resolveDefaultBranch(repoFullName, explicitDefault)
+	worktreeName, worktreePath, err := checkoutPullRequest(cfg, newGitManager(cfg), repoFullName, defaultBranch, number)
+	if err != nil {
+		return err
+	}
+	registerPathsWithZoxide([]string{worktreePath}, quiet)
+
+	if skipOpen {
+		fmt.Printf("Checked out #%d at %s\n", number, worktreePath)
+		return nil
+	}
+	return runOpenCommand(cfg, repoFullName, worktreeName)
+}
+
+// checkoutPullRequest makes sure the repo is cloned with the worktree layout,
+// fetches the pull request head and creates or refreshes its pr-N worktree.
+func checkoutPullRequest(cfg *config.Config, gitMgr git.GitManager, repoFullName, defaultBranch string, number int) (string, string, error) {
+	repoRootPath, err := ensureWorktreeLayout(cfg, gitMgr, repoFullName, defaultBranch)
+	if err != nil {
+		return "", "", err
+	}
+
+	metadataPath := filepath.Join(repoRootPath, ".git")
+	ref, err := gitMgr.FetchPullRequest(metadataPath, number)
+	if err != nil {
+		return "", "", err
+	}
+
+	worktreeName := fmt.Sprintf("pr-%d", number)
+	worktreePath := filepath.Join(repoRootPath, worktreeName)
+	worktrees, err := gitMgr.ListWorktrees(repoRootPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if !containsString(worktrees, worktreeName) {
+		if err := gitMgr.CreateDetachedWorktree(metadataPath, worktreePath, ref); err != nil {
+			return "", "", err
+		}
+		return worktreeName, worktreePath, nil
+	}
+
+	dirty, err := gitMgr.IsWorktreeDirty(worktreePath)
+	if err != nil {
+		return "", "", err
+	}
+	if dirty {
+		fmt.Fprintf(os.Stderr, "Warning: %s has uncommitted changes, not updating it to the latest head\n", worktreeName)
+		return worktreeName, worktreePath, nil
+	}
+	if err := gitMgr.CheckoutDetached(worktreePath, ref); err != nil {
+		return "", "", err
+	}
+	return worktreeName, worktreePath, nil
+}
+
+// ensureWorktreeLayout clones a missing repo with the worktree layout, or
+// converts a regular clone once the user confirms, and returns the repo root.
+func ensureWorktreeLayout(cfg *config.Config, gitMgr git.GitManager, repoFullName, defaultBranch string) (string, error) {
+	repoRootPath := getRepoPath(cfg, repoFullName, false, defaultBranch)
+	if repoRootPath == "" {
+		return "", fmt.Errorf("failed to resolve local path for %s", repoFullName)
+	}
+
+	state, err := detectExistingRepoState(repoRootPath)
+	if err != nil {
+		return "", err
+	}
+	switch state {
+	case existingRepoMissing:
+		if err := cloneRepoWithWorktrees(cfg, repoFullName, defaultBranch, defaultBranch); err != nil {
+			return "", err
+		}
+	case existingRepoRegular:
+		action, err := resolveExistingRegularRepoAction(repoRootPath)
+		if err != nil {
+			return "", fmt.Errorf("%w\nor run 'ezgit convert %s' first", err, repoRootPath)
+		}
+		if action != existingRepoActionConvert {
+			return "", fmt.Errorf("%s needs the worktree layout; run 'ezgit convert %s' to switch", repoFullName, repoRootPath)
+		}
+		if err := convertWithoutWorktrees(cfg, repoRootPath, defaultBranch); err != nil {
+			return "", err
+		}
+		if err := ensureWorktreeExists(gitMgr, repoRootPath, repoFullName, defaultBranch, defaultBranch); err != nil {
+			return "", err
+		}
+	case existingRepoNonRepo:
+		return "", fmt.Errorf("destination exists but is not a git repository: %s", repoRootPath)
+	}
+	return repoRootPath, nil
+}
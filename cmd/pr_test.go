@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+func TestParsePullRequestNumber(t *testing.T) {
+	for input, want := range map[string]int{
+		"12":  12,
+		"#12": 12,
+		"https://github.com/acme/widgets/pull/12":       12,
+		"https://github.com/acme/widgets/pull/12/files": 12,
+	} {
+		got, err := parsePullRequestNumber(input)
+		if err != nil || got != want {
+			t.Fatalf("parsePullRequestNumber(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "abc", "0", "#-1"} {
+		if _, err := parsePullRequestNumber(input); err == nil {
+			t.Fatalf("parsePullRequestNumber(%q) expected error", input)
+		}
+	}
+}
+
+// setupWorktreeLayoutFixture clones an origin with a main branch into
+// clone_dir/acme/widgets using the worktree layout. push commits a new file in
+// a seed clone and pushes it to the given refs of origin.
+func setupWorktreeLayoutFixture(t *testing.T) (cfg *config.Config, repoDir string, push func(file string, refs ...string)) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	cloneDir := filepath.Join(tmpDir, "src")
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	push = func(file string, refs ...string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(seedDir, file), []byte(file+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGitCmd(t, seedDir, "add", file)
+		runGitCmd(t, seedDir, "commit", "-m", "add "+file)
+		for _, ref := range refs {
+			runGitCmd(t, seedDir, "push", "--force", "origin", "HEAD:"+ref)
+		}
+	}
+
+	runGitCmd(t, "", "init", "--bare", originDir)
+	runGitCmd(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, "", "clone", originDir, seedDir)
+	runGitCmd(t, seedDir, "config", "user.email", "test@example.com")
+	runGitCmd(t, seedDir, "config", "user.name", "test")
+	push("README.md", "main")
+
+	repoDir = filepath.Join(cloneDir, "acme", "widgets")
+	gitMgr := git.New()
+	runGitCmd(t, "", "clone", "--bare", originDir, filepath.Join(repoDir, ".git"))
+	if err := gitMgr.ConfigureBareRemote(filepath.Join(repoDir, ".git"), "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitMgr.CreateWorktree(filepath.Join(repoDir, ".git"), filepath.Join(repoDir, "main"), "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	return &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}, repoDir, push
+}
+
+func TestCheckoutPullRequestCreatesAndRefreshesWorktree(t *testing.T) {
+	cfg, repoDir, push := setupWorktreeLayoutFixture(t)
+	// A fork's branch is only reachable through the pull request ref.
+	push("fork.txt", "refs/pull/7/head")
+
+	gitMgr := git.New()
+	name, path, err := checkoutPullRequest(cfg, gitMgr, "acme/widgets", "main", 7)
+	if err != nil {
+		t.Fatalf("checkoutPullRequest() error = %v", err)
+	}
+	if name != "pr-7" || path != filepath.Join(repoDir, "pr-7") {
+		t.Fatalf("checkoutPullRequest() = %q, %q", name, path)
+	}
+	if _, err := os.Stat(filepath.Join(path, "fork.txt")); err != nil {
+		t.Fatalf("pull request head not checked out: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "main", "fork.txt")); !os.IsNotExist(err) {
+		t.Fatal("default worktree should not see the pull request commit")
+	}
+
+	push("update.txt", "refs/pull/7/head")
+	if _, _, err := checkoutPullRequest(cfg, gitMgr, "acme/widgets", "main", 7); err != nil {
+		t.Fatalf("checkoutPullRequest() refresh error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "update.txt")); err != nil {
+		t.Fatalf("pr-7 was not refreshed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(path, "update.txt"), []byte("local edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	push("later.txt", "refs/pull/7/head")
+	if _, _, err := checkoutPullRequest(cfg, gitMgr, "acme/widgets", "main", 7); err != nil {
+		t.Fatalf("checkoutPullRequest() dirty error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "later.txt")); !os.IsNotExist(err) {
+		t.Fatal("dirty pr-7 worktree should not be refreshed")
+	}
+	if data, _ := os.ReadFile(filepath.Join(path, "update.txt")); !strings.Contains(string(data), "local edit") {
+		t.Fatal("local changes were lost")
+	}
+}
+
+func TestEnsureWorktreeLayoutDoesNotConvertWithoutConfirmation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cloneDir := t.TempDir()
+	repoDir := filepath.Join(cloneDir, "acme", "widgets")
+	runGitCmd(t, "", "init", "-b", "main", repoDir)
+	if err := os.WriteFile(filepath.Join(repoDir, "notes.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stdin is not a terminal, so no one can confirm the conversion.
+	stdin, err := os.Open(filepath.Join(repoDir, "notes.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	oldStdin := os.Stdin
+	t.Cleanup(func() { os.Stdin = oldStdin })
+	os.Stdin = stdin
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	_, err = ensureWorktreeLayout(cfg, git.New(), "acme/widgets", "main")
+	if err == nil || !strings.Contains(err.Error(), "ezgit convert") {
+		t.Fatalf("ensureWorktreeLayout() error = %v, want a hint to run ezgit convert", err)
+	}
+	if state, _ := detectExistingRepoState(repoDir); state != existingRepoRegular {
+		t.Fatalf("repo state = %v, want it left as a regular clone", state)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "notes.txt")); err != nil {
+		t.Fatalf("working tree changed: %v", err)
+	}
+}
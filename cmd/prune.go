@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune [repo]",
+	Short: "Remove worktrees whose branch is merged or deleted on the remote",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runPrune,
+}
+
+var (
+	pruneAll     bool
+	pruneForce   bool
+	pruneYes     bool
+	pruneNoFetch bool
+)
+
+// pruneCandidate is a worktree that prune would remove, with the reason.
+type pruneCandidate struct {
+	localWorktree
+	Reason string
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().BoolVar(&pruneAll, "all", false, "prune every locally cloned repository")
+	pruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "also remove dirty worktrees and unpushed or unprovably merged branches")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "do not ask for confirmation")
+	pruneCmd.Flags().BoolVar(&pruneNoFetch, "no-fetch", false, "skip 'git fetch --prune' before checking branches")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	if pruneAll == (len(args) == 1) {
+		return fmt.Errorf("specify either a repository or --all")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repoInputs := args
+	if pruneAll {
+		repoInputs, err = localWorktreeLayoutRepos(cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	gitMgr := newGitManager(cfg)
+	var candidates []pruneCandidate
+	for _, repoInput := range repoInputs {
+		found, err := findRepoPruneCandidates(cfg, gitMgr, repoInput)
+		if err != nil {
+			if !pruneAll {
+				return err
+			}
+			fmt.Printf("Warning: skipping %s: %v\n", repoInput, err)
+			continue
+		}
+		candidates = append(candidates, found...)
+	}
+
+	if len(candidates) == 0 {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+
+	fmt.Println("Worktrees to prune:")
+	for _, candidate := range candidates {
+		fmt.Printf("  %s/%s (%s)\n", candidate.RepoFullName, candidate.Name, candidate.Reason)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if !pruneYes {
+		if !isInteractiveStdin() {
+			return fmt.Errorf("refusing to prune without confirmation; re-run with --yes")
+		}
+		fmt.Printf("Remove %d worktree(s) and their branches? [y/n]: ", len(candidates))
+		input, err := readLineTrimmed(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if confirmed, ok := parseYesNoRequired(input); !ok || !confirmed {
+			return fmt.Errorf("cancelled")
+		}
+	}
+
+	failed := 0
+	for _, candidate := range candidates {
+		if err := pruneWorktree(gitMgr, candidate.localWorktree, pruneForce); err != nil {
+			failed++
+			fmt.Printf("Failed to prune %s/%s: %v\n", candidate.RepoFullName, candidate.Name, err)
+			continue
+		}
+		fmt.Printf("✓ Pruned %s/%s\n", candidate.RepoFullName, candidate.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to prune %d worktree(s)", failed)
+	}
+	return nil
+}
+
+// pruneWorktree removes a prune candidate and deletes its branch. Without
+// force the branch goes with 'git branch -d', which keeps branches git
+// cannot prove merged (squash merges); force also removes dirty worktrees
+// and deletes those branches.
+func pruneWorktree(gitMgr git.GitManager, wt localWorktree, force bool) error {
+	if err := removeLocalWorktree(gitMgr, wt, force, false); err != nil {
+		return err
+	}
+	if wt.Branch == "" {
+		return nil
+	}
+	if err := gitMgr.DeleteBranch(wt.MetadataPath, wt.Branch, force); err != nil {
+		return fmt.Errorf("removed worktree %s but kept branch %s (delete it with --force): %w", wt.Path, wt.Branch, err)
+	}
+	return nil
+}
+
+func findRepoPruneCandidates(cfg *config.Config, gitMgr git.GitManager, repoInput string) ([]pruneCandidate, error) {
+	repoFullName, repoPath, metadataPath, _, err := resolveLocalRepo(cfg, repoInput)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pruneNoFetch {
+		if err := gitMgr.FetchPrune(metadataPath); err != nil && !quiet {
+			fmt.Printf("Warning: %s: %v\n", repoFullName, err)
+		}
+	}
+
+	return findPruneCandidates(gitMgr, repoFullName, repoPath, metadataPath, resolveDefaultBranch(repoFullName, ""), pruneForce)
+}
+
+// findPruneCandidates returns the worktrees whose branch is merged into the
+// default branch or whose upstream branch was deleted. The default branch
+// worktree, detached worktrees (like review) and, unless force is set,
+// dirty worktrees and gone branches with commits on no remote are kept.
+func findPruneCandidates(gitMgr git.GitManager, repoFullName, repoPath, metadataPath, defaultBranch string, force bool) ([]pruneCandidate, error) {
+	worktrees, err := gitMgr.ListWorktrees(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	mergeTarget := defaultBranch
+	if remoteDefault := "origin/" + defaultBranch; gitMgr.RefExists(metadataPath, remoteDefault) {
+		mergeTarget = remoteDefault
+	}
+
+	var candidates []pruneCandidate
+	for _, name := range sortedStrings(worktrees) {
+		wt := newLocalWorktree(gitMgr, repoFullName, repoPath, metadataPath, name)
+		if wt.Branch == "" || wt.Branch == defaultBranch {
+			continue
+		}
+
+		reason := ""
+		if merged, err := gitMgr.IsBranchMerged(metadataPath, wt.Branch, mergeTarget); err == nil && merged {
+			reason = "merged into " + mergeTarget
+		} else if gone, err := gitMgr.IsBranchUpstreamGone(metadataPath, wt.Branch); err == nil && gone {
+			// Commits made after the remote branch was deleted exist only here.
+			if !force {
+				if unpushed, err := gitMgr.UnpushedCommits(wt.Path); err != nil || unpushed > 0 {
+					continue
+				}
+			}
+			reason = "upstream branch deleted"
+		}
+		if reason == "" {
+			continue
+		}
+
+		if !force {
+			if dirty, err := gitMgr.IsWorktreeDirty(wt.Path); err != nil || dirty {
+				continue
+			}
+		}
+
+		candidates = append(candidates, pruneCandidate{localWorktree: wt, Reason: reason})
+	}
+
+	return candidates, nil
+}
+
+// localWorktreeLayoutRepos lists cached repos that are cloned locally in the
+// bare+worktree layout.
+func localWorktreeLayoutRepos(cfg *config.Config) ([]string, error) {
+	repos, err := collectCachedRepos(cache.New())
+	if err != nil {
+		return nil, err
+	}
+
+	localRepos := utils.BuildLocalRepoMap(cfg.GetCloneDir(), repos)
+	var names []string
+	for _, name := range sortedRepoNames(repos, localRepos, true) {
+		repoPath := getRepoPath(cfg, name, false, "")
+		if state, err := detectExistingRepoState(repoPath); err == nil && state == existingRepoWorktree {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	return names, nil
+}
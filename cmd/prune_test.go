@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+type fakePruneGitManager struct {
+	git.GitManager
+	worktrees map[string]string
+	merged    map[string]bool
+	gone      map[string]bool
+	dirty     map[string]bool
+	unpushed  map[string]int
+	refs      map[string]bool
+}
+
+func (f *fakePruneGitManager) ListWorktrees(path string) ([]string, error) {
+	names := make([]string, 0, len(f.worktrees))
+	for name := range f.worktrees {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakePruneGitManager) CurrentBranch(path string) (string, error) {
+	return f.worktrees[filepath.Base(path)], nil
+}
+
+func (f *fakePruneGitManager) IsBranchMerged(path, branch, target string) (bool, error) {
+	return f.merged[branch], nil
+}
+
+func (f *fakePruneGitManager) IsBranchUpstreamGone(path, branch string) (bool, error) {
+	return f.gone[branch], nil
+}
+
+func (f *fakePruneGitManager) IsWorktreeDirty(worktreePath string) (bool, error) {
+	return f.dirty[filepath.Base(worktreePath)], nil
+}
+
+func (f *fakePruneGitManager) UnpushedCommits(worktreePath string) (int, error) {
+	return f.unpushed[filepath.Base(worktreePath)], nil
+}
+
+func (f *fakePruneGitManager) RefExists(path, ref string) bool {
+	return f.refs[ref]
+}
+
+func TestFindPruneCandidates(t *testing.T) {
+	fake := &fakePruneGitManager{
+		worktrees: map[string]string{
+			"main":      "main",
+			"review":    "",
+			"merged":    "merged",
+			"gone":      "gone",
+			"stranded":  "stranded",
+			"active":    "active",
+			"dirty":     "dirty",
+			"also-main": "main",
+		},
+		merged:   map[string]bool{"main": true, "merged": true, "dirty": true},
+		gone:     map[string]bool{"gone": true, "stranded": true},
+		dirty:    map[string]bool{"dirty": true},
+		unpushed: map[string]int{"stranded": 2},
+		refs:     map[string]bool{"origin/main": true},
+	}
+
+	candidates, err := findPruneCandidates(fake, "owner/repo", "/src/owner/repo", "/src/owner/repo/.git", "main", false)
+	if err != nil {
+		t.Fatalf("findPruneCandidates() error = %v", err)
+	}
+
+	got := make(map[string]string, len(candidates))
+	for _, candidate := range candidates {
+		got[candidate.Name] = candidate.Reason
+	}
+	want := map[string]string{
+		"merged": "merged into origin/main",
+		"gone":   "upstream branch deleted",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("findPruneCandidates() = %v, want %v", got, want)
+	}
+
+	candidates, err = findPruneCandidates(fake, "owner/repo", "/src/owner/repo", "/src/owner/repo/.git", "main", true)
+	if err != nil {
+		t.Fatalf("findPruneCandidates(force) error = %v", err)
+	}
+	if len(candidates) != 4 {
+		t.Fatalf("findPruneCandidates(force) returned %d candidates, want 4", len(candidates))
+	}
+}
+
+func TestFindPruneCandidatesFallsBackToLocalDefaultBranch(t *testing.T) {
+	fake := &fakePruneGitManager{
+		worktrees: map[string]string{"merged": "merged"},
+		merged:    map[string]bool{"merged": true},
+	}
+
+	candidates, err := findPruneCandidates(fake, "owner/repo", "/src/owner/repo", "/src/owner/repo/.git", "main", false)
+	if err != nil {
+		t.Fatalf("findPruneCandidates() error = %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Reason != "merged into main" {
+		t.Fatalf("findPruneCandidates() = %+v, want merged into main", candidates)
+	}
+}
+
+func TestWorktreeSessions(t *testing.T) {
+	sessions := []string{
+		"owner/repo/feature",
+		"owner-repo/feature",
+		"work/owner/repo/feature",
+		"owner/repo/other",
+		"owner/repo-two/feature",
+	}
+
+	got := worktreeSessions(sessions, "owner/repo", "feature")
+	want := []string{"owner/repo/feature", "owner-repo/feature", "work/owner/repo/feature"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("worktreeSessions() = %v, want %v", got, want)
+	}
+}
+
+func TestPruneWorktreeDeletesUnprovableBranchesOnlyWithForce(t *testing.T) {
+	cfg, repoDir, _ := setupWorktreeLayoutFixture(t)
+	metadataPath := filepath.Join(repoDir, ".git")
+	gitMgr := git.New()
+	// Squash-merged branches: their commits never reach main, so
+	// 'git branch -d' refuses to delete them.
+	for _, name := range []string{"squashed", "forced"} {
+		path := filepath.Join(repoDir, name)
+		if err := gitMgr.CreateFeatureWorktree(metadataPath, path, name, "main"); err != nil {
+			t.Fatal(err)
+		}
+		runGitCmd(t, path, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "--allow-empty", "-m", "squashed upstream")
+	}
+
+	var zoxideRemoved []string
+	origRemove := runZoxideRemove
+	t.Cleanup(func() { runZoxideRemove = origRemove })
+	runZoxideRemove = func(path string) error { zoxideRemoved = append(zoxideRemoved, path); return nil }
+
+	wt, err := resolveLocalWorktree(cfg, gitMgr, "acme/widgets", "squashed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pruneWorktree(gitMgr, wt, false); err == nil {
+		t.Fatal("pruneWorktree() without force deleted an unmerged branch")
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Fatalf("worktree %s still exists: %v", wt.Path, err)
+	}
+	if !gitMgr.RefExists(metadataPath, "refs/heads/squashed") {
+		t.Fatal("pruneWorktree() without force deleted the branch")
+	}
+
+	wt, err = resolveLocalWorktree(cfg, gitMgr, "acme/widgets", "forced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pruneWorktree(gitMgr, wt, true); err != nil {
+		t.Fatalf("pruneWorktree(force) error = %v", err)
+	}
+	if gitMgr.RefExists(metadataPath, "refs/heads/forced") {
+		t.Fatal("branch of force-pruned worktree still exists")
+	}
+	want := []string{filepath.Join(repoDir, "squashed"), filepath.Join(repoDir, "forced")}
+	if !reflect.DeepEqual(zoxideRemoved, want) {
+		t.Fatalf("zoxide removed %q, want %q", zoxideRemoved, want)
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <repo> <worktree>",
+	Short: "Remove a worktree from a repository",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRemove,
+}
+
+var (
+	removeForce        bool
+	removeDeleteBranch bool
+
+	runZoxideRemove = func(path string) error {
+		cmd := exec.Command("zoxide", "remove", path)
+		_, err := cmd.CombinedOutput()
+		return err
+	}
+	killTmuxSession = func(session string) error {
+		cmd := exec.Command("tmux", "kill-session", "-t", session)
+		_, err := cmd.CombinedOutput()
+		return err
+	}
+)
+
+// localWorktree identifies a worktree of a locally cloned repository.
+type localWorktree struct {
+	RepoFullName string
+	RepoPath     string
+	MetadataPath string
+	Name         string
+	Path         string
+	Branch       string
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+
+	rmCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "remove even if the worktree is dirty or has unpushed commits")
+	rmCmd.Flags().BoolVar(&removeDeleteBranch, "delete-branch", false, "also delete the worktree's local branch")
+}
+
+func runRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	gitMgr := newGitManager(cfg)
+	wt, err := resolveLocalWorktree(cfg, gitMgr, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	if !removeForce {
+		if err := checkWorktreeRemovable(gitMgr, wt); err != nil {
+			return err
+		}
+	}
+
+	if err := removeLocalWorktree(gitMgr, wt, removeForce, removeDeleteBranch); err != nil {
+		return err
+	}
+	if !quiet {
+		fmt.Printf("✓ Removed worktree %s\n", wt.Path)
+	}
+	return nil
+}
+
+// resolveLocalRepo returns the root and git metadata paths of a cloned repo.
+func resolveLocalRepo(cfg *config.Config, repoInput string) (repoFullName, repoPath, metadataPath string, state existingRepoState, err error) {
+	repoFullName, ok := extractRepoFullName(repoInput)
+	if !ok {
+		return "", "", "", existingRepoMissing, fmt.Errorf("invalid repo format: %s", repoInput)
+	}
+
+	repoPath = getRepoPath(cfg, repoFullName, false, resolveDefaultBranch(repoFullName, ""))
+	if repoPath == "" {
+		return "", "", "", existingRepoMissing, fmt.Errorf("failed to resolve local path for %s", repoFullName)
+	}
+
+	state, err = detectExistingRepoState(repoPath)
+	if err != nil {
+		return "", "", "", state, err
+	}
+	switch state {
+	case existingRepoMissing:
+		return "", "", "", state, fmt.Errorf("%s is not cloned at %s", repoFullName, repoPath)
+	case existingRepoNonRepo:
+		return "", "", "", state, fmt.Errorf("destination exists but is not a git repository: %s", repoPath)
+	}
+
+	metadataPath = repoPath
+	if state == existingRepoWorktree {
+		metadataPath = filepath.Join(repoPath, ".git")
+	}
+	return repoFullName, repoPath, metadataPath, state, nil
+}
+
+func resolveLocalWorktree(cfg *config.Config, gitMgr git.GitManager, repoInput, worktreeName string) (localWorktree, error) {
+	worktreeName = strings.TrimSpace(worktreeName)
+	if worktreeName == "" {
+		return localWorktree{}, fmt.Errorf("worktree name cannot be empty")
+	}
+
+	repoFullName, repoPath, metadataPath, _, err := resolveLocalRepo(cfg, repoInput)
+	if err != nil {
+		return localWorktree{}, err
+	}
+
+	worktrees, err := gitMgr.ListWorktrees(repoPath)
+	if err != nil {
+		return localWorktree{}, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if !containsString(worktrees, worktreeName) {
+		return localWorktree{}, fmt.Errorf("worktree %q not found in %s", worktreeName, repoFullName)
+	}
+
+	return newLocalWorktree(gitMgr, repoFullName, repoPath, metadataPath, worktreeName), nil
+}
+
+func newLocalWorktree(gitMgr git.GitManager, repoFullName, repoPath, metadataPath, worktreeName string) localWorktree {
+	wt := localWorktree{
+		RepoFullName: repoFullName,
+		RepoPath:     repoPath,
+		MetadataPath: metadataPath,
+		Name:         worktreeName,
+		Path:         resolveOpenTargetPath(repoPath, worktreeName),
+	}
+	if branchName, err := gitMgr.CurrentBranch(wt.Path); err == nil {
+		wt.Branch = branchName
+	}
+	return wt
+}
+
+// checkWorktreeRemovable refuses removal when the worktree would lose work:
+// uncommitted changes or commits that exist on no remote.
+func checkWorktreeRemovable(gitMgr git.GitManager, wt localWorktree) error {
+	dirty, err := gitMgr.IsWorktreeDirty(wt.Path)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("worktree %q has uncommitted changes; use --force to remove anyway", wt.Name)
+	}
+
+	unpushed, err := gitMgr.UnpushedCommits(wt.Path)
+	if err != nil {
+		return err
+	}
+	if unpushed > 0 {
+		return fmt.Errorf("worktree %q has %d unpushed commit(s); use --force to remove anyway", wt.Name, unpushed)
+	}
+	return nil
+}
+
+// removeLocalWorktree removes the worktree, drops its zoxide and tmux
+// integrations once it is gone and then deletes its branch when deleteBranch
+// is set, so a branch git refuses to delete never leaves them behind.
+func removeLocalWorktree(gitMgr git.GitManager, wt localWorktree, force bool, deleteBranch bool) error {
+	if err := gitMgr.RemoveWorktree(wt.MetadataPath, wt.Path, force); err != nil {
+		return err
+	}
+	unregisterWorktreeIntegrations(wt, quiet)
+
+	if deleteBranch && wt.Branch != "" {
+		if err := gitMgr.DeleteBranch(wt.MetadataPath, wt.Branch, force); err != nil {
+			return fmt.Errorf("removed worktree %s but %w", wt.Path, err)
+		}
+	}
+	return nil
+}
+
+// unregisterWorktreeIntegrations drops the worktree from zoxide and kills any
+// tmux session opened for it. Both are best-effort.
+func unregisterWorktreeIntegrations(wt localWorktree, quiet bool) {
+	if absPath, err := filepath.Abs(wt.Path); err == nil {
+		if dryRun {
+			planStep("zoxide remove %s", utils.ShellQuote(absPath))
+		} else if err := runZoxideRemove(absPath); err != nil && verbose {
+			fmt.Printf("Warning: failed to remove %s from zoxide: %v\n", absPath, err)
+		}
+	}
+
+	sessions, err := listTmuxSessions()
+	if err != nil {
+		return
+	}
+	for _, session := range worktreeSessions(sessions, wt.RepoFullName, wt.Name) {
+		if dryRun {
+			planStep("tmux kill-session -t %s", utils.ShellQuote(session))
+			continue
+		}
+		if err := killTmuxSession(session); err != nil && !quiet {
+			fmt.Printf("Warning: failed to kill tmux session %s: %v\n", session, err)
+		}
+	}
+}
+
+// worktreeSessions returns the tmux sessions that the picker would show as
+// the [open] badge for this worktree.
+func worktreeSessions(sessions []string, repoFullName, worktreeName string) []string {
+	markers := repoSessionMarkers(repoFullName)
+	var matched []string
+	for _, session := range sessions {
+		session = strings.TrimSpace(session)
+		if wt, ok := extractWorktreeFromSessionWithMarkers(session, markers); ok && wt == worktreeName {
+			matched = append(matched, session)
+		}
+	}
+	return matched
+}
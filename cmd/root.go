@@ -3,8 +3,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/journal"
+	"github.com/kirksw/ezgit/internal/utils"
 	"github.com/kirksw/ezgit/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -18,12 +23,28 @@ var rootCmd = &cobra.Command{
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	RunE:          runRoot,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if !dryRun {
+			activeJournal = journal.New(journal.DefaultDir(), "ezgit "+strings.Join(os.Args[1:], " "))
+		}
+	},
 }
 
 var (
 	verbose    bool
 	configPath string
 	noOpen     bool
+	dryRun     bool
+
+	// activeJournal records mutating git operations for `ezgit undo`. It is
+	// set for real invocations only, so tests do not write to the state dir.
+	activeJournal *journal.Journal
+
+	// dryRunGitMgr is shared by every newGitManager call of a --dry-run
+	// invocation, so later steps see the clones and worktrees planned by
+	// earlier ones.
+	dryRunGitMgr   *git.DryRun
+	dryRunGitMgrMu sync.Mutex
 )
 
 func Execute() {
@@ -37,6 +58,7 @@ func init() {
 	rootCmd.SetVersionTemplate("ezgit {{.Version}}\n")
 	rootCmd.Flags().BoolP("version", "v", false, "print version and exit")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the git commands and filesystem changes instead of making them")
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "config file path (default: ./config.toml, ~/.config/ezgit/config.toml, or ~/.ezgit.toml)")
 	rootCmd.Flags().BoolVar(&noOpen, "no-open", false, "prepare repository/worktree but do not run open command")
 }
@@ -58,3 +80,47 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("usage: ezgit [owner/repo] [worktree]")
 	}
 }
+
+// newGitManager returns the git backend selected by [git].backend. The native
+// backend reads repository metadata directly and falls back to the git binary
+// for writes. Mutating calls are journaled when a journal is active, and
+// only printed with --dry-run. Configured lifecycle hooks run around them.
+func newGitManager(cfg *config.Config) git.GitManager {
+	gitMgr := git.New()
+	if cfg != nil {
+		gitMgr = git.NewWithBackend(cfg.Git.Backend)
+	}
+	if dryRun {
+		return withHooks(cfg, dryRunPlan(gitMgr))
+	}
+	if activeJournal != nil {
+		gitMgr = git.WithJournal(gitMgr, activeJournal)
+	}
+	return withHooks(cfg, gitMgr)
+}
+
+// dryRunPlan returns the manager shared by a --dry-run invocation, reading
+// through gitMgr when it is the first call.
+func dryRunPlan(gitMgr git.GitManager) *git.DryRun {
+	dryRunGitMgrMu.Lock()
+	defer dryRunGitMgrMu.Unlock()
+	if dryRunGitMgr == nil {
+		dryRunGitMgr = git.NewDryRun(gitMgr, os.Stdout)
+	}
+	return dryRunGitMgr
+}
+
+// planStep prints and records a step that --dry-run skips, such as creating
+// a directory or running the open command.
+func planStep(format string, args ...any) {
+	dryRunPlan(git.New()).Note(fmt.Sprintf(format, args...))
+}
+
+// mkdirAll creates dir, or only plans it with --dry-run.
+func mkdirAll(dir string) error {
+	if dryRun {
+		planStep("mkdir -p %s", utils.ShellQuote(dir))
+		return nil
+	}
+	return os.MkdirAll(dir, 0755)
+}
@@ -119,7 +119,8 @@ func runRootFuzzy(cfg *config.Config) error {
 
 	buildWg.Wait()
 
-	worktreeLoader := func(repo github.Repo) ([]string, error) {
+	gitMgr := newGitManager(cfg)
+	worktreeInfoLoader := func(repo github.Repo) ([]git.WorktreeInfo, error) {
 		if !localRepos[repo.FullName] {
 			return nil, nil
 		}
@@ -129,15 +130,10 @@ func runRootFuzzy(cfg *config.Config) error {
 			return nil, nil
 		}
 
-		gitMgr := git.New()
-		worktrees, err := gitMgr.ListWorktrees(repoPath)
-		if err != nil {
-			return nil, err
-		}
-		return worktrees, nil
+		return gitMgr.ListWorktreeInfo(repoPath)
 	}
 
-	result, err := ui.RunOpenFuzzySearchWithOpenedAndLoader(allRepos, localRepos, openedRepos, openedWorktrees, nil, worktreeLoader)
+	result, err := ui.RunOpenFuzzySearchWithOpenedAndInfoLoader(allRepos, localRepos, openedRepos, openedWorktrees, worktreeInfoLoader)
 	if err != nil {
 		return fmt.Errorf("cancelled: %w", err)
 	}
@@ -146,6 +142,9 @@ func runRootFuzzy(cfg *config.Config) error {
 	}
 
 	repo := result.Repo
+	if result.CheckoutPullRequest {
+		return runPullRequestSelection(cfg, repo.FullName)
+	}
 	if result.CreateWorktree {
 		if err := runCloneWithWorktreeAndBase(cfg, repo.FullName, result.SelectedWorktree, result.WorktreeBase); err != nil {
 			return err
@@ -327,7 +326,7 @@ type repoWorktreeLister interface {
 }
 
 func buildLocalRepoWorktreeMap(cfg *config.Config, allRepos []github.Repo, localRepos map[string]bool) map[string][]string {
-	return buildLocalRepoWorktreeMapWithLister(cfg, allRepos, localRepos, git.New(), defaultWorktreeLookupConcurrency)
+	return buildLocalRepoWorktreeMapWithLister(cfg, allRepos, localRepos, newGitManager(cfg), defaultWorktreeLookupConcurrency)
 }
 
 func buildLocalRepoWorktreeMapWithLister(cfg *config.Config, allRepos []github.Repo, localRepos map[string]bool, lister repoWorktreeLister, workers int) map[string][]string {
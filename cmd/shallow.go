@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var unshallowCmd = &cobra.Command{
+	Use:   "unshallow <repo>",
+	Short: "Fetch the full history of a shallow clone",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUnshallow,
+}
+
+var deepenCmd = &cobra.Command{
+	Use:   "deepen <repo>",
+	Short: "Fetch more history for a shallow clone",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDeepen,
+}
+
+var (
+	deepenDepth int
+	deepenSince string
+)
+
+// repoDeepener is the subset of git.GitManager unshallow and deepen need.
+type repoDeepener interface {
+	ShallowInfo(path string) (git.ShallowInfo, error)
+	Unshallow(path string) error
+	Deepen(path string, depth int, since string) error
+}
+
+func init() {
+	rootCmd.AddCommand(unshallowCmd, deepenCmd)
+
+	deepenCmd.Flags().IntVar(&deepenDepth, "depth", 0, "fetch this many more commits of history")
+	deepenCmd.Flags().StringVar(&deepenSince, "since", "", "fetch history back to this date (e.g. 2024-01-01 or '6 months ago')")
+}
+
+func runUnshallow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return changeRepoDepth(cfg, newGitManager(cfg), args[0], func(d repoDeepener, path string) error {
+		return d.Unshallow(path)
+	})
+}
+
+func runDeepen(cmd *cobra.Command, args []string) error {
+	since := strings.TrimSpace(deepenSince)
+	if (deepenDepth > 0) == (since != "") {
+		return fmt.Errorf("specify either --depth or --since")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return changeRepoDepth(cfg, newGitManager(cfg), args[0], func(d repoDeepener, path string) error {
+		return d.Deepen(path, deepenDepth, since)
+	})
+}
+
+// changeRepoDepth runs fetchHistory against the repo's git metadata, which
+// is the clone itself for regular clones and .git for the worktree layout,
+// and reports the depth before and after.
+func changeRepoDepth(cfg *config.Config, deepener repoDeepener, repoInput string, fetchHistory func(repoDeepener, string) error) error {
+	repoFullName, _, metadataPath, _, err := resolveLocalRepo(cfg, repoInput)
+	if err != nil {
+		return err
+	}
+
+	before, err := deepener.ShallowInfo(metadataPath)
+	if err != nil {
+		return err
+	}
+	if !before.Shallow {
+		fmt.Printf("%s already has full history\n", repoFullName)
+		return nil
+	}
+
+	if err := fetchHistory(deepener, metadataPath); err != nil {
+		return err
+	}
+
+	after, err := deepener.ShallowInfo(metadataPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: depth %d -> %s\n", repoFullName, before.Depth, describeDepth(after))
+	return nil
+}
+
+func describeDepth(info git.ShallowInfo) string {
+	if !info.Shallow {
+		return "full history"
+	}
+	return fmt.Sprintf("%d", info.Depth)
+}
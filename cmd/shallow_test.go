@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+func TestDescribeAndDeepenShallowClone(t *testing.T) {
+	sourceDir := t.TempDir()
+	runGitCmd(t, sourceDir, "init", "-b", "main")
+	runGitCmd(t, sourceDir, "config", "user.email", "test@example.com")
+	runGitCmd(t, sourceDir, "config", "user.name", "Test User")
+	for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGitCmd(t, sourceDir, "add", name)
+		runGitCmd(t, sourceDir, "commit", "-m", "add "+name)
+	}
+
+	cloneDir := t.TempDir()
+	repoPath := filepath.Join(cloneDir, "acme", "widgets")
+	runGitCmd(t, "", "clone", "--quiet", "--depth", "1", "file://"+sourceDir, repoPath)
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	gitMgr := git.New()
+
+	desc, err := describeRepo(cfg, "acme/widgets", gitMgr)
+	if err != nil {
+		t.Fatalf("describeRepo() error = %v", err)
+	}
+	if !desc.Shallow || desc.Depth != 1 {
+		t.Fatalf("shallow = %v depth = %d, want true 1", desc.Shallow, desc.Depth)
+	}
+
+	if err := changeRepoDepth(cfg, gitMgr, "acme/widgets", func(d repoDeepener, path string) error {
+		return d.Deepen(path, 1, "")
+	}); err != nil {
+		t.Fatalf("deepen error = %v", err)
+	}
+	if desc, _ = describeRepo(cfg, "acme/widgets", gitMgr); desc.Depth != 2 {
+		t.Fatalf("depth after deepen = %d, want 2", desc.Depth)
+	}
+
+	if err := changeRepoDepth(cfg, gitMgr, "acme/widgets", func(d repoDeepener, path string) error {
+		return d.Unshallow(path)
+	}); err != nil {
+		t.Fatalf("unshallow error = %v", err)
+	}
+	if desc, _ = describeRepo(cfg, "acme/widgets", gitMgr); desc.Shallow || desc.Depth != 0 {
+		t.Fatalf("after unshallow shallow = %v depth = %d, want false 0", desc.Shallow, desc.Depth)
+	}
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the state of every local clone and worktree",
+	Args:  cobra.NoArgs,
+	RunE:  runStatus,
+}
+
+var (
+	statusOrg    string
+	statusDirty  bool
+	statusBehind bool
+	statusJSON   bool
+)
+
+// repoStatus is one repository in the status dashboard.
+type repoStatus struct {
+	FullName      string           `json:"full_name"`
+	Path          string           `json:"path"`
+	Layout        string           `json:"layout"`
+	Worktrees     []worktreeStatus `json:"worktrees"`
+	StaleBranches []string         `json:"stale_branches,omitempty"`
+	Error         string           `json:"error,omitempty"`
+}
+
+type worktreeStatus struct {
+	git.WorktreeInfo
+	Unpushed int      `json:"unpushed"`
+	Issues   []string `json:"issues,omitempty"`
+}
+
+// repoStatusScanner is the subset of git.GitManager the dashboard needs.
+type repoStatusScanner interface {
+	repoWorktreeLister
+	ListWorktreeInfo(path string) ([]git.WorktreeInfo, error)
+	WorktreeStatus(path string) (git.WorktreeInfo, error)
+	UnpushedCommits(worktreePath string) (int, error)
+	StaleBranches(path string) ([]string, error)
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringVar(&statusOrg, "org", "", "only show repositories owned by this org or user")
+	statusCmd.Flags().BoolVar(&statusDirty, "dirty", false, "only show repositories with uncommitted changes")
+	statusCmd.Flags().BoolVar(&statusBehind, "behind", false, "only show repositories behind their upstream")
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "print status as JSON")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := collectCachedRepos(cache.New())
+	if err != nil {
+		return err
+	}
+	repos = filterReposByOrg(repos, statusOrg)
+
+	localRepos := utils.BuildLocalRepoMap(cfg.GetCloneDir(), repos)
+	statuses := scanRepoStatuses(cfg, repos, localRepos, newGitManager(cfg), defaultWorktreeLookupConcurrency)
+	statuses = filterRepoStatuses(statuses, statusDirty, statusBehind)
+
+	if statusJSON {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	writeRepoStatuses(os.Stdout, statuses)
+	return nil
+}
+
+func filterReposByOrg(repos []github.Repo, org string) []github.Repo {
+	org = strings.TrimSpace(org)
+	if org == "" {
+		return repos
+	}
+
+	var filtered []github.Repo
+	for _, repo := range repos {
+		owner, _, _ := strings.Cut(repo.FullName, "/")
+		if strings.EqualFold(owner, org) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// scanRepoStatuses inspects every local repo in parallel. The worktree map
+// (cheap with the native backend) decides which repos need the more expensive
+// per-worktree status scan.
+func scanRepoStatuses(cfg *config.Config, repos []github.Repo, localRepos map[string]bool, scanner repoStatusScanner, workers int) []repoStatus {
+	worktreesByRepo := buildLocalRepoWorktreeMapWithLister(cfg, repos, localRepos, scanner, workers)
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan github.Repo)
+	var wg sync.WaitGroup
+	var resultMu sync.Mutex
+	var statuses []repoStatus
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range jobs {
+				status, ok := scanRepoStatus(cfg, repo, len(worktreesByRepo[repo.FullName]) > 0, scanner)
+				if !ok {
+					continue
+				}
+				resultMu.Lock()
+				statuses = append(statuses, status)
+				resultMu.Unlock()
+			}
+		}()
+	}
+
+	for _, repo := range repos {
+		if localRepos[repo.FullName] {
+			jobs <- repo
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].FullName < statuses[j].FullName })
+	return statuses
+}
+
+func scanRepoStatus(cfg *config.Config, repo github.Repo, hasWorktrees bool, scanner repoStatusScanner) (repoStatus, bool) {
+	repoPath := getRepoPath(cfg, repo.FullName, false, repo.DefaultBranch)
+	if strings.TrimSpace(repoPath) == "" {
+		return repoStatus{}, false
+	}
+
+	state, err := detectExistingRepoState(repoPath)
+	if err != nil || state == existingRepoMissing {
+		return repoStatus{}, false
+	}
+
+	status := repoStatus{FullName: repo.FullName, Path: repoPath, Layout: repoLayout(state)}
+	metadataPath := repoPath
+	switch state {
+	case existingRepoNonRepo:
+		status.Error = "not a git repository"
+		return status, true
+	case existingRepoWorktree:
+		metadataPath = filepath.Join(repoPath, ".git")
+	case existingRepoRegular:
+		root, err := scanner.WorktreeStatus(repoPath)
+		if err != nil {
+			status.Error = err.Error()
+			return status, true
+		}
+		root.Name = "."
+		status.Worktrees = append(status.Worktrees, newWorktreeStatus(root, scanner))
+	}
+
+	if hasWorktrees {
+		infos, err := scanner.ListWorktreeInfo(repoPath)
+		if err != nil {
+			status.Error = err.Error()
+			return status, true
+		}
+		sortWorktreeInfos(infos)
+		for _, info := range infos {
+			status.Worktrees = append(status.Worktrees, newWorktreeStatus(info, scanner))
+		}
+	}
+
+	if stale, err := scanner.StaleBranches(metadataPath); err == nil {
+		status.StaleBranches = unCheckedOutBranches(stale, status.Worktrees)
+	}
+
+	return status, true
+}
+
+func newWorktreeStatus(info git.WorktreeInfo, scanner repoStatusScanner) worktreeStatus {
+	status := worktreeStatus{WorktreeInfo: info}
+	// Prunable and unreadable worktrees have no working tree to count
+	// commits in.
+	if info.Prunable || info.Error != "" {
+		status.Issues = worktreeIssues(status)
+		return status
+	}
+
+	// Ahead counts commits missing from the upstream; without an upstream,
+	// count commits that are on no remote at all.
+	if info.Upstream != "" && !info.UpstreamGone {
+		status.Unpushed = info.Ahead
+	} else if count, err := scanner.UnpushedCommits(info.Path); err == nil {
+		status.Unpushed = count
+	}
+
+	status.Issues = worktreeIssues(status)
+	return status
+}
+
+func worktreeIssues(status worktreeStatus) []string {
+	var issues []string
+	if status.Prunable {
+		issues = append(issues, "missing worktree directory (prunable)")
+	}
+	if status.Error != "" {
+		issues = append(issues, "unreadable: "+status.Error)
+	}
+	if status.IsDirty() {
+		issues = append(issues, fmt.Sprintf("uncommitted changes (%d modified, %d untracked)", status.Dirty, status.Untracked))
+	}
+	if status.Unpushed > 0 {
+		issues = append(issues, fmt.Sprintf("%d unpushed commit(s)", status.Unpushed))
+	}
+	if status.Behind > 0 {
+		issues = append(issues, fmt.Sprintf("%d commit(s) behind %s", status.Behind, status.Upstream))
+	}
+	// The review worktree is detached by design.
+	if status.Detached && status.Name != "review" {
+		issues = append(issues, "detached HEAD at "+status.ShortHead())
+	}
+	if status.UpstreamGone {
+		issues = append(issues, "upstream branch "+status.Upstream+" was deleted")
+	}
+	return issues
+}
+
+// unCheckedOutBranches drops stale branches that are checked out in a
+// worktree; those are already reported as worktree issues.
+func unCheckedOutBranches(branches []string, worktrees []worktreeStatus) []string {
+	checkedOut := make(map[string]bool, len(worktrees))
+	for _, wt := range worktrees {
+		checkedOut[wt.Branch] = true
+	}
+
+	var result []string
+	for _, branch := range branches {
+		if !checkedOut[branch] {
+			result = append(result, branch)
+		}
+	}
+	return result
+}
+
+func filterRepoStatuses(statuses []repoStatus, dirtyOnly, behindOnly bool) []repoStatus {
+	if !dirtyOnly && !behindOnly {
+		return statuses
+	}
+
+	var filtered []repoStatus
+	for _, status := range statuses {
+		for _, wt := range status.Worktrees {
+			if (dirtyOnly && wt.IsDirty()) || (behindOnly && wt.Behind > 0) {
+				filtered = append(filtered, status)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func (s repoStatus) needsAttention() bool {
+	if s.Error != "" || len(s.StaleBranches) > 0 {
+		return true
+	}
+	for _, wt := range s.Worktrees {
+		if len(wt.Issues) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func writeRepoStatuses(w io.Writer, statuses []repoStatus) {
+	if len(statuses) == 0 {
+		fmt.Fprintln(w, "No local repositories found")
+		return
+	}
+
+	attention := 0
+	for _, status := range statuses {
+		if !status.needsAttention() {
+			fmt.Fprintf(w, "✓ %s\n", status.FullName)
+			continue
+		}
+
+		attention++
+		fmt.Fprintf(w, "✗ %s\n", status.FullName)
+		if status.Error != "" {
+			fmt.Fprintf(w, "    error: %s\n", status.Error)
+		}
+		for _, wt := range status.Worktrees {
+			if len(wt.Issues) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "    %s [%s]: %s\n", wt.Name, worktreeRef(wt.WorktreeInfo), strings.Join(wt.Issues, ", "))
+		}
+		if len(status.StaleBranches) > 0 {
+			fmt.Fprintf(w, "    stale branches: %s\n", strings.Join(status.StaleBranches, ", "))
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d repositories, %d need attention\n", len(statuses), attention)
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/github"
+)
+
+func TestWorktreeIssues(t *testing.T) {
+	status := worktreeStatus{
+		WorktreeInfo: git.WorktreeInfo{
+			Name:         "feature",
+			Dirty:        1,
+			Behind:       2,
+			Upstream:     "origin/feature",
+			UpstreamGone: true,
+		},
+		Unpushed: 3,
+	}
+	want := []string{
+		"uncommitted changes (1 modified, 0 untracked)",
+		"3 unpushed commit(s)",
+		"2 commit(s) behind origin/feature",
+		"upstream branch origin/feature was deleted",
+	}
+	if got := worktreeIssues(status); !reflect.DeepEqual(got, want) {
+		t.Fatalf("worktreeIssues() = %v, want %v", got, want)
+	}
+
+	review := worktreeStatus{WorktreeInfo: git.WorktreeInfo{Name: "review", Detached: true, Head: "0123456789"}}
+	if got := worktreeIssues(review); len(got) != 0 {
+		t.Fatalf("review worktree issues = %v, want none", got)
+	}
+	review.Name = "scratch"
+	if got := worktreeIssues(review); !reflect.DeepEqual(got, []string{"detached HEAD at 0123456"}) {
+		t.Fatalf("detached worktree issues = %v", got)
+	}
+}
+
+type unreadableStatusScanner struct {
+	repoStatusScanner
+	t *testing.T
+}
+
+func (s unreadableStatusScanner) UnpushedCommits(worktreePath string) (int, error) {
+	s.t.Fatalf("UnpushedCommits(%q) called for an unreadable worktree", worktreePath)
+	return 0, nil
+}
+
+func TestNewWorktreeStatusReportsUnreadableWorktree(t *testing.T) {
+	info := git.WorktreeInfo{Name: "broken", Path: "/src/acme/widgets/broken", Branch: "broken", Error: "permission denied"}
+	status := newWorktreeStatus(info, unreadableStatusScanner{t: t})
+	if !reflect.DeepEqual(status.Issues, []string{"unreadable: permission denied"}) {
+		t.Fatalf("issues = %v, want the read error", status.Issues)
+	}
+	repo := repoStatus{FullName: "acme/widgets", Worktrees: []worktreeStatus{status}}
+	if !repo.needsAttention() {
+		t.Fatal("repo with an unreadable worktree does not need attention")
+	}
+}
+
+func TestFilterRepoStatuses(t *testing.T) {
+	statuses := []repoStatus{
+		{FullName: "acme/clean", Worktrees: []worktreeStatus{{}}},
+		{FullName: "acme/dirty", Worktrees: []worktreeStatus{{WorktreeInfo: git.WorktreeInfo{Untracked: 1}}}},
+		{FullName: "acme/behind", Worktrees: []worktreeStatus{{WorktreeInfo: git.WorktreeInfo{Behind: 1}}}},
+	}
+
+	names := func(statuses []repoStatus) []string {
+		var result []string
+		for _, status := range statuses {
+			result = append(result, status.FullName)
+		}
+		return result
+	}
+
+	if got := names(filterRepoStatuses(statuses, false, false)); len(got) != 3 {
+		t.Fatalf("unfiltered = %v", got)
+	}
+	if got := names(filterRepoStatuses(statuses, true, false)); !reflect.DeepEqual(got, []string{"acme/dirty"}) {
+		t.Fatalf("--dirty = %v", got)
+	}
+	if got := names(filterRepoStatuses(statuses, true, true)); !reflect.DeepEqual(got, []string{"acme/dirty", "acme/behind"}) {
+		t.Fatalf("--dirty --behind = %v", got)
+	}
+}
+
+func TestFilterReposByOrg(t *testing.T) {
+	repos := []github.Repo{{FullName: "Acme/api"}, {FullName: "other/api"}}
+	got := filterReposByOrg(repos, "acme")
+	if len(got) != 1 || got[0].FullName != "Acme/api" {
+		t.Fatalf("filterReposByOrg() = %v", got)
+	}
+}
+
+func TestScanRepoStatusesRegularAndWorktreeLayouts(t *testing.T) {
+	tmpDir := t.TempDir()
+	cloneDir := filepath.Join(tmpDir, "src")
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+
+	runGitCmd(t, "", "init", "--bare", originDir)
+	runGitCmd(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, "", "clone", originDir, seedDir)
+	runGitCmd(t, seedDir, "config", "user.email", "test@example.com")
+	runGitCmd(t, seedDir, "config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGitCmd(t, seedDir, "add", "README.md")
+	runGitCmd(t, seedDir, "commit", "-m", "initial commit")
+	runGitCmd(t, seedDir, "push", "origin", "HEAD:main")
+	runGitCmd(t, seedDir, "push", "origin", "HEAD:feature")
+	runGitCmd(t, seedDir, "push", "origin", "HEAD:old")
+
+	// Regular clone with an untracked file and a branch whose upstream is gone.
+	regularDir := filepath.Join(cloneDir, "acme", "regular")
+	runGitCmd(t, "", "clone", originDir, regularDir)
+	runGitCmd(t, regularDir, "branch", "--track", "old", "origin/old")
+	if err := os.WriteFile(filepath.Join(regularDir, "scratch.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bare layout with a feature worktree tracking a branch that gets deleted.
+	bareRepoDir := filepath.Join(cloneDir, "acme", "bare")
+	metadataDir := filepath.Join(bareRepoDir, ".git")
+	runGitCmd(t, "", "clone", "--bare", originDir, metadataDir)
+	runGitCmd(t, metadataDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	runGitCmd(t, metadataDir, "fetch", "origin")
+	runGitCmd(t, metadataDir, "worktree", "add", filepath.Join(bareRepoDir, "main"), "main")
+	runGitCmd(t, metadataDir, "worktree", "add", "--detach", filepath.Join(bareRepoDir, "review"), "origin/main")
+	runGitCmd(t, metadataDir, "worktree", "add", "-b", "feature-local", filepath.Join(bareRepoDir, "feature"), "origin/feature")
+	runGitCmd(t, metadataDir, "branch", "--set-upstream-to", "origin/feature", "feature-local")
+
+	runGitCmd(t, seedDir, "push", "origin", "--delete", "feature", "old")
+	runGitCmd(t, regularDir, "fetch", "--prune")
+	runGitCmd(t, metadataDir, "fetch", "--prune")
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	repos := []github.Repo{{FullName: "acme/regular"}, {FullName: "acme/bare"}, {FullName: "acme/missing"}}
+	localRepos := map[string]bool{"acme/regular": true, "acme/bare": true}
+
+	statuses := scanRepoStatuses(cfg, repos, localRepos, git.New(), 2)
+	if len(statuses) != 2 || statuses[0].FullName != "acme/bare" || statuses[1].FullName != "acme/regular" {
+		t.Fatalf("scanRepoStatuses() = %+v", statuses)
+	}
+
+	bare := statuses[0]
+	if bare.Layout != "worktree" || len(bare.Worktrees) != 3 {
+		t.Fatalf("bare status = %+v", bare)
+	}
+	for _, wt := range bare.Worktrees {
+		switch wt.Name {
+		case "feature":
+			if !wt.UpstreamGone || len(wt.Issues) != 1 {
+				t.Fatalf("feature worktree = %+v, want only upstream gone", wt)
+			}
+		default:
+			if len(wt.Issues) != 0 {
+				t.Fatalf("%s worktree issues = %v, want none", wt.Name, wt.Issues)
+			}
+		}
+	}
+	if len(bare.StaleBranches) != 0 {
+		t.Fatalf("bare stale branches = %v, want none (feature-local is checked out)", bare.StaleBranches)
+	}
+
+	regular := statuses[1]
+	if regular.Layout != "regular" || len(regular.Worktrees) != 1 || regular.Worktrees[0].Name != "." {
+		t.Fatalf("regular status = %+v", regular)
+	}
+	if regular.Worktrees[0].Untracked != 1 || regular.Worktrees[0].Branch != "main" {
+		t.Fatalf("regular root = %+v", regular.Worktrees[0])
+	}
+	if !reflect.DeepEqual(regular.StaleBranches, []string{"old"}) {
+		t.Fatalf("regular stale branches = %v, want [old]", regular.StaleBranches)
+	}
+
+	var buf bytes.Buffer
+	writeRepoStatuses(&buf, statuses)
+	output := buf.String()
+	for _, want := range []string{"✗ acme/bare", "feature [feature-local]: upstream branch origin/feature was deleted", "stale branches: old", "2 repositories, 2 need attention"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("output missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/kirksw/ezgit/internal/cache"
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [repo...]",
+	Short: "Fetch and fast-forward local repositories and worktrees",
+	RunE:  runSync,
+}
+
+var (
+	syncAll  bool
+	syncJSON bool
+	syncJobs int
+)
+
+const (
+	syncUpdated    = "updated"
+	syncUpToDate   = "up-to-date"
+	syncSkipped    = "skipped"
+	syncNoUpstream = "no-upstream"
+	syncDetached   = "detached"
+	syncFailed     = "failed"
+)
+
+type repoSyncResult struct {
+	FullName  string               `json:"full_name"`
+	Path      string               `json:"path,omitempty"`
+	Error     string               `json:"error,omitempty"`
+	Worktrees []worktreeSyncResult `json:"worktrees,omitempty"`
+}
+
+type worktreeSyncResult struct {
+	Name    string `json:"name"`
+	Branch  string `json:"branch,omitempty"`
+	Result  string `json:"result"`
+	Reason  string `json:"reason,omitempty"`
+	Commits int    `json:"commits,omitempty"`
+}
+
+// repoSyncer is the subset of git.GitManager sync needs.
+type repoSyncer interface {
+	FetchPrune(path string) error
+	FastForward(worktreePath string) error
+	ListWorktreeInfo(path string) ([]git.WorktreeInfo, error)
+	WorktreeStatus(path string) (git.WorktreeInfo, error)
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVar(&syncAll, "all", false, "sync every locally cloned repository")
+	syncCmd.Flags().BoolVar(&syncJSON, "json", false, "print results as JSON")
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", defaultWorktreeLookupConcurrency, "number of repositories to sync in parallel")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if syncAll == (len(args) > 0) {
+		return fmt.Errorf("specify repositories or --all")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repoInputs := args
+	if syncAll {
+		repos, err := collectCachedRepos(cache.New())
+		if err != nil {
+			return err
+		}
+		repoInputs = sortedRepoNames(repos, utils.BuildLocalRepoMap(cfg.GetCloneDir(), repos), true)
+	}
+
+	results := syncRepos(cfg, repoInputs, newGitManager(cfg), syncJobs)
+
+	if syncJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode sync results: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		writeSyncTable(os.Stdout, results)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to sync %d repositories", failed)
+	}
+	return nil
+}
+
+func syncRepos(cfg *config.Config, repoInputs []string, syncer repoSyncer, workers int) []repoSyncResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	var resultMu sync.Mutex
+	results := make([]repoSyncResult, 0, len(repoInputs))
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repoInput := range jobs {
+				result := syncRepo(cfg, repoInput, syncer)
+				resultMu.Lock()
+				results = append(results, result)
+				resultMu.Unlock()
+			}
+		}()
+	}
+
+	for _, repoInput := range repoInputs {
+		jobs <- repoInput
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].FullName < results[j].FullName })
+	return results
+}
+
+func syncRepo(cfg *config.Config, repoInput string, syncer repoSyncer) repoSyncResult {
+	repoFullName, repoPath, metadataPath, state, err := resolveLocalRepo(cfg, repoInput)
+	if err != nil {
+		return repoSyncResult{FullName: repoInput, Error: err.Error()}
+	}
+	result := repoSyncResult{FullName: repoFullName, Path: repoPath}
+
+	if err := syncer.FetchPrune(metadataPath); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var infos []git.WorktreeInfo
+	if state == existingRepoRegular {
+		root, err := syncer.WorktreeStatus(repoPath)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		root.Name = "."
+		infos = append(infos, root)
+	}
+	linked, err := syncer.ListWorktreeInfo(repoPath)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	sortWorktreeInfos(linked)
+	infos = append(infos, linked...)
+
+	for _, info := range infos {
+		result.Worktrees = append(result.Worktrees, syncWorktree(info, syncer))
+	}
+	return result
+}
+
+// syncWorktree fast-forwards one worktree when it is safe: on a branch with a
+// live upstream, clean, and not ahead of the upstream.
+func syncWorktree(info git.WorktreeInfo, syncer repoSyncer) worktreeSyncResult {
+	result := worktreeSyncResult{Name: info.Name, Branch: info.Branch}
+	switch {
+	case info.Prunable:
+		result.Result, result.Reason = syncSkipped, "worktree directory is missing"
+	case info.Error != "":
+		result.Result, result.Reason = syncSkipped, info.Error
+	case info.Branch == "":
+		result.Result = syncDetached
+	case info.Upstream == "":
+		result.Result = syncNoUpstream
+	case info.UpstreamGone:
+		result.Result, result.Reason = syncSkipped, "upstream branch was deleted"
+	case info.Behind == 0:
+		result.Result = syncUpToDate
+	case info.IsDirty():
+		result.Result, result.Reason = syncSkipped, "uncommitted changes"
+	case info.Ahead > 0:
+		result.Result, result.Reason = syncSkipped, fmt.Sprintf("diverged from %s (%d ahead, %d behind)", info.Upstream, info.Ahead, info.Behind)
+	default:
+		if err := syncer.FastForward(info.Path); err != nil {
+			result.Result, result.Reason = syncFailed, err.Error()
+		} else {
+			result.Result, result.Commits = syncUpdated, info.Behind
+		}
+	}
+	return result
+}
+
+func writeSyncTable(w io.Writer, results []repoSyncResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPO\tWORKTREE\tBRANCH\tRESULT")
+
+	updated, attention := 0, 0
+	for _, repo := range results {
+		if repo.Error != "" {
+			attention++
+			fmt.Fprintf(tw, "%s\t-\t-\t%s: %s\n", repo.FullName, syncFailed, firstErrorLine(repo.Error))
+			continue
+		}
+
+		for _, wt := range repo.Worktrees {
+			status := wt.Result
+			switch wt.Result {
+			case syncUpdated:
+				updated++
+				status = fmt.Sprintf("%s (+%d)", wt.Result, wt.Commits)
+			case syncSkipped, syncFailed:
+				attention++
+				status = fmt.Sprintf("%s: %s", wt.Result, firstErrorLine(wt.Reason))
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", repo.FullName, wt.Name, wt.Branch, status)
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%d repositories, %d worktrees updated, %d need attention\n", len(results), updated, attention)
+}
+
+// firstErrorLine trims git's multi-line command output from an error for
+// table display; --json keeps the full text.
+func firstErrorLine(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return line
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+type fakeSyncer struct {
+	repoSyncer
+	fastForwarded []string
+}
+
+func (f *fakeSyncer) FastForward(worktreePath string) error {
+	f.fastForwarded = append(f.fastForwarded, worktreePath)
+	return nil
+}
+
+func TestSyncWorktreeDecisions(t *testing.T) {
+	cases := []struct {
+		name   string
+		info   git.WorktreeInfo
+		result string
+		reason string
+	}{
+		{"detached", git.WorktreeInfo{Name: "review", Detached: true}, syncDetached, ""},
+		{"no upstream", git.WorktreeInfo{Name: "local", Branch: "local"}, syncNoUpstream, ""},
+		{"gone", git.WorktreeInfo{Name: "gone", Branch: "gone", Upstream: "origin/gone", UpstreamGone: true}, syncSkipped, "upstream branch was deleted"},
+		{"current", git.WorktreeInfo{Name: "main", Branch: "main", Upstream: "origin/main"}, syncUpToDate, ""},
+		{"dirty", git.WorktreeInfo{Name: "main", Branch: "main", Upstream: "origin/main", Behind: 1, Dirty: 1}, syncSkipped, "uncommitted changes"},
+		{"diverged", git.WorktreeInfo{Name: "main", Branch: "main", Upstream: "origin/main", Behind: 1, Ahead: 2}, syncSkipped, "diverged from origin/main (2 ahead, 1 behind)"},
+		{"unreadable", git.WorktreeInfo{Name: "main", Branch: "main", Upstream: "origin/main", Error: "git status failed"}, syncSkipped, "git status failed"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			syncer := &fakeSyncer{}
+			got := syncWorktree(tc.info, syncer)
+			if got.Result != tc.result || got.Reason != tc.reason {
+				t.Fatalf("syncWorktree() = %s %q, want %s %q", got.Result, got.Reason, tc.result, tc.reason)
+			}
+			if len(syncer.fastForwarded) != 0 {
+				t.Fatalf("unexpected fast-forward of %v", syncer.fastForwarded)
+			}
+		})
+	}
+
+	syncer := &fakeSyncer{}
+	got := syncWorktree(git.WorktreeInfo{Name: "main", Path: "/src/main", Branch: "main", Upstream: "origin/main", Behind: 3}, syncer)
+	if got.Result != syncUpdated || got.Commits != 3 || len(syncer.fastForwarded) != 1 {
+		t.Fatalf("syncWorktree() = %+v, fast-forwarded %v", got, syncer.fastForwarded)
+	}
+}
+
+func TestSyncReposFastForwardsCleanWorktrees(t *testing.T) {
+	tmpDir := t.TempDir()
+	cloneDir := filepath.Join(tmpDir, "src")
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+
+	commit := func(dir, name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGitCmd(t, dir, "add", name)
+		runGitCmd(t, dir, "commit", "-m", "add "+name)
+	}
+
+	runGitCmd(t, "", "init", "--bare", originDir)
+	runGitCmd(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGitCmd(t, "", "clone", originDir, seedDir)
+	runGitCmd(t, seedDir, "config", "user.email", "test@example.com")
+	runGitCmd(t, seedDir, "config", "user.name", "test")
+	commit(seedDir, "README.md")
+	runGitCmd(t, seedDir, "push", "origin", "HEAD:main")
+	runGitCmd(t, seedDir, "push", "origin", "HEAD:dev")
+
+	repoDir := filepath.Join(cloneDir, "acme", "widgets")
+	runGitCmd(t, "", "clone", originDir, repoDir)
+	devPath := filepath.Join(tmpDir, "dev")
+	runGitCmd(t, repoDir, "worktree", "add", "--track", "-b", "dev", devPath, "origin/dev")
+	if err := os.WriteFile(filepath.Join(devPath, "scratch.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	commit(seedDir, "new.txt")
+	runGitCmd(t, seedDir, "push", "origin", "HEAD:main", "HEAD:dev")
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	results := syncRepos(cfg, []string{"acme/widgets", "acme/missing"}, git.New(), 2)
+	if len(results) != 2 {
+		t.Fatalf("syncRepos() = %+v", results)
+	}
+	if results[0].FullName != "acme/missing" || results[0].Error == "" {
+		t.Fatalf("missing repo result = %+v, want error", results[0])
+	}
+
+	widgets := results[1]
+	if widgets.Error != "" || len(widgets.Worktrees) != 2 {
+		t.Fatalf("widgets result = %+v", widgets)
+	}
+	if root := widgets.Worktrees[0]; root.Name != "." || root.Result != syncUpdated || root.Commits != 1 {
+		t.Fatalf("root result = %+v, want updated by 1", root)
+	}
+	if dev := widgets.Worktrees[1]; dev.Name != "dev" || dev.Result != syncSkipped || dev.Reason != "uncommitted changes" {
+		t.Fatalf("dev result = %+v, want skipped", dev)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "new.txt")); err != nil {
+		t.Fatalf("root worktree was not fast-forwarded: %v", err)
+	}
+
+	var buf bytes.Buffer
+	writeSyncTable(&buf, results)
+	for _, want := range []string{"updated (+1)", "skipped: uncommitted changes", "2 repositories, 1 worktrees updated, 2 need attention"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("table missing %q:\n%s", want, buf.String())
+		}
+	}
+}
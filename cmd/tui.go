@@ -87,7 +87,7 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		}
 		if result.Convert {
 			repoPath := getRepoPath(cfg, result.Repo.FullName, false, result.Repo.DefaultBranch)
-			return runConvertPath(repoPath, result.Repo.DefaultBranch)
+			return runConvertPath(cfg, repoPath, result.Repo.DefaultBranch)
 		}
 		return runOpenRepoSelection(cfg, result.Repo, localOnly, localRepos, "")
 	case ui.HubModeConnect:
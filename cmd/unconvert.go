@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/ui"
+	"github.com/kirksw/ezgit/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var unconvertCmd = &cobra.Command{
+	Use:   "unconvert <path>",
+	Short: "Convert a bare repository with worktrees back to a regular clone",
+	Long: `Convert a bare repository with worktrees back to a regular clone.
+
+One worktree becomes the main checkout, keeping its branch, staged changes and
+untracked files. The other worktree directories are deleted after
+confirmation; dirty ones block the conversion unless --force is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUnconvert,
+}
+
+var (
+	unconvertWorktree string
+	unconvertForce    bool
+	unconvertYes      bool
+)
+
+func init() {
+	rootCmd.AddCommand(unconvertCmd)
+
+	unconvertCmd.Flags().StringVarP(&unconvertWorktree, "worktree", "w", "", "worktree to keep as the main checkout")
+	unconvertCmd.Flags().BoolVarP(&unconvertForce, "force", "f", false, "delete other worktrees even if they have uncommitted changes")
+	unconvertCmd.Flags().BoolVarP(&unconvertYes, "yes", "y", false, "do not ask for confirmation")
+}
+
+func runUnconvert(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return runUnconvertPath(newGitManager(cfg), args[0], unconvertWorktree, unconvertForce, unconvertYes)
+}
+
+func runUnconvertPath(gitMgr git.GitManager, repoPath, keep string, force, yes bool) error {
+	if err := utils.ValidatePath(repoPath); err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+	absRepoPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+	repoPath = absRepoPath
+
+	state, err := detectExistingRepoState(repoPath)
+	if err != nil {
+		return err
+	}
+	if state != existingRepoWorktree {
+		return fmt.Errorf("%s is not a bare repository with worktrees", repoPath)
+	}
+
+	infos, err := gitMgr.ListWorktreeInfo(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	// Only worktrees inside the repo directory become the checkout or get
+	// deleted; ones checked out elsewhere stay registered with the regular
+	// clone. External worktrees are named by their base name, so the path
+	// decides.
+	var worktrees []string
+	for _, info := range infos {
+		if strings.HasPrefix(info.Path, repoPath+string(filepath.Separator)) {
+			worktrees = append(worktrees, info.Name)
+		}
+	}
+	keep, err = selectUnconvertWorktree(worktrees, keep)
+	if err != nil || keep == "" {
+		return err
+	}
+
+	var removed, dirty []string
+	for _, name := range worktrees {
+		if name == keep {
+			continue
+		}
+		removed = append(removed, name)
+		isDirty, err := gitMgr.IsWorktreeDirty(filepath.Join(repoPath, name))
+		if err != nil {
+			return err
+		}
+		if isDirty {
+			dirty = append(dirty, name)
+		}
+	}
+	if len(dirty) > 0 && !force {
+		return fmt.Errorf("worktree(s) with uncommitted changes: %s; use --force to delete them anyway", strings.Join(dirty, ", "))
+	}
+	others, err := unconvertOtherEntries(repoPath, worktrees)
+	if err != nil {
+		return err
+	}
+
+	if len(removed) > 0 || len(others) > 0 {
+		if len(removed) > 0 {
+			fmt.Println("Worktrees to delete:")
+			for _, name := range removed {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+		if len(others) > 0 {
+			fmt.Println("Other files to delete:")
+			for _, name := range others {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+		if !yes {
+			if !isInteractiveStdin() {
+				return fmt.Errorf("refusing to delete worktrees without confirmation; re-run with --yes")
+			}
+			fmt.Printf("Delete %d worktree(s) and %d other file(s) and keep %s as the checkout? [y/n]: ", len(removed), len(others), keep)
+			input, err := readLineTrimmed(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read confirmation: %w", err)
+			}
+			if confirmed, ok := parseYesNoRequired(input); !ok || !confirmed {
+				return fmt.Errorf("cancelled")
+			}
+		}
+	}
+
+	fmt.Printf("Converting %s to a regular clone with %s checked out...\n", repoPath, keep)
+	if err := gitMgr.ConvertToRegular(repoPath, filepath.Join(repoPath, keep)); err != nil {
+		return fmt.Errorf("failed to convert to regular clone: %w", err)
+	}
+	fmt.Println("✓ Successfully converted to regular clone")
+	return nil
+}
+
+// unconvertOtherEntries lists the top-level entries of the repo directory
+// that are neither .git nor hold a worktree. The conversion deletes the old
+// directory with everything in it, so these go too.
+func unconvertOtherEntries(repoPath string, worktrees []string) ([]string, error) {
+	holdsWorktree := make(map[string]bool, len(worktrees))
+	for _, name := range worktrees {
+		top, _, _ := strings.Cut(filepath.ToSlash(name), "/")
+		holdsWorktree[top] = true
+	}
+
+	entries, err := os.ReadDir(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", repoPath, err)
+	}
+	var others []string
+	for _, entry := range entries {
+		if entry.Name() == ".git" || holdsWorktree[entry.Name()] {
+			continue
+		}
+		others = append(others, entry.Name())
+	}
+	return others, nil
+}
+
+// selectUnconvertWorktree returns the worktree to keep: the --worktree flag,
+// the only worktree, or a picker choice. An empty result means cancelled.
+func selectUnconvertWorktree(worktrees []string, keep string) (string, error) {
+	keep = strings.TrimSpace(keep)
+	if keep != "" {
+		if !containsString(worktrees, keep) {
+			return "", fmt.Errorf("worktree %q not found", keep)
+		}
+		return keep, nil
+	}
+
+	switch {
+	case len(worktrees) == 0:
+		return "", fmt.Errorf("no worktrees to keep; create one first")
+	case len(worktrees) == 1:
+		return worktrees[0], nil
+	case !isInteractiveStdin():
+		return "", fmt.Errorf("specify the worktree to keep with --worktree")
+	}
+
+	selected, cancelled, err := ui.RunPicker(sortedStrings(worktrees), ui.PickerLabels{
+		Title:       "Select worktree to keep",
+		Placeholder: "Filter worktrees...",
+		Empty:       "No worktrees",
+		Action:      "keep",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to select worktree: %w", err)
+	}
+	if cancelled {
+		return "", nil
+	}
+	return selected, nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+func TestUnconvertRefusesDirtyWorktreesWithoutForce(t *testing.T) {
+	_, repoDir, _ := setupWorktreeLayoutFixture(t)
+	gitMgr := git.New()
+	metadataPath := filepath.Join(repoDir, ".git")
+	scratchPath := filepath.Join(repoDir, "scratch")
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, scratchPath, "scratch", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(scratchPath, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runUnconvertPath(gitMgr, repoDir, "main", false, true)
+	if err == nil || !strings.Contains(err.Error(), "scratch") {
+		t.Fatalf("expected dirty worktree error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(scratchPath, "wip.txt")); err != nil {
+		t.Fatalf("dirty worktree was touched: %v", err)
+	}
+
+	if err := runUnconvertPath(gitMgr, repoDir, "main", true, true); err != nil {
+		t.Fatalf("runUnconvertPath(force) error = %v", err)
+	}
+	if got := strings.TrimSpace(runGitCmd(t, repoDir, "rev-parse", "--abbrev-ref", "HEAD")); got != "main" {
+		t.Fatalf("HEAD = %q, want main", got)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "README.md")); err != nil {
+		t.Fatalf("README.md missing from checkout: %v", err)
+	}
+	if _, err := os.Stat(scratchPath); !os.IsNotExist(err) {
+		t.Fatalf("scratch worktree still exists (err=%v)", err)
+	}
+	state, err := detectExistingRepoState(repoDir)
+	if err != nil || state != existingRepoRegular {
+		t.Fatalf("state = %v (err=%v), want regular", state, err)
+	}
+}
+
+func TestSelectUnconvertWorktree(t *testing.T) {
+	if got, err := selectUnconvertWorktree([]string{"main"}, ""); err != nil || got != "main" {
+		t.Fatalf("single worktree = %q, %v", got, err)
+	}
+	if _, err := selectUnconvertWorktree([]string{"main"}, "missing"); err == nil {
+		t.Fatal("expected error for unknown worktree")
+	}
+	if _, err := selectUnconvertWorktree(nil, ""); err == nil {
+		t.Fatal("expected error when there are no worktrees")
+	}
+}
+
+func TestUnconvertKeepsExternalWorktreesAndListsOtherFiles(t *testing.T) {
+	_, repoDir, _ := setupWorktreeLayoutFixture(t)
+	gitMgr := git.New()
+	metadataPath := filepath.Join(repoDir, ".git")
+	externalPath := filepath.Join(t.TempDir(), "elsewhere")
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, externalPath, "elsewhere", "main"); err != nil {
+		t.Fatal(err)
+	}
+	nestedPath := filepath.Join(repoDir, "feature", "nested")
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, nestedPath, "nested", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "notes.txt"), []byte("notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	others, err := unconvertOtherEntries(repoDir, []string{"main", filepath.Join("feature", "nested")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(others) != 1 || others[0] != "notes.txt" {
+		t.Fatalf("unconvertOtherEntries() = %v, want [notes.txt]", others)
+	}
+
+	if err := runUnconvertPath(gitMgr, repoDir, "elsewhere", false, true); err == nil {
+		t.Fatal("runUnconvertPath() accepted a worktree outside the repo as the checkout")
+	}
+	if err := runUnconvertPath(gitMgr, repoDir, "main", false, true); err != nil {
+		t.Fatalf("runUnconvertPath() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(externalPath, "README.md")); err != nil {
+		t.Fatalf("external worktree was touched: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "feature")); !os.IsNotExist(err) {
+		t.Fatalf("nested worktree still exists (err=%v)", err)
+	}
+	if got := runGitCmd(t, repoDir, "worktree", "list"); !strings.Contains(got, externalPath) {
+		t.Fatalf("external worktree no longer registered:\n%s", got)
+	}
+}
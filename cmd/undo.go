@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/journal"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [id]",
+	Short: "Revert an operation recorded in the journal",
+	Long: `Revert an ezgit operation recorded in the journal, the most recent one by
+default.
+
+Deleted branches are restored at their recorded commits, removed worktrees are
+re-created, added worktrees are removed (unless they have uncommitted changes)
+and conversions are reversed. Uncommitted changes in a removed worktree cannot
+be brought back. See 'ezgit history' for entry ids.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent operations recorded in the journal",
+	Args:  cobra.NoArgs,
+	RunE:  runHistory,
+}
+
+var (
+	historyLimit int
+	historyJSON  bool
+)
+
+// actionUndoer is the subset of git.GitManager undo needs.
+type actionUndoer interface {
+	UndoAction(action journal.Action) error
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd, historyCmd)
+
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "number of entries to show (0 for all)")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "print entries as JSON")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	id := ""
+	if len(args) == 1 {
+		id = args[0]
+	}
+	return undoEntry(journal.New(journal.DefaultDir(), ""), newGitManager(cfg), id)
+}
+
+func undoEntry(j *journal.Journal, undoer actionUndoer, id string) error {
+	entry, err := findUndoEntry(j, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Undoing %s: %s\n", entry.ID, entry.Command)
+	for i := len(entry.Actions) - 1; i >= 0; i-- {
+		action := entry.Actions[i]
+		if err := undoer.UndoAction(action); err != nil {
+			return fmt.Errorf("failed to undo %q: %w\nSteps listed above were reverted; fix the problem and run 'ezgit undo %s' again", action.Describe(), err, entry.ID)
+		}
+		if !dryRun {
+			fmt.Printf("  ✓ reverted: %s\n", action.Describe())
+		}
+	}
+
+	// A dry run reverted nothing, so the entry stays available to undo.
+	if dryRun {
+		return nil
+	}
+	if err := j.MarkUndone(entry.ID); err != nil {
+		return fmt.Errorf("reverted, but failed to update the journal: %w", err)
+	}
+	return nil
+}
+
+// findUndoEntry returns the entry with id, or the newest one not yet undone.
+func findUndoEntry(j *journal.Journal, id string) (journal.Entry, error) {
+	if id != "" {
+		entry, err := j.Get(id)
+		if err != nil {
+			return journal.Entry{}, err
+		}
+		if entry.UndoneAt != nil {
+			return journal.Entry{}, fmt.Errorf("%s was already undone at %s", entry.ID, entry.UndoneAt.Format("2006-01-02 15:04"))
+		}
+		return entry, nil
+	}
+
+	entries, err := j.List()
+	if err != nil {
+		return journal.Entry{}, err
+	}
+	for _, entry := range entries {
+		if entry.UndoneAt == nil {
+			return entry, nil
+		}
+	}
+	return journal.Entry{}, fmt.Errorf("nothing to undo")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	entries, err := journal.New(journal.DefaultDir(), "").List()
+	if err != nil {
+		return err
+	}
+	if historyLimit > 0 && len(entries) > historyLimit {
+		entries = entries[:historyLimit]
+	}
+
+	if historyJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode history: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No operations recorded")
+		return nil
+	}
+	for _, entry := range entries {
+		status := ""
+		if entry.UndoneAt != nil {
+			status = " (undone)"
+		}
+		fmt.Printf("%s  %s  %s%s\n", entry.ID, entry.Time.Format("2006-01-02 15:04"), entry.Command, status)
+		for _, action := range entry.Actions {
+			fmt.Printf("    %s\n", action.Describe())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/journal"
+)
+
+func TestUndoEntryRestoresRemovedWorktree(t *testing.T) {
+	_, repoDir, _ := setupWorktreeLayoutFixture(t)
+	metadataPath := filepath.Join(repoDir, ".git")
+	j := journal.New(t.TempDir(), "ezgit rm acme/widgets scratch")
+
+	base := git.New()
+	scratchPath := filepath.Join(repoDir, "scratch")
+	if err := base.CreateFeatureWorktree(metadataPath, scratchPath, "scratch", "main"); err != nil {
+		t.Fatal(err)
+	}
+	wt := localWorktree{RepoFullName: "acme/widgets", RepoPath: repoDir, MetadataPath: metadataPath, Name: "scratch", Path: scratchPath, Branch: "scratch"}
+	if err := removeLocalWorktree(git.WithJournal(base, j), wt, false, true); err != nil {
+		t.Fatalf("removeLocalWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(scratchPath); !os.IsNotExist(err) {
+		t.Fatalf("worktree not removed (err=%v)", err)
+	}
+
+	oldDryRun := dryRun
+	t.Cleanup(func() { dryRun = oldDryRun })
+	dryRun = true
+	err := undoEntry(j, git.NewDryRun(base, io.Discard), "")
+	dryRun = false
+	if err != nil {
+		t.Fatalf("undoEntry(dry-run) error = %v", err)
+	}
+	if _, err := os.Stat(scratchPath); !os.IsNotExist(err) {
+		t.Fatalf("dry-run undo restored the worktree (err=%v)", err)
+	}
+
+	// The dry run must leave the entry for the real undo.
+	if err := undoEntry(j, base, ""); err != nil {
+		t.Fatalf("undoEntry() error = %v", err)
+	}
+	if got := strings.TrimSpace(runGitCmd(t, scratchPath, "rev-parse", "--abbrev-ref", "HEAD")); got != "scratch" {
+		t.Fatalf("restored HEAD = %q, want scratch", got)
+	}
+
+	if err := undoEntry(j, base, ""); err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Fatalf("second undo error = %v, want nothing to undo", err)
+	}
+}
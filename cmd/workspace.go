@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Converge local clones with a workspace.toml manifest",
+}
+
+var workspacePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what 'workspace apply' would change",
+	Args:  cobra.NoArgs,
+	RunE:  runWorkspacePlan,
+}
+
+var workspaceApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Clone, convert and add worktrees to match the manifest",
+	Args:  cobra.NoArgs,
+	RunE:  runWorkspaceApply,
+}
+
+var (
+	workspaceFile     string
+	workspacePlanJSON bool
+	workspaceApplyYes bool
+)
+
+const (
+	workspaceClone    = "clone"
+	workspaceConvert  = "convert"
+	workspaceWorktree = "worktree"
+	workspaceConflict = "conflict"
+	workspaceExtra    = "extra"
+)
+
+// workspaceChange is one step of a workspace plan. Conflicts and extra
+// worktrees are reported but never acted on.
+type workspaceChange struct {
+	Action   string `json:"action"`
+	Repo     string `json:"repo"`
+	Worktree string `json:"worktree,omitempty"`
+	Path     string `json:"path"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// worktreeNameLister is the subset of git.GitManager workspace plan needs.
+type worktreeNameLister interface {
+	ListWorktrees(path string) ([]string, error)
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspacePlanCmd)
+	workspaceCmd.AddCommand(workspaceApplyCmd)
+
+	workspaceCmd.PersistentFlags().StringVarP(&workspaceFile, "file", "f", "workspace.toml", "workspace manifest path")
+	workspacePlanCmd.Flags().BoolVar(&workspacePlanJSON, "json", false, "print the plan as JSON")
+	workspaceApplyCmd.Flags().BoolVarP(&workspaceApplyYes, "yes", "y", false, "do not ask for confirmation")
+}
+
+func runWorkspacePlan(cmd *cobra.Command, args []string) error {
+	cfg, ws, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	changes, err := planWorkspace(cfg, ws, newGitManager(cfg))
+	if err != nil {
+		return err
+	}
+
+	if workspacePlanJSON {
+		if changes == nil {
+			changes = []workspaceChange{}
+		}
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode workspace plan: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	writeWorkspacePlan(os.Stdout, changes)
+	return nil
+}
+
+func runWorkspaceApply(cmd *cobra.Command, args []string) error {
+	cfg, ws, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	gitMgr := newGitManager(cfg)
+	changes, err := planWorkspace(cfg, ws, gitMgr)
+	if err != nil {
+		return err
+	}
+	writeWorkspacePlan(os.Stdout, changes)
+
+	counts := countWorkspaceChanges(changes)
+	pending := counts[workspaceClone] + counts[workspaceConvert] + counts[workspaceWorktree]
+	if pending == 0 {
+		if counts[workspaceConflict] > 0 {
+			return fmt.Errorf("%d repositories conflict with the manifest", counts[workspaceConflict])
+		}
+		return nil
+	}
+
+	if !workspaceApplyYes {
+		if !isInteractiveStdin() {
+			return fmt.Errorf("refusing to apply without confirmation; re-run with --yes")
+		}
+		fmt.Printf("\nApply %d change(s)? [y/n]: ", pending)
+		input, err := readLineTrimmed(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if confirmed, ok := parseYesNoRequired(input); !ok || !confirmed {
+			return fmt.Errorf("cancelled")
+		}
+	}
+
+	sshKey := keyPath
+	if sshKey != "" && counts[workspaceClone] > 0 {
+		if err := gitMgr.ValidateSSHKey(sshKey); err != nil {
+			return fmt.Errorf("SSH key validation failed: %w", err)
+		}
+	}
+
+	fmt.Println()
+	failed := applyWorkspace(cfg, gitMgr, ws, changes, sshKey, os.Stdout)
+	fmt.Printf("\nApplied %d change(s), %d failed\n", pending-failed, failed)
+
+	if failed > 0 || counts[workspaceConflict] > 0 {
+		return fmt.Errorf("workspace did not fully converge: %d failed, %d conflicts", failed, counts[workspaceConflict])
+	}
+	return nil
+}
+
+func loadWorkspace() (*config.Config, *config.Workspace, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	ws, err := config.LoadWorkspace(workspaceFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, ws, nil
+}
+
+// planWorkspace diffs the manifest against the clone directory. Changes are
+// ordered so that applying them top to bottom converges each repo.
+func planWorkspace(cfg *config.Config, ws *config.Workspace, lister worktreeNameLister) ([]workspaceChange, error) {
+	var changes []workspaceChange
+	for _, repo := range ws.Repos {
+		repoPath := getRepoPath(cfg, repo.Name, false, "")
+		if repoPath == "" {
+			return nil, fmt.Errorf("failed to resolve local path for %s (is [git].clone_dir set?)", repo.Name)
+		}
+		change := func(action, worktreeName, detail string) workspaceChange {
+			changePath := repoPath
+			if worktreeName != "" {
+				changePath = filepath.Join(repoPath, worktreeName)
+			}
+			return workspaceChange{Action: action, Repo: repo.Name, Worktree: worktreeName, Path: changePath, Detail: detail}
+		}
+		addWorktrees := func(names []string) {
+			for _, name := range names {
+				changes = append(changes, change(workspaceWorktree, name, ""))
+			}
+		}
+
+		desired := workspaceWorktrees(repo)
+		state, err := detectExistingRepoState(repoPath)
+		if err != nil {
+			changes = append(changes, change(workspaceConflict, "", err.Error()))
+			continue
+		}
+
+		switch state {
+		case existingRepoMissing:
+			changes = append(changes, change(workspaceClone, "", workspaceCloneDetail(repo)))
+			addWorktrees(desired)
+		case existingRepoNonRepo:
+			changes = append(changes, change(workspaceConflict, "", "path exists but is not a git repository"))
+		case existingRepoRegular:
+			if repo.Layout == config.LayoutWorktree {
+				changes = append(changes, change(workspaceConvert, "", "to worktree layout"))
+				addWorktrees(desired)
+			}
+		case existingRepoWorktree:
+			if repo.Layout != config.LayoutWorktree {
+				changes = append(changes, change(workspaceConflict, "", "on disk in worktree layout, manifest wants regular"))
+				continue
+			}
+			existing, err := lister.ListWorktrees(repoPath)
+			if err != nil {
+				changes = append(changes, change(workspaceConflict, "", fmt.Sprintf("failed to list worktrees: %v", err)))
+				continue
+			}
+			var missing []string
+			for _, name := range desired {
+				if !containsString(existing, name) {
+					missing = append(missing, name)
+				}
+			}
+			addWorktrees(missing)
+			for _, name := range sortedStrings(existing) {
+				if !containsString(desired, name) {
+					changes = append(changes, change(workspaceExtra, name, "not in manifest"))
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+// workspaceWorktrees returns the worktrees a repo should have. Worktree
+// layout repos without an explicit list get the default branch and review,
+// the same pair clone creates.
+func workspaceWorktrees(repo config.WorkspaceRepo) []string {
+	if repo.Layout != config.LayoutWorktree {
+		return nil
+	}
+	if len(repo.Worktrees) > 0 {
+		return repo.Worktrees
+	}
+	return []string{workspaceDefaultBranch(repo), "review"}
+}
+
+func workspaceDefaultBranch(repo config.WorkspaceRepo) string {
+	return resolveDefaultBranch(repo.Name, repo.Branch)
+}
+
+func workspaceCloneDetail(repo config.WorkspaceRepo) string {
+	detail := repo.Layout
+	if repo.Branch != "" {
+		detail += ", branch " + repo.Branch
+	}
+	if repo.Depth > 0 {
+		detail += fmt.Sprintf(", depth %d", repo.Depth)
+	}
+	return detail
+}
+
+func countWorkspaceChanges(changes []workspaceChange) map[string]int {
+	counts := make(map[string]int)
+	for _, change := range changes {
+		counts[change.Action]++
+	}
+	return counts
+}
+
+func writeWorkspacePlan(w io.Writer, changes []workspaceChange) {
+	counts := countWorkspaceChanges(changes)
+	if counts[workspaceClone]+counts[workspaceConvert]+counts[workspaceWorktree]+counts[workspaceConflict] == 0 {
+		for _, change := range changes {
+			fmt.Fprintf(w, "? %s/%s (%s)\n", change.Repo, change.Worktree, change.Detail)
+		}
+		fmt.Fprintln(w, "Workspace matches the manifest")
+		return
+	}
+
+	for _, change := range changes {
+		switch change.Action {
+		case workspaceClone:
+			fmt.Fprintf(w, "+ clone %s (%s)\n", change.Repo, change.Detail)
+		case workspaceConvert:
+			fmt.Fprintf(w, "~ convert %s %s\n", change.Repo, change.Detail)
+		case workspaceWorktree:
+			fmt.Fprintf(w, "+ worktree %s/%s\n", change.Repo, change.Worktree)
+		case workspaceConflict:
+			fmt.Fprintf(w, "! %s: %s\n", change.Repo, change.Detail)
+		case workspaceExtra:
+			fmt.Fprintf(w, "? %s/%s (%s)\n", change.Repo, change.Worktree, change.Detail)
+		}
+	}
+	fmt.Fprintf(w, "\nPlan: %d to clone, %d to convert, %d worktrees to add, %d conflicts\n",
+		counts[workspaceClone], counts[workspaceConvert], counts[workspaceWorktree], counts[workspaceConflict])
+}
+
+// applyWorkspace runs the clone, convert and worktree changes in order and
+// returns the number that failed. Once a change for a repo fails, the rest of
+// that repo's changes are skipped and counted as failed.
+func applyWorkspace(cfg *config.Config, gitMgr git.GitManager, ws *config.Workspace, changes []workspaceChange, sshKeyPath string, out io.Writer) int {
+	repos := make(map[string]config.WorkspaceRepo, len(ws.Repos))
+	for _, repo := range ws.Repos {
+		repos[repo.Name] = repo
+	}
+
+	failed := 0
+	brokenRepos := make(map[string]bool)
+	touchedRepos := make([]string, 0)
+	for _, change := range changes {
+		label := change.Action + " " + change.Repo
+		if change.Worktree != "" {
+			label += "/" + change.Worktree
+		}
+
+		repo := repos[change.Repo]
+		repoPath := getRepoPath(cfg, repo.Name, false, "")
+		var err error
+		switch {
+		case change.Action != workspaceClone && change.Action != workspaceConvert && change.Action != workspaceWorktree:
+			continue
+		case brokenRepos[change.Repo]:
+			failed++
+			fmt.Fprintf(out, "✗ %s: skipped after an earlier failure\n", label)
+			continue
+		case change.Action == workspaceClone:
+			err = cloneWorkspaceRepo(cfg, gitMgr, repo, sshKeyPath)
+		case change.Action == workspaceConvert:
+			err = convertWithoutWorktrees(cfg, repoPath, workspaceDefaultBranch(repo))
+		default:
+			err = addWorkspaceWorktree(gitMgr, repoPath, workspaceDefaultBranch(repo), change.Worktree)
+		}
+
+		if err != nil {
+			failed++
+			brokenRepos[change.Repo] = true
+			fmt.Fprintf(out, "✗ %s: %s\n", label, firstErrorLine(err.Error()))
+			continue
+		}
+		fmt.Fprintf(out, "✓ %s\n", label)
+		if !containsString(touchedRepos, change.Repo) {
+			touchedRepos = append(touchedRepos, change.Repo)
+		}
+	}
+
+	for _, name := range touchedRepos {
+		if !brokenRepos[name] {
+			registerRepoAndWorktreesWithZoxide(gitMgr, getRepoPath(cfg, name, false, ""), true)
+		}
+	}
+	return failed
+}
+
+// cloneWorkspaceRepo clones a manifest repo without prompts. Worktree layout
+// repos get a bare clone only; their worktrees are separate plan changes.
+func cloneWorkspaceRepo(cfg *config.Config, gitMgr git.GitManager, repo config.WorkspaceRepo, sshKeyPath string) error {
+	repoURL, err := repoCloneURL(cfg, repo.Name)
+	if err != nil {
+		return fmt.Errorf("invalid repo format: %w", err)
+	}
+
+	dest := getRepoPath(cfg, repo.Name, false, "")
+	asWorktree := repo.Layout == config.LayoutWorktree
+	cloneTarget, metadataPath := resolveClonePaths(dest, asWorktree)
+	if asWorktree {
+		if err := mkdirAll(dest); err != nil {
+			return fmt.Errorf("failed to create destination directory: %w", err)
+		}
+	}
+
+	filter, sparse, err := resolvePartialCloneOptions(cfg, repo.Name, repo.Filter, repo.Sparse)
+	if err != nil {
+		return err
+	}
+	extras, err := resolveCheckoutExtras(cfg, repo.Name, git.CheckoutExtras{Submodules: repo.Submodules, LFS: repo.LFS})
+	if err != nil {
+		return err
+	}
+	cloneOpts := git.CloneOptions{
+		Bare:       asWorktree,
+		Branch:     repo.Branch,
+		Depth:      repo.Depth,
+		Quiet:      true,
+		SSHKeyPath: cloneSSHKey(cfg, sshKeyPath, repoURL, repo.Name),
+		Filter:     filter,
+		Sparse:     sparse,
+		Config:     cloneConfig(cfg, repoURL),
+
+		CheckoutExtras: extras,
+	}
+	if err := gitMgr.Clone(repoURL, cloneTarget, cloneOpts); err != nil {
+		return err
+	}
+
+	if asWorktree {
+		if err := gitMgr.ConfigureBareRemote(metadataPath, workspaceDefaultBranch(repo)); err != nil {
+			return fmt.Errorf("failed to configure bare remote: %w", err)
+		}
+	}
+	return nil
+}
+
+// convertWithoutWorktrees converts a regular clone without prompting. Only
+// the checked-out branch gets a worktree (its files move there); callers add
+// the other worktrees they need afterwards.
+func convertWithoutWorktrees(cfg *config.Config, repoPath, defaultBranch string) error {
+	originalNoWorktrees := noWorktrees
+	noWorktrees = true
+	defer func() { noWorktrees = originalNoWorktrees }()
+
+	return runConvertPath(cfg, repoPath, defaultBranch)
+}
+
+// addWorkspaceWorktree creates one manifest worktree: the default branch,
+// the detached review worktree, an existing remote branch, or a new branch
+// from the default branch.
+func addWorkspaceWorktree(gitMgr git.GitManager, repoPath, defaultBranch, name string) error {
+	metadataPath := filepath.Join(repoPath, ".git")
+	worktreePath := filepath.Join(repoPath, name)
+	switch {
+	case name == "review" && name != defaultBranch:
+		return gitMgr.CreateDetachedWorktree(metadataPath, worktreePath, defaultBranch)
+	case name == defaultBranch || gitMgr.RefExists(metadataPath, "refs/remotes/origin/"+name):
+		return gitMgr.CreateWorktree(metadataPath, worktreePath, name)
+	default:
+		return gitMgr.CreateFeatureWorktree(metadataPath, worktreePath, name, defaultBranch)
+	}
+}
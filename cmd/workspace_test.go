@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+type fakeWorktreeNameLister map[string][]string
+
+func (f fakeWorktreeNameLister) ListWorktrees(path string) ([]string, error) {
+	return f[path], nil
+}
+
+func TestPlanWorkspace(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cloneDir := t.TempDir()
+	repoDir := func(name string) string { return filepath.Join(cloneDir, "acme", name) }
+
+	runGitCmd(t, "", "init", "-b", "main", repoDir("web"))
+	runGitCmd(t, "", "init", "-b", "main", repoDir("tools"))
+	runGitCmd(t, "", "init", "--bare", filepath.Join(repoDir("mono"), ".git"))
+	runGitCmd(t, "", "init", "--bare", filepath.Join(repoDir("legacy"), ".git"))
+	if err := os.MkdirAll(repoDir("notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	ws := &config.Workspace{Repos: []config.WorkspaceRepo{
+		{Name: "acme/api", Layout: config.LayoutRegular, Branch: "develop", Depth: 1},
+		{Name: "acme/web", Layout: config.LayoutWorktree},
+		{Name: "acme/tools", Layout: config.LayoutRegular},
+		{Name: "acme/mono", Layout: config.LayoutWorktree, Worktrees: []string{"main", "review", "feature-x"}},
+		{Name: "acme/legacy", Layout: config.LayoutRegular},
+		{Name: "acme/notes", Layout: config.LayoutRegular},
+	}}
+	lister := fakeWorktreeNameLister{repoDir("mono"): {"old", "main"}}
+
+	changes, err := planWorkspace(cfg, ws, lister)
+	if err != nil {
+		t.Fatalf("planWorkspace() error = %v", err)
+	}
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, strings.TrimSpace(change.Action+" "+change.Repo+" "+change.Worktree))
+	}
+	want := []string{
+		"clone acme/api",
+		"convert acme/web",
+		"worktree acme/web main",
+		"worktree acme/web review",
+		"worktree acme/mono review",
+		"worktree acme/mono feature-x",
+		"extra acme/mono old",
+		"conflict acme/legacy",
+		"conflict acme/notes",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("planWorkspace() = %v\nwant %v", got, want)
+	}
+	if changes[0].Detail != "regular, branch develop, depth 1" {
+		t.Fatalf("clone detail = %q", changes[0].Detail)
+	}
+
+	var buf bytes.Buffer
+	writeWorkspacePlan(&buf, changes)
+	if !strings.Contains(buf.String(), "Plan: 1 to clone, 1 to convert, 4 worktrees to add, 2 conflicts") {
+		t.Fatalf("plan output:\n%s", buf.String())
+	}
+}
+
+func TestApplyWorkspaceConverges(t *testing.T) {
+	tmpDir := t.TempDir()
+	originsDir := filepath.Join(tmpDir, "origins")
+	cloneDir := filepath.Join(tmpDir, "src")
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	for _, name := range []string{"api", "mono", "web"} {
+		originDir := filepath.Join(originsDir, name+".git")
+		seedDir := filepath.Join(tmpDir, "seed-"+name)
+		runGitCmd(t, "", "init", "--bare", originDir)
+		runGitCmd(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+		runGitCmd(t, "", "clone", originDir, seedDir)
+		runGitCmd(t, seedDir, "config", "user.email", "test@example.com")
+		runGitCmd(t, seedDir, "config", "user.name", "test")
+		if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGitCmd(t, seedDir, "add", "README.md")
+		runGitCmd(t, seedDir, "commit", "-m", "initial commit")
+		runGitCmd(t, seedDir, "push", "origin", "HEAD:main", "HEAD:existing")
+	}
+	runGitCmd(t, "", "clone", filepath.Join(originsDir, "web.git"), filepath.Join(cloneDir, "acme", "web"))
+
+	originalURL := repoCloneURL
+	originalZoxide := runZoxideAdd
+	t.Cleanup(func() {
+		repoCloneURL = originalURL
+		runZoxideAdd = originalZoxide
+	})
+	repoCloneURL = func(_ *config.Config, input string) (string, error) {
+		_, name, _ := strings.Cut(input, "/")
+		return filepath.Join(originsDir, name+".git"), nil
+	}
+	runZoxideAdd = func(path string) error { return nil }
+
+	cfg := &config.Config{Git: config.GitConfig{CloneDir: cloneDir}}
+	ws := &config.Workspace{Repos: []config.WorkspaceRepo{
+		{Name: "acme/api", Layout: config.LayoutRegular},
+		{Name: "acme/mono", Layout: config.LayoutWorktree, Worktrees: []string{"main", "review", "existing", "feature-x"}},
+		{Name: "acme/web", Layout: config.LayoutWorktree},
+	}}
+
+	gitMgr := git.New()
+	changes, err := planWorkspace(cfg, ws, gitMgr)
+	if err != nil {
+		t.Fatalf("planWorkspace() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if failed := applyWorkspace(cfg, gitMgr, ws, changes, "", &out); failed != 0 {
+		t.Fatalf("applyWorkspace() failed %d changes:\n%s", failed, out.String())
+	}
+
+	if state, _ := detectExistingRepoState(filepath.Join(cloneDir, "acme", "api")); state != existingRepoRegular {
+		t.Fatalf("acme/api state = %v, want regular", state)
+	}
+	for _, wt := range []string{"mono/main", "mono/review", "mono/existing", "mono/feature-x", "web/main", "web/review"} {
+		if _, err := os.Stat(filepath.Join(cloneDir, "acme", wt, "README.md")); err != nil {
+			t.Fatalf("worktree %s missing: %v", wt, err)
+		}
+	}
+
+	changes, err = planWorkspace(cfg, ws, gitMgr)
+	if err != nil {
+		t.Fatalf("planWorkspace() after apply error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("planWorkspace() after apply = %+v, want no changes", changes)
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+	"github.com/kirksw/ezgit/internal/utils"
+)
+
+// seedWorktree copies and symlinks the repo's worktree_copy and
+// worktree_link matches into the new worktree at worktreePath. Paths the
+// checkout already has are left alone, so tracked files are never replaced.
+func seedWorktree(cfg *config.Config, gitMgr git.GitManager, barePath, worktreePath, repoFullName string) error {
+	copies, links := cfg.RepoWorktreeFiles(repoFullName)
+	if len(copies) == 0 && len(links) == 0 {
+		return nil
+	}
+	source := worktreeFilesSource(gitMgr, barePath, repoFullName)
+	if source == "" || sameDir(source, worktreePath) {
+		return nil
+	}
+
+	var errs []error
+	seed := func(patterns []string, link bool) {
+		for _, rel := range worktreeFileMatches(source, patterns) {
+			from := filepath.Join(source, rel)
+			to := filepath.Join(worktreePath, rel)
+			if _, err := os.Lstat(to); err == nil {
+				continue
+			}
+			if dryRun {
+				if link {
+					planStep("ln -s %s %s", utils.ShellQuote(from), utils.ShellQuote(to))
+				} else {
+					planStep("cp -R %s %s", utils.ShellQuote(from), utils.ShellQuote(to))
+				}
+				continue
+			}
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Seeding %s from %s\n", to, from)
+			}
+			err := os.MkdirAll(filepath.Dir(to), 0755)
+			if err == nil && link {
+				err = os.Symlink(from, to)
+			} else if err == nil {
+				err = utils.CopyTree(from, to)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to seed %s from %s: %w", to, from, err))
+			}
+		}
+	}
+	seed(copies, false)
+	seed(links, true)
+	return errors.Join(errs...)
+}
+
+// worktreeFilesSource returns the checkout new worktrees take their
+// untracked files from: the root of a regular clone, or the worktree of the
+// default branch (the branch HEAD names in bare metadata). It is empty when
+// that worktree does not exist.
+func worktreeFilesSource(gitMgr git.GitManager, barePath, repoFullName string) string {
+	root := hookRepoRoot(barePath)
+	cmd := exec.Command("git", "rev-parse", "--is-bare-repository")
+	cmd.Dir = barePath
+	if output, err := cmd.Output(); err == nil && strings.TrimSpace(string(output)) == "false" {
+		return root
+	}
+
+	branch, err := gitMgr.CurrentBranch(barePath)
+	if err != nil || branch == "" {
+		branch = resolveDefaultBranch(repoFullName, "")
+	}
+	source := filepath.Join(root, branch)
+	if info, err := os.Stat(source); err != nil || !info.IsDir() {
+		return ""
+	}
+	return source
+}
+
+// worktreeFileMatches expands patterns in source to paths relative to it,
+// in pattern order without duplicates. A trailing slash matches directories only; .git is
+// never matched.
+func worktreeFileMatches(source string, patterns []string) []string {
+	var matches []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		dirOnly := strings.HasSuffix(pattern, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if pattern == "" {
+			continue
+		}
+		found, err := filepath.Glob(filepath.Join(source, filepath.FromSlash(pattern)))
+		if err != nil {
+			continue
+		}
+		for _, match := range found {
+			rel, err := filepath.Rel(source, match)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			if first := strings.Split(filepath.ToSlash(rel), "/")[0]; first == ".git" {
+				continue
+			}
+			if dirOnly {
+				if info, err := os.Stat(match); err != nil || !info.IsDir() {
+					continue
+				}
+			}
+			if !seen[rel] {
+				seen[rel] = true
+				matches = append(matches, rel)
+			}
+		}
+	}
+	return matches
+}
+
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
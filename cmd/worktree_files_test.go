@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/config"
+	"github.com/kirksw/ezgit/internal/git"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewWorktreesGetCopiedAndLinkedDevFiles(t *testing.T) {
+	cfg, repoDir, _ := setupWorktreeLayoutFixture(t)
+	mainPath := filepath.Join(repoDir, "main")
+	writeTestFile(t, filepath.Join(mainPath, ".env"), "TOKEN=1\n")
+	writeTestFile(t, filepath.Join(mainPath, ".env.local"), "DEBUG=1\n")
+	writeTestFile(t, filepath.Join(mainPath, ".vscode", "settings.json"), "{}\n")
+	writeTestFile(t, filepath.Join(mainPath, "node_modules", "left-pad", "index.js"), "module.exports = 1\n")
+	writeTestFile(t, filepath.Join(mainPath, "README.md"), "local edit\n")
+
+	cfg.Git.WorktreeCopy = []string{".env*", ".vscode/", "README.md"}
+	cfg.Git.WorktreeLink = []string{"node_modules"}
+	gitMgr := withHooks(cfg, git.New())
+	metadataPath := filepath.Join(repoDir, ".git")
+	featurePath := filepath.Join(repoDir, "feature-x")
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, featurePath, "feature-x", "main"); err != nil {
+		t.Fatalf("CreateFeatureWorktree() error = %v", err)
+	}
+
+	for file, want := range map[string]string{
+		".env":                  "TOKEN=1\n",
+		".env.local":            "DEBUG=1\n",
+		".vscode/settings.json": "{}\n",
+		// Tracked files come from the checkout, not the default worktree.
+		"README.md": "README.md\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(featurePath, file))
+		if err != nil || string(data) != want {
+			t.Fatalf("%s = %q, %v; want %q", file, data, err, want)
+		}
+	}
+	target, err := os.Readlink(filepath.Join(featurePath, "node_modules"))
+	if err != nil || target != filepath.Join(mainPath, "node_modules") {
+		t.Fatalf("node_modules link = %q, %v; want a symlink to the default worktree's", target, err)
+	}
+
+	// Per-repo clone rules replace the defaults.
+	cfg.Git.CloneRules = []config.CloneRule{{Match: "acme/*", WorktreeCopy: []string{".vscode/"}}}
+	otherPath := filepath.Join(repoDir, "feature-y")
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, otherPath, "feature-y", "main"); err != nil {
+		t.Fatalf("CreateFeatureWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(otherPath, ".vscode", "settings.json")); err != nil {
+		t.Fatalf("rule's worktree_copy not applied: %v", err)
+	}
+	for _, file := range []string{".env", "node_modules"} {
+		if _, err := os.Lstat(filepath.Join(otherPath, file)); !os.IsNotExist(err) {
+			t.Fatalf("%s seeded despite the clone rule replacing the defaults (err = %v)", file, err)
+		}
+	}
+}
+
+func TestWorktreeFileMatchesSkipsGitAndHonoursDirOnlyPatterns(t *testing.T) {
+	source := t.TempDir()
+	writeTestFile(t, filepath.Join(source, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeTestFile(t, filepath.Join(source, ".idea", "workspace.xml"), "<xml/>\n")
+	writeTestFile(t, filepath.Join(source, ".envrc"), "use flake\n")
+
+	got := worktreeFileMatches(source, []string{".*", ".envrc/", ".idea/"})
+	want := []string{".envrc", ".idea"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("worktreeFileMatches() = %q, want %q", got, want)
+	}
+}
@@ -17,6 +17,11 @@ const CacheDir = ".cache/ezgit"
 const DefaultTTL = 24 * time.Hour
 const PersonalCacheKey = "personal"
 
+// FormatVersion is bumped whenever cached repos gain fields that an
+// incremental refresh would never fill in for repos already cached. Version
+// 2 added topics, archived and fork.
+const FormatVersion = 2
+
 type OrgCache struct {
 	cacheDir string
 	ttl      time.Duration
@@ -26,6 +31,7 @@ type CacheMetadata struct {
 	LastRefreshed       time.Time     `json:"last_refreshed"`
 	TTL                 time.Duration `json:"ttl"`
 	LatestRepoCreatedAt time.Time     `json:"latest_repo_created_at"`
+	FormatVersion       int           `json:"format_version"`
 }
 
 type allReposSnapshot struct {
@@ -53,6 +59,11 @@ func New() *OrgCache {
 	}
 }
 
+// Dir returns the directory the cache files live in.
+func (c *OrgCache) Dir() string {
+	return c.cacheDir
+}
+
 func (c *OrgCache) Get(org string) (*github.CachedOrg, error) {
 	data, err := os.ReadFile(c.orgPath(org))
 	if err != nil {
@@ -97,6 +108,7 @@ func (c *OrgCache) Set(org string, repos []github.Repo) error {
 		LastRefreshed:       time.Now(),
 		TTL:                 c.ttl,
 		LatestRepoCreatedAt: latestRepoCreatedAt(repos),
+		FormatVersion:       FormatVersion,
 	}
 
 	metaData, err := json.Marshal(metadata)
@@ -293,6 +305,13 @@ func (c *OrgCache) IsExpired(org string) bool {
 	return !time.Now().Before(metadata.LastRefreshed.Add(metadata.TTL))
 }
 
+// IsOutdated reports whether org was cached in an older format (or not at
+// all) and needs a full refresh rather than an incremental one.
+func (c *OrgCache) IsOutdated(org string) bool {
+	metadata, err := c.readMetadata(org)
+	return err != nil || metadata.FormatVersion < FormatVersion
+}
+
 func (c *OrgCache) readMetadata(org string) (CacheMetadata, error) {
 	data, err := os.ReadFile(c.metadataPath(org))
 	if err != nil {
@@ -3,10 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
-	"sync"
 
 	"github.com/BurntSushi/toml"
 )
@@ -16,6 +15,7 @@ type Config struct {
 	Repos         RepoConfig         `toml:"repos"`
 	GitHub        GitHubConfig       `toml:"github"`
 	Git           GitConfig          `toml:"git"`
+	Hooks         HooksConfig        `toml:"hooks"`
 }
 
 type OrganizationConfig struct {
@@ -27,13 +27,187 @@ type RepoConfig struct {
 }
 
 type GitHubConfig struct {
-	Token string `toml:"token"`
+	Token        string                      `toml:"token"`
+	TokenSources []string                    `toml:"token_sources"`
+	TokenCommand string                      `toml:"token_command"`
+	TokenFile    string                      `toml:"token_file"`
+	TokenEnv     []string                    `toml:"token_env"`
+	Hosts        map[string]GitHubHostConfig `toml:"hosts"`
+}
+
+// GitHubHostConfig overrides [github] settings for a single host. Empty
+// fields inherit the top-level value.
+type GitHubHostConfig struct {
+	Token        string   `toml:"token"`
+	TokenSources []string `toml:"token_sources"`
+	TokenCommand string   `toml:"token_command"`
+	TokenFile    string   `toml:"token_file"`
+	TokenEnv     []string `toml:"token_env"`
 }
 
 type GitConfig struct {
-	CloneDir                 string `toml:"clone_dir"`
-	OpenCommand              string `toml:"open_command"`
-	ShallowPromptThresholdKB int    `toml:"shallow_prompt_threshold_kb"`
+	CloneDir                 string       `toml:"clone_dir"`
+	OpenCommand              string       `toml:"open_command"`
+	ShallowPromptThresholdKB int          `toml:"shallow_prompt_threshold_kb"`
+	Backend                  string       `toml:"backend"`
+	LayoutRules              []LayoutRule `toml:"layout_rules"`
+	IssueBranchTemplate      string       `toml:"issue_branch_template"`
+	CloneRules               []CloneRule  `toml:"clone_rules"`
+	SSHKeys                  []SSHKeyRule `toml:"ssh_keys"`
+	// Protocol is how owner/repo inputs are cloned: ssh (default), https
+	// or auto. ProtocolRules override it per host or repo glob.
+	Protocol      string         `toml:"protocol"`
+	ProtocolRules []ProtocolRule `toml:"protocol_rules"`
+	// Proxy is stored as http.proxy in HTTPS clones.
+	Proxy string `toml:"proxy"`
+	// WorktreeCopy and WorktreeLink list untracked files (globs relative to
+	// the checkout, e.g. ".env*" or ".vscode/") copied or symlinked into
+	// every new worktree. Clone rules that set either replace both.
+	WorktreeCopy []string `toml:"worktree_copy"`
+	WorktreeLink []string `toml:"worktree_link"`
+}
+
+const (
+	ProtocolSSH   = "ssh"
+	ProtocolHTTPS = "https"
+	ProtocolAuto  = "auto"
+)
+
+// ProtocolRule picks the clone protocol for repos on Host and/or whose
+// owner/name matches the glob in Match.
+type ProtocolRule struct {
+	Match    string `toml:"match"`
+	Host     string `toml:"host"`
+	Protocol string `toml:"protocol"`
+}
+
+// SSHKeyRule pins the SSH key used for repos on Host and/or whose owner/name
+// matches the glob in Match (e.g. "acme/*"). A rule with both set must match
+// both.
+type SSHKeyRule struct {
+	Match string `toml:"match"`
+	Host  string `toml:"host"`
+	Key   string `toml:"key"`
+}
+
+// CloneRule sets partial clone and sparse-checkout options for repos whose
+// owner/name matches the glob in Match. Filter is "blob:none" or "tree:0";
+// Sparse lists cone directories checked out in every worktree. Submodules
+// ("recursive" or "shallow") and LFS ("pull" or "skip") apply to the clone
+// and every worktree. WorktreeCopy and WorktreeLink replace the [git]
+// defaults for new worktrees.
+type CloneRule struct {
+	Match        string   `toml:"match"`
+	Filter       string   `toml:"filter"`
+	Sparse       []string `toml:"sparse"`
+	Submodules   string   `toml:"submodules"`
+	LFS          string   `toml:"lfs"`
+	WorktreeCopy []string `toml:"worktree_copy"`
+	WorktreeLink []string `toml:"worktree_link"`
+}
+
+// Lifecycle events that run hooks.
+const (
+	HookPostClone          = "post-clone"
+	HookPostConvert        = "post-convert"
+	HookPostWorktreeCreate = "post-worktree-create"
+	HookPreOpen            = "pre-open"
+	HookPreRemove          = "pre-remove"
+)
+
+// HooksConfig lists shell commands run at lifecycle events. The top-level
+// commands run for every repo and each rule whose Match glob matches
+// owner/name adds its own ("acme/*" for an org), global ones first.
+// TrustedRepos lists the owner/name globs whose own .ezgit/hooks scripts may
+// run; scripts in other repos are skipped because they arrive with the clone.
+type HooksConfig struct {
+	HookCommands
+	Rules        []HookRule `toml:"rules"`
+	TrustedRepos []string   `toml:"trusted_repos"`
+}
+
+// HookCommands holds the commands for each lifecycle event.
+type HookCommands struct {
+	PostClone          []string `toml:"post_clone"`
+	PostConvert        []string `toml:"post_convert"`
+	PostWorktreeCreate []string `toml:"post_worktree_create"`
+	PreOpen            []string `toml:"pre_open"`
+	PreRemove          []string `toml:"pre_remove"`
+}
+
+// HookRule adds hook commands for repos whose owner/name matches Match.
+type HookRule struct {
+	Match string `toml:"match"`
+	HookCommands
+}
+
+func (h HookCommands) forEvent(event string) []string {
+	switch event {
+	case HookPostClone:
+		return h.PostClone
+	case HookPostConvert:
+		return h.PostConvert
+	case HookPostWorktreeCreate:
+		return h.PostWorktreeCreate
+	case HookPreOpen:
+		return h.PreOpen
+	case HookPreRemove:
+		return h.PreRemove
+	default:
+		return nil
+	}
+}
+
+// HasHooks reports whether any hook command or trusted repo is configured.
+func (c *Config) HasHooks() bool {
+	return len(c.Hooks.TrustedRepos) > 0 || len(c.Hooks.Rules) > 0 || c.Hooks.HookCommands.any()
+}
+
+func (h HookCommands) any() bool {
+	return len(h.PostClone)+len(h.PostConvert)+len(h.PostWorktreeCreate)+len(h.PreOpen)+len(h.PreRemove) > 0
+}
+
+// RepoHooks returns the configured commands for event in repoFullName: the
+// global ones, then those of every matching rule in order.
+func (c *Config) RepoHooks(event, repoFullName string) []string {
+	commands := append([]string(nil), c.Hooks.forEvent(event)...)
+	name := strings.ToLower(strings.TrimSpace(repoFullName))
+	for _, rule := range c.Hooks.Rules {
+		pattern := strings.ToLower(strings.TrimSpace(rule.Match))
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			commands = append(commands, rule.forEvent(event)...)
+		}
+	}
+	return commands
+}
+
+// TrustsRepoHooks reports whether repoFullName may run its own
+// .ezgit/hooks scripts.
+func (c *Config) TrustsRepoHooks(repoFullName string) bool {
+	name := strings.ToLower(strings.TrimSpace(repoFullName))
+	for _, pattern := range c.Hooks.TrustedRepos {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultIssueBranchTemplate names branches created by 'ezgit issue' when
+// [git].issue_branch_template is unset.
+const DefaultIssueBranchTemplate = "{number}-{title_slug}"
+
+const (
+	LayoutRegular  = "regular"
+	LayoutWorktree = "worktree"
+)
+
+// LayoutRule picks the clone layout for repos whose owner/name matches the
+// glob in Match (e.g. "acme/*-service").
+type LayoutRule struct {
+	Match  string `toml:"match"`
+	Layout string `toml:"layout"`
 }
 
 func Load(path string) (*Config, error) {
@@ -102,58 +276,208 @@ func (c *Config) GetPrivateRepos() []string {
 	return c.Repos.Private
 }
 
-var (
-	ghTokenCache   string
-	ghTokenCached  bool
-	ghTokenCacheMu sync.RWMutex
-)
+func (c *Config) GetCloneDir() string {
+	return expandHome(c.Git.CloneDir)
+}
 
-func getGitHubCLIAuthToken() (string, error) {
-	ghTokenCacheMu.RLock()
-	if ghTokenCached {
-		token := ghTokenCache
-		ghTokenCacheMu.RUnlock()
-		return token, nil
+func (c *Config) GetIssueBranchTemplate() string {
+	if template := strings.TrimSpace(c.Git.IssueBranchTemplate); template != "" {
+		return template
 	}
-	ghTokenCacheMu.RUnlock()
+	return DefaultIssueBranchTemplate
+}
 
-	cmd := exec.Command("gh", "auth", "token")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
+// RepoLayout returns the layout of the first layout rule matching
+// repoFullName, or an empty string when no rule matches.
+func (c *Config) RepoLayout(repoFullName string) string {
+	name := strings.ToLower(strings.TrimSpace(repoFullName))
+	for _, rule := range c.Git.LayoutRules {
+		pattern := strings.ToLower(strings.TrimSpace(rule.Match))
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return strings.ToLower(strings.TrimSpace(rule.Layout))
+		}
 	}
+	return ""
+}
 
-	token := strings.TrimSpace(string(output))
+// RepoCloneRule returns the first clone rule matching repoFullName, or the
+// zero rule when none matches.
+func (c *Config) RepoCloneRule(repoFullName string) CloneRule {
+	name := strings.ToLower(strings.TrimSpace(repoFullName))
+	for _, rule := range c.Git.CloneRules {
+		pattern := strings.ToLower(strings.TrimSpace(rule.Match))
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return rule
+		}
+	}
+	return CloneRule{}
+}
 
-	ghTokenCacheMu.Lock()
-	ghTokenCache = token
-	ghTokenCached = true
-	ghTokenCacheMu.Unlock()
+// RepoWorktreeFiles returns the globs copied and symlinked into new
+// worktrees of repoFullName: those of the first matching clone rule that
+// sets either, else the [git] defaults.
+func (c *Config) RepoWorktreeFiles(repoFullName string) (copies, links []string) {
+	rule := c.RepoCloneRule(repoFullName)
+	if rule.WorktreeCopy != nil || rule.WorktreeLink != nil {
+		return rule.WorktreeCopy, rule.WorktreeLink
+	}
+	return c.Git.WorktreeCopy, c.Git.WorktreeLink
+}
 
-	return token, nil
+// HasWorktreeFiles reports whether any worktree_copy or worktree_link is
+// configured.
+func (c *Config) HasWorktreeFiles() bool {
+	if len(c.Git.WorktreeCopy) > 0 || len(c.Git.WorktreeLink) > 0 {
+		return true
+	}
+	for _, rule := range c.Git.CloneRules {
+		if len(rule.WorktreeCopy) > 0 || len(rule.WorktreeLink) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
-func (c *Config) GetGitHubToken() string {
-	token, err := getGitHubCLIAuthToken()
-	if err == nil && token != "" {
-		return token
+// RepoSSHKey returns the key of the first SSH key rule matching host and
+// repoFullName, or an empty string when none matches.
+func (c *Config) RepoSSHKey(host, repoFullName string) string {
+	for _, rule := range c.Git.SSHKeys {
+		if hostRuleMatches(rule.Host, rule.Match, host, repoFullName) {
+			return expandHome(strings.TrimSpace(rule.Key))
+		}
 	}
+	return ""
+}
 
-	if c.GitHub.Token != "" {
-		return c.GitHub.Token
+// RepoProtocol returns the clone protocol for repoFullName on host: the
+// first matching protocol rule, else [git].protocol, else ssh.
+func (c *Config) RepoProtocol(host, repoFullName string) string {
+	if protocol := c.ConfiguredRepoProtocol(host, repoFullName); protocol != "" {
+		return protocol
 	}
+	return ProtocolSSH
+}
 
-	return os.Getenv("GITHUB_TOKEN")
+// ConfiguredRepoProtocol is RepoProtocol without the ssh default: empty
+// when neither a protocol rule nor [git].protocol applies.
+func (c *Config) ConfiguredRepoProtocol(host, repoFullName string) string {
+	for _, rule := range c.Git.ProtocolRules {
+		if hostRuleMatches(rule.Host, rule.Match, host, repoFullName) {
+			return strings.ToLower(strings.TrimSpace(rule.Protocol))
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(c.Git.Protocol))
 }
 
-func (c *Config) GetCloneDir() string {
-	dir := c.Git.CloneDir
-	if strings.HasPrefix(dir, "~/") {
-		if home, err := os.UserHomeDir(); err == nil {
-			dir = filepath.Join(home, dir[2:])
+// hostRuleMatches reports whether a rule with ruleHost and/or the glob
+// pattern applies to repoFullName on host. A rule with neither never does.
+func hostRuleMatches(ruleHost, pattern, host, repoFullName string) bool {
+	ruleHost = strings.ToLower(strings.TrimSpace(ruleHost))
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if ruleHost == "" && pattern == "" {
+		return false
+	}
+	if ruleHost != "" && ruleHost != strings.ToLower(strings.TrimSpace(host)) {
+		return false
+	}
+	if pattern != "" {
+		matched, err := path.Match(pattern, strings.ToLower(strings.TrimSpace(repoFullName)))
+		return err == nil && matched
+	}
+	return true
+}
+
+// Validate reports settings that parse but cannot work: unknown layouts,
+// protocols or token sources, malformed globs and incomplete rules.
+func (c *Config) Validate() []error {
+	var problems []error
+	checkGlob := func(field string, i int, pattern string) {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Errorf("[git].%s[%d]: invalid match glob %q", field, i, pattern))
+		}
+	}
+
+	for i, rule := range c.Git.LayoutRules {
+		checkGlob("layout_rules", i, rule.Match)
+		switch strings.ToLower(strings.TrimSpace(rule.Layout)) {
+		case LayoutRegular, LayoutWorktree:
+		default:
+			problems = append(problems, fmt.Errorf("[git].layout_rules[%d]: unknown layout %q (use %s or %s)", i, rule.Layout, LayoutRegular, LayoutWorktree))
+		}
+	}
+	checkWorktreeFiles := func(field string, patterns []string) {
+		for i, pattern := range patterns {
+			clean := path.Clean(strings.TrimSpace(pattern))
+			if _, err := path.Match(clean, ""); err != nil || pattern == "" || path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+				problems = append(problems, fmt.Errorf("[git].%s[%d]: %q must be a glob inside the checkout", field, i, pattern))
+			}
 		}
 	}
-	return dir
+	checkWorktreeFiles("worktree_copy", c.Git.WorktreeCopy)
+	checkWorktreeFiles("worktree_link", c.Git.WorktreeLink)
+	for i, rule := range c.Git.CloneRules {
+		checkGlob("clone_rules", i, rule.Match)
+		checkWorktreeFiles(fmt.Sprintf("clone_rules[%d].worktree_copy", i), rule.WorktreeCopy)
+		checkWorktreeFiles(fmt.Sprintf("clone_rules[%d].worktree_link", i), rule.WorktreeLink)
+	}
+	for i, rule := range c.Git.SSHKeys {
+		checkGlob("ssh_keys", i, rule.Match)
+		if strings.TrimSpace(rule.Match) == "" && strings.TrimSpace(rule.Host) == "" {
+			problems = append(problems, fmt.Errorf("[git].ssh_keys[%d]: set match and/or host, or the rule never applies", i))
+		}
+		if strings.TrimSpace(rule.Key) == "" {
+			problems = append(problems, fmt.Errorf("[git].ssh_keys[%d]: key is empty", i))
+		}
+	}
+
+	for i, rule := range c.Hooks.Rules {
+		pattern := strings.ToLower(strings.TrimSpace(rule.Match))
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			problems = append(problems, fmt.Errorf("[hooks].rules[%d]: invalid match glob %q", i, rule.Match))
+		}
+	}
+	for i, pattern := range c.Hooks.TrustedRepos {
+		if _, err := path.Match(strings.ToLower(strings.TrimSpace(pattern)), ""); err != nil {
+			problems = append(problems, fmt.Errorf("[hooks].trusted_repos[%d]: invalid glob %q", i, pattern))
+		}
+	}
+
+	validProtocol := func(protocol string) bool {
+		switch strings.ToLower(strings.TrimSpace(protocol)) {
+		case ProtocolSSH, ProtocolHTTPS, ProtocolAuto:
+			return true
+		}
+		return false
+	}
+	if c.Git.Protocol != "" && !validProtocol(c.Git.Protocol) {
+		problems = append(problems, fmt.Errorf("[git].protocol: unknown protocol %q (use ssh, https or auto)", c.Git.Protocol))
+	}
+	for i, rule := range c.Git.ProtocolRules {
+		checkGlob("protocol_rules", i, rule.Match)
+		if strings.TrimSpace(rule.Match) == "" && strings.TrimSpace(rule.Host) == "" {
+			problems = append(problems, fmt.Errorf("[git].protocol_rules[%d]: set match and/or host, or the rule never applies", i))
+		}
+		if !validProtocol(rule.Protocol) {
+			problems = append(problems, fmt.Errorf("[git].protocol_rules[%d]: unknown protocol %q (use ssh, https or auto)", i, rule.Protocol))
+		}
+	}
+
+	checkSources := func(field string, sources []string) {
+		for _, source := range sources {
+			if _, ok := tokenSourceResolvers[strings.TrimSpace(source)]; !ok {
+				problems = append(problems, fmt.Errorf("%s: unknown token source %q", field, source))
+			}
+		}
+	}
+	checkSources("[github].token_sources", c.GitHub.TokenSources)
+	for _, host := range c.GitHubHosts() {
+		if settings, ok := c.GitHub.Hosts[host]; ok {
+			checkSources(fmt.Sprintf("[github.hosts.%q].token_sources", host), settings.TokenSources)
+		}
+	}
+
+	return problems
 }
 
 func ParseOwnerRepo(input string) (string, string, error) {
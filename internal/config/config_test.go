@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -152,13 +153,11 @@ func TestGetGitHubToken(t *testing.T) {
 		os.Setenv("HOME", oldHome)
 		os.Setenv("PATH", oldPath)
 		os.Unsetenv("GITHUB_TOKEN")
-		ghTokenCache = ""
-		ghTokenCached = false
+		ghHostTokenCache = make(map[string]string)
 	})
 
 	t.Run("uses gh cli token when available", func(t *testing.T) {
-		ghTokenCache = "gh_test_token_12345"
-		ghTokenCached = true
+		ghHostTokenCache = map[string]string{DefaultGitHubHost: "gh_test_token_12345"}
 
 		cfg := &Config{}
 		token := cfg.GetGitHubToken()
@@ -168,8 +167,7 @@ func TestGetGitHubToken(t *testing.T) {
 	})
 
 	t.Run("falls back to config token when gh not available", func(t *testing.T) {
-		ghTokenCache = ""
-		ghTokenCached = false
+		ghHostTokenCache = make(map[string]string)
 		os.Setenv("PATH", "")
 
 		os.Setenv("GITHUB_TOKEN", "")
@@ -183,8 +181,7 @@ func TestGetGitHubToken(t *testing.T) {
 	})
 
 	t.Run("falls back to env var when gh not available", func(t *testing.T) {
-		ghTokenCache = ""
-		ghTokenCached = false
+		ghHostTokenCache = make(map[string]string)
 		os.Setenv("PATH", "")
 
 		os.Setenv("GITHUB_TOKEN", "env_token")
@@ -196,8 +193,7 @@ func TestGetGitHubToken(t *testing.T) {
 	})
 
 	t.Run("returns empty string when no token available", func(t *testing.T) {
-		ghTokenCache = ""
-		ghTokenCached = false
+		ghHostTokenCache = make(map[string]string)
 		os.Setenv("PATH", "")
 
 		os.Setenv("GITHUB_TOKEN", "")
@@ -208,3 +204,255 @@ func TestGetGitHubToken(t *testing.T) {
 		}
 	})
 }
+
+func TestRepoLayoutUsesFirstMatchingRule(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	configContent := `[git]
+layout_rules = [
+  { match = "acme/monorepo", layout = "worktree" },
+  { match = "acme/*", layout = "regular" },
+  { match = "*/*-service", layout = "Worktree" },
+]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	cases := map[string]string{
+		"acme/monorepo":       LayoutWorktree,
+		"Acme/web":            LayoutRegular,
+		"other/users-service": LayoutWorktree,
+		"other/tool":          "",
+	}
+	for repo, want := range cases {
+		if got := cfg.RepoLayout(repo); got != want {
+			t.Errorf("RepoLayout(%q) = %q, want %q", repo, got, want)
+		}
+	}
+}
+
+func TestGetIssueBranchTemplate(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.GetIssueBranchTemplate(); got != DefaultIssueBranchTemplate {
+		t.Fatalf("GetIssueBranchTemplate() = %q, want default", got)
+	}
+	cfg.Git.IssueBranchTemplate = " {user}/{number}-{title_slug} "
+	if got := cfg.GetIssueBranchTemplate(); got != "{user}/{number}-{title_slug}" {
+		t.Fatalf("GetIssueBranchTemplate() = %q", got)
+	}
+}
+
+func TestRepoCloneRuleUsesFirstMatchingRule(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	configContent := `[git]
+clone_rules = [
+  { match = "acme/monorepo", filter = "blob:none", sparse = ["services/api", "libs"] },
+  { match = "acme/*", filter = "tree:0" },
+]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	mono := cfg.RepoCloneRule("Acme/Monorepo")
+	if mono.Filter != "blob:none" || len(mono.Sparse) != 2 || mono.Sparse[0] != "services/api" {
+		t.Fatalf("RepoCloneRule(acme/monorepo) = %+v", mono)
+	}
+	if web := cfg.RepoCloneRule("acme/web"); web.Filter != "tree:0" || len(web.Sparse) != 0 {
+		t.Fatalf("RepoCloneRule(acme/web) = %+v", web)
+	}
+	if other := cfg.RepoCloneRule("other/tool"); other.Filter != "" || other.Sparse != nil {
+		t.Fatalf("RepoCloneRule(other/tool) = %+v, want zero rule", other)
+	}
+}
+
+func TestRepoWorktreeFilesPrefersMatchingCloneRule(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	configContent := `[git]
+worktree_copy = [".env*", ".vscode/"]
+worktree_link = ["node_modules"]
+clone_rules = [
+  { match = "acme/monorepo", filter = "blob:none" },
+  { match = "acme/*", worktree_link = [".venv"] },
+]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.HasWorktreeFiles() {
+		t.Fatal("HasWorktreeFiles() = false")
+	}
+
+	// The first matching rule sets neither list, so the defaults apply.
+	copies, links := cfg.RepoWorktreeFiles("acme/monorepo")
+	if len(copies) != 2 || len(links) != 1 || links[0] != "node_modules" {
+		t.Fatalf("RepoWorktreeFiles(acme/monorepo) = %q, %q", copies, links)
+	}
+	copies, links = cfg.RepoWorktreeFiles("acme/api")
+	if len(copies) != 0 || len(links) != 1 || links[0] != ".venv" {
+		t.Fatalf("RepoWorktreeFiles(acme/api) = %q, %q", copies, links)
+	}
+	if (&Config{}).HasWorktreeFiles() {
+		t.Fatal("HasWorktreeFiles() of empty config = true")
+	}
+}
+
+func TestRepoSSHKeyMatchesHostAndRepo(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	configContent := `[git]
+ssh_keys = [
+  { match = "acme/*", host = "github.com", key = "~/.ssh/id_ed25519_acme" },
+  { host = "ghe.example.com", key = "/keys/ghe" },
+  { key = "/keys/ignored" },
+]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		host string
+		repo string
+		want string
+	}{
+		{host: "github.com", repo: "Acme/api", want: filepath.Join(home, ".ssh", "id_ed25519_acme")},
+		{host: "ghe.example.com", repo: "acme/api", want: "/keys/ghe"},
+		{host: "ghe.example.com", repo: "other/tool", want: "/keys/ghe"},
+		{host: "github.com", repo: "other/tool", want: ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.RepoSSHKey(tt.host, tt.repo); got != tt.want {
+			t.Errorf("RepoSSHKey(%q, %q) = %q, want %q", tt.host, tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestRepoProtocolPrefersRulesThenDefault(t *testing.T) {
+	cfg := &Config{Git: GitConfig{
+		Protocol: "auto",
+		ProtocolRules: []ProtocolRule{
+			{Match: "acme/*", Protocol: "HTTPS"},
+			{Host: "ghe.example.com", Protocol: "ssh"},
+		},
+	}}
+
+	tests := []struct {
+		host string
+		repo string
+		want string
+	}{
+		{host: "github.com", repo: "Acme/api", want: ProtocolHTTPS},
+		{host: "ghe.example.com", repo: "other/tool", want: ProtocolSSH},
+		{host: "github.com", repo: "other/tool", want: ProtocolAuto},
+	}
+	for _, tt := range tests {
+		if got := cfg.RepoProtocol(tt.host, tt.repo); got != tt.want {
+			t.Errorf("RepoProtocol(%q, %q) = %q, want %q", tt.host, tt.repo, got, tt.want)
+		}
+	}
+
+	if got := (&Config{}).RepoProtocol("github.com", "acme/api"); got != ProtocolSSH {
+		t.Fatalf("RepoProtocol() default = %q, want %q", got, ProtocolSSH)
+	}
+}
+
+func TestValidateReportsUnusableSettings(t *testing.T) {
+	cfg := &Config{
+		GitHub: GitHubConfig{
+			TokenSources: []string{TokenSourceGH, "vault"},
+			Hosts:        map[string]GitHubHostConfig{"ghe.example.com": {TokenSources: []string{"keychain"}}},
+		},
+		Git: GitConfig{
+			Protocol:      "ftp",
+			LayoutRules:   []LayoutRule{{Match: "acme/[", Layout: LayoutWorktree}, {Match: "*", Layout: "flat"}},
+			SSHKeys:       []SSHKeyRule{{Key: "/keys/a"}},
+			ProtocolRules: []ProtocolRule{{Host: "github.com", Protocol: ProtocolHTTPS}},
+			WorktreeLink:  []string{"node_modules", "../shared"},
+		},
+	}
+
+	var got []string
+	for _, problem := range cfg.Validate() {
+		got = append(got, problem.Error())
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{
+		`invalid match glob "acme/["`,
+		`unknown layout "flat"`,
+		"ssh_keys[0]: set match and/or host",
+		`[git].protocol: unknown protocol "ftp"`,
+		`unknown token source "vault"`,
+		`unknown token source "keychain"`,
+		`worktree_link[1]: "../shared" must be a glob inside the checkout`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Validate() = %s\nmissing %q", joined, want)
+		}
+	}
+	if len(got) != 7 {
+		t.Fatalf("Validate() returned %d problems, want 7:\n%s", len(got), joined)
+	}
+
+	if problems := (&Config{}).Validate(); len(problems) != 0 {
+		t.Fatalf("Validate() of empty config = %v", problems)
+	}
+}
+
+func TestRepoHooksCombineGlobalAndMatchingRules(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	configContent := `[hooks]
+post_clone = ["mise install"]
+trusted_repos = ["acme/*"]
+
+[[hooks.rules]]
+match = "acme/*"
+post_clone = ["direnv allow"]
+
+[[hooks.rules]]
+match = "Acme/web"
+post_clone = ["npm ci"]
+pre_open = ["git fetch"]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := strings.Join(cfg.RepoHooks(HookPostClone, "acme/web"), ";"); got != "mise install;direnv allow;npm ci" {
+		t.Errorf("RepoHooks(post-clone, acme/web) = %q", got)
+	}
+	if got := strings.Join(cfg.RepoHooks(HookPostClone, "other/tool"), ";"); got != "mise install" {
+		t.Errorf("RepoHooks(post-clone, other/tool) = %q", got)
+	}
+	if got := cfg.RepoHooks(HookPreOpen, "acme/api"); len(got) != 0 {
+		t.Errorf("RepoHooks(pre-open, acme/api) = %q, want none", got)
+	}
+	if !cfg.HasHooks() || !cfg.TrustsRepoHooks("ACME/api") || cfg.TrustsRepoHooks("other/tool") {
+		t.Errorf("HasHooks/TrustsRepoHooks gave unexpected results")
+	}
+}
@@ -0,0 +1,304 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const DefaultGitHubHost = "github.com"
+
+const (
+	TokenSourceGH            = "gh"
+	TokenSourceGitCredential = "git-credential"
+	TokenSourceCommand       = "command"
+	TokenSourceFile          = "file"
+	TokenSourceEnv           = "env"
+	TokenSourceConfig        = "config"
+)
+
+// defaultTokenSources preserves the historical lookup order: gh CLI, the
+// config value, then GITHUB_TOKEN.
+var defaultTokenSources = []string{TokenSourceGH, TokenSourceConfig, TokenSourceEnv}
+
+var defaultTokenEnv = []string{"GITHUB_TOKEN"}
+
+// TokenResolution records which source produced a token so callers can
+// report it.
+type TokenResolution struct {
+	Host   string
+	Source string
+	Token  string
+}
+
+type tokenSourceFunc func(host string, settings GitHubHostConfig) (string, error)
+
+var tokenSourceResolvers = map[string]tokenSourceFunc{
+	TokenSourceGH:            resolveGHToken,
+	TokenSourceGitCredential: cachedTokenSource(TokenSourceGitCredential, resolveGitCredentialToken),
+	TokenSourceCommand:       cachedTokenSource(TokenSourceCommand, resolveCommandToken),
+	TokenSourceFile:          cachedTokenSource(TokenSourceFile, resolveFileToken),
+	TokenSourceEnv:           resolveEnvToken,
+	TokenSourceConfig:        resolveConfigToken,
+}
+
+// ghHostTokenCache holds the token gh printed for each host, so gh runs at
+// most once per host and process.
+var (
+	ghHostTokenCache   = make(map[string]string)
+	ghHostTokenCacheMu sync.RWMutex
+)
+
+func getGitHubCLIAuthTokenForHost(host string) (string, error) {
+	ghHostTokenCacheMu.RLock()
+	if token, ok := ghHostTokenCache[host]; ok {
+		ghHostTokenCacheMu.RUnlock()
+		return token, nil
+	}
+	ghHostTokenCacheMu.RUnlock()
+
+	args := []string{"auth", "token"}
+	if host != DefaultGitHubHost {
+		args = append(args, "--hostname", host)
+	}
+	cmd := exec.Command("gh", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(output))
+
+	ghHostTokenCacheMu.Lock()
+	ghHostTokenCache[host] = token
+	ghHostTokenCacheMu.Unlock()
+
+	return token, nil
+}
+
+// sourceTokenCache holds what the git-credential, command and file sources
+// returned for each host, so a slow secret manager runs at most once per host
+// and process, like gh.
+var (
+	sourceTokenCache   = make(map[string]string)
+	sourceTokenCacheMu sync.RWMutex
+)
+
+// cachedTokenSource wraps resolve with sourceTokenCache. Failures are not
+// cached.
+func cachedTokenSource(source string, resolve tokenSourceFunc) tokenSourceFunc {
+	return func(host string, settings GitHubHostConfig) (string, error) {
+		key := strings.Join([]string{source, host, settings.TokenCommand, settings.TokenFile}, "\x00")
+		sourceTokenCacheMu.RLock()
+		if token, ok := sourceTokenCache[key]; ok {
+			sourceTokenCacheMu.RUnlock()
+			return token, nil
+		}
+		sourceTokenCacheMu.RUnlock()
+
+		token, err := resolve(host, settings)
+		if err != nil {
+			return "", err
+		}
+
+		sourceTokenCacheMu.Lock()
+		sourceTokenCache[key] = token
+		sourceTokenCacheMu.Unlock()
+
+		return token, nil
+	}
+}
+
+func (c *Config) GetGitHubToken() string {
+	return c.GetGitHubTokenForHost(DefaultGitHubHost)
+}
+
+func (c *Config) GetGitHubTokenForHost(host string) string {
+	resolution, err := c.ResolveGitHubToken(host)
+	if err != nil {
+		return ""
+	}
+	return resolution.Token
+}
+
+// ResolveGitHubToken walks the token sources configured for host in order and
+// returns the first non-empty token. When no source yields a token, the error
+// lists why each source was skipped.
+func (c *Config) ResolveGitHubToken(host string) (TokenResolution, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		host = DefaultGitHubHost
+	}
+
+	settings := c.GitHubHostSettings(host)
+	var failures []string
+	for _, source := range settings.TokenSources {
+		source = strings.TrimSpace(source)
+		resolve, ok := tokenSourceResolvers[source]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: unknown token source", source))
+			continue
+		}
+
+		token, err := resolve(host, settings)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", source, err))
+			continue
+		}
+		if token == "" {
+			failures = append(failures, fmt.Sprintf("%s: no token", source))
+			continue
+		}
+		return TokenResolution{Host: host, Source: source, Token: token}, nil
+	}
+
+	if len(failures) == 0 {
+		return TokenResolution{Host: host}, fmt.Errorf("no token sources configured for %s", host)
+	}
+	return TokenResolution{Host: host}, fmt.Errorf("no GitHub token found for %s (%s)", host, strings.Join(failures, "; "))
+}
+
+// GitHubHosts returns github.com followed by every host with a
+// [github.hosts."<host>"] section, sorted.
+func (c *Config) GitHubHosts() []string {
+	hosts := []string{DefaultGitHubHost}
+	var others []string
+	for host := range c.GitHub.Hosts {
+		if host != DefaultGitHubHost {
+			others = append(others, host)
+		}
+	}
+	sort.Strings(others)
+	return append(hosts, others...)
+}
+
+// IsGitHubHost reports whether host is github.com or a host configured
+// under [github.hosts], the hosts ezgit has tokens for.
+func (c *Config) IsGitHubHost(host string) bool {
+	host = strings.ToLower(strings.TrimSpace(host))
+	for _, known := range c.GitHubHosts() {
+		if strings.ToLower(known) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// GitHubHostSettings merges the top-level [github] settings with any
+// [github.hosts."<host>"] overrides and fills in defaults.
+func (c *Config) GitHubHostSettings(host string) GitHubHostConfig {
+	settings := GitHubHostConfig{
+		Token:        c.GitHub.Token,
+		TokenSources: c.GitHub.TokenSources,
+		TokenCommand: c.GitHub.TokenCommand,
+		TokenFile:    c.GitHub.TokenFile,
+		TokenEnv:     c.GitHub.TokenEnv,
+	}
+
+	if override, ok := c.GitHub.Hosts[host]; ok {
+		if override.Token != "" {
+			settings.Token = override.Token
+		}
+		if len(override.TokenSources) > 0 {
+			settings.TokenSources = override.TokenSources
+		}
+		if override.TokenCommand != "" {
+			settings.TokenCommand = override.TokenCommand
+		}
+		if override.TokenFile != "" {
+			settings.TokenFile = override.TokenFile
+		}
+		if len(override.TokenEnv) > 0 {
+			settings.TokenEnv = override.TokenEnv
+		}
+	}
+
+	if len(settings.TokenSources) == 0 {
+		settings.TokenSources = defaultTokenSources
+	}
+	if len(settings.TokenEnv) == 0 {
+		settings.TokenEnv = defaultTokenEnv
+	}
+	return settings
+}
+
+func resolveGHToken(host string, _ GitHubHostConfig) (string, error) {
+	return getGitHubCLIAuthTokenForHost(host)
+}
+
+func resolveGitCredentialToken(host string, _ GitHubHostConfig) (string, error) {
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if value, ok := strings.CutPrefix(line, "password="); ok {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", nil
+}
+
+func resolveCommandToken(_ string, settings GitHubHostConfig) (string, error) {
+	command := strings.TrimSpace(settings.TokenCommand)
+	if command == "" {
+		return "", errors.New("token_command not set")
+	}
+
+	cmd := exec.Command("bash", "-c", command)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return firstLine(string(output)), nil
+}
+
+func resolveFileToken(_ string, settings GitHubHostConfig) (string, error) {
+	path := strings.TrimSpace(settings.TokenFile)
+	if path == "" {
+		return "", errors.New("token_file not set")
+	}
+
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return "", err
+	}
+	return firstLine(string(data)), nil
+}
+
+func resolveEnvToken(_ string, settings GitHubHostConfig) (string, error) {
+	for _, name := range settings.TokenEnv {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+func resolveConfigToken(_ string, settings GitHubHostConfig) (string, error) {
+	return strings.TrimSpace(settings.Token), nil
+}
+
+func firstLine(value string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(value), "\n")
+	return strings.TrimSpace(line)
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
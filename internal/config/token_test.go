@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveGitHubTokenUsesConfiguredOrder(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file_token\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	t.Setenv("EZGIT_TEST_TOKEN", "env_token")
+
+	cfg := &Config{GitHub: GitHubConfig{
+		TokenSources: []string{TokenSourceFile, TokenSourceEnv},
+		TokenFile:    tokenFile,
+		TokenEnv:     []string{"EZGIT_TEST_TOKEN"},
+	}}
+
+	resolution, err := cfg.ResolveGitHubToken("")
+	if err != nil {
+		t.Fatalf("ResolveGitHubToken() error = %v", err)
+	}
+	if resolution.Token != "file_token" || resolution.Source != TokenSourceFile || resolution.Host != DefaultGitHubHost {
+		t.Fatalf("ResolveGitHubToken() = %+v, want file_token from file on github.com", resolution)
+	}
+}
+
+func TestResolveGitHubTokenFallsThroughFailingSources(t *testing.T) {
+	cfg := &Config{GitHub: GitHubConfig{
+		TokenSources: []string{TokenSourceFile, TokenSourceCommand},
+		TokenFile:    filepath.Join(t.TempDir(), "missing"),
+		TokenCommand: "printf 'cmd_token\\nsecond line'",
+	}}
+
+	resolution, err := cfg.ResolveGitHubToken(DefaultGitHubHost)
+	if err != nil {
+		t.Fatalf("ResolveGitHubToken() error = %v", err)
+	}
+	if resolution.Token != "cmd_token" || resolution.Source != TokenSourceCommand {
+		t.Fatalf("ResolveGitHubToken() = %+v, want cmd_token from command", resolution)
+	}
+}
+
+func TestResolveGitHubTokenHostOverride(t *testing.T) {
+	cfg := &Config{GitHub: GitHubConfig{
+		Token:        "default_token",
+		TokenSources: []string{TokenSourceConfig},
+		Hosts: map[string]GitHubHostConfig{
+			"github.example.com": {Token: "enterprise_token"},
+		},
+	}}
+
+	if got := cfg.GetGitHubTokenForHost("github.example.com"); got != "enterprise_token" {
+		t.Fatalf("GetGitHubTokenForHost(enterprise) = %q, want enterprise_token", got)
+	}
+	if got := cfg.GetGitHubToken(); got != "default_token" {
+		t.Fatalf("GetGitHubToken() = %q, want default_token", got)
+	}
+}
+
+func TestResolveGitHubTokenReportsEverySource(t *testing.T) {
+	cfg := &Config{GitHub: GitHubConfig{TokenSources: []string{TokenSourceCommand, "bogus"}}}
+
+	_, err := cfg.ResolveGitHubToken(DefaultGitHubHost)
+	if err == nil {
+		t.Fatal("expected error when no source yields a token")
+	}
+	for _, want := range []string{"command: token_command not set", "bogus: unknown token source"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestResolveGitHubTokenRunsCommandOncePerHost(t *testing.T) {
+	sourceTokenCache = make(map[string]string)
+	t.Cleanup(func() { sourceTokenCache = make(map[string]string) })
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	cfg := &Config{GitHub: GitHubConfig{
+		TokenSources: []string{TokenSourceCommand},
+		TokenCommand: "echo run >> " + counter + "; echo cmd_token",
+		Hosts:        map[string]GitHubHostConfig{"github.example.com": {}},
+	}}
+
+	for _, host := range []string{DefaultGitHubHost, DefaultGitHubHost, "github.example.com"} {
+		if got := cfg.GetGitHubTokenForHost(host); got != "cmd_token" {
+			t.Fatalf("GetGitHubTokenForHost(%s) = %q, want cmd_token", host, got)
+		}
+	}
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "run"); runs != 2 {
+		t.Fatalf("token_command ran %d times, want once per host", runs)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Workspace is a declarative list of repos a team expects to have cloned,
+// usually checked in as workspace.toml.
+type Workspace struct {
+	Repos []WorkspaceRepo `toml:"repos"`
+}
+
+// WorkspaceRepo describes one repo in a workspace manifest. Branch overrides
+// the default branch; Worktrees lists the worktrees to keep for the worktree
+// layout and defaults to the default branch plus review. Filter, Sparse,
+// Submodules and LFS override the matching [git].clone_rules entry.
+type WorkspaceRepo struct {
+	Name       string   `toml:"name"`
+	Layout     string   `toml:"layout"`
+	Branch     string   `toml:"branch"`
+	Depth      int      `toml:"depth"`
+	Filter     string   `toml:"filter"`
+	Sparse     []string `toml:"sparse"`
+	Submodules string   `toml:"submodules"`
+	LFS        string   `toml:"lfs"`
+	Worktrees  []string `toml:"worktrees"`
+}
+
+func LoadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace manifest: %w", err)
+	}
+
+	var ws Workspace
+	if err := toml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace manifest: %w", err)
+	}
+	if err := ws.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid workspace manifest %s: %w", path, err)
+	}
+	return &ws, nil
+}
+
+func (w *Workspace) normalize() error {
+	seen := make(map[string]bool, len(w.Repos))
+	for i := range w.Repos {
+		repo := &w.Repos[i]
+		repo.Name = strings.TrimSpace(repo.Name)
+		if _, _, err := ParseOwnerRepo(repo.Name); err != nil {
+			return fmt.Errorf("repo %q: expected owner/repo", repo.Name)
+		}
+		key := strings.ToLower(repo.Name)
+		if seen[key] {
+			return fmt.Errorf("repo %s is listed more than once", repo.Name)
+		}
+		seen[key] = true
+
+		repo.Layout = strings.ToLower(strings.TrimSpace(repo.Layout))
+		switch repo.Layout {
+		case "":
+			repo.Layout = LayoutRegular
+		case LayoutRegular, LayoutWorktree:
+		default:
+			return fmt.Errorf("repo %s: unknown layout %q (use regular or worktree)", repo.Name, repo.Layout)
+		}
+
+		repo.Branch = strings.TrimSpace(repo.Branch)
+		if repo.Depth < 0 {
+			return fmt.Errorf("repo %s: depth must not be negative", repo.Name)
+		}
+
+		worktrees := make([]string, 0, len(repo.Worktrees))
+		for _, name := range repo.Worktrees {
+			if name = strings.TrimSpace(name); name != "" {
+				worktrees = append(worktrees, name)
+			}
+		}
+		if len(worktrees) > 0 && repo.Layout != LayoutWorktree {
+			return fmt.Errorf("repo %s: worktrees require layout = %q", repo.Name, LayoutWorktree)
+		}
+		repo.Worktrees = worktrees
+	}
+	return nil
+}
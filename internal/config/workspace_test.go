@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadWorkspace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workspace.toml")
+	manifest := `
+[[repos]]
+name = "acme/api"
+
+[[repos]]
+name = "acme/mono"
+layout = "Worktree"
+branch = "develop"
+depth = 1
+worktrees = ["develop", " review ", "", "feature-x"]
+`
+	if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := LoadWorkspace(path)
+	if err != nil {
+		t.Fatalf("LoadWorkspace() error = %v", err)
+	}
+	want := []WorkspaceRepo{
+		{Name: "acme/api", Layout: LayoutRegular, Worktrees: []string{}},
+		{Name: "acme/mono", Layout: LayoutWorktree, Branch: "develop", Depth: 1, Worktrees: []string{"develop", "review", "feature-x"}},
+	}
+	if !reflect.DeepEqual(ws.Repos, want) {
+		t.Fatalf("LoadWorkspace() = %+v\nwant %+v", ws.Repos, want)
+	}
+}
+
+func TestLoadWorkspaceRejectsInvalidRepos(t *testing.T) {
+	manifests := map[string]string{
+		"bad name":       "[[repos]]\nname = \"api\"\n",
+		"duplicate":      "[[repos]]\nname = \"acme/api\"\n[[repos]]\nname = \"Acme/API\"\n",
+		"unknown layout": "[[repos]]\nname = \"acme/api\"\nlayout = \"sparse\"\n",
+		"negative depth": "[[repos]]\nname = \"acme/api\"\ndepth = -1\n",
+		"regular trees":  "[[repos]]\nname = \"acme/api\"\nworktrees = [\"main\"]\n",
+	}
+	for name, manifest := range manifests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "workspace.toml")
+			if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadWorkspace(path); err == nil {
+				t.Fatal("LoadWorkspace() expected error")
+			}
+		})
+	}
+}
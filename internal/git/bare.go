@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -49,55 +50,225 @@ func (g *gitManager) ConfigureBareRemote(barePath, defaultBranch string) error {
 	return nil
 }
 
-func (g *gitManager) ConvertToBare(path string) error {
+// ConvertPlan describes what ConvertToBare will do to a regular clone.
+type ConvertPlan struct {
+	// Branch is the branch checked out in the clone; its worktree receives
+	// the current working tree files.
+	Branch       string
+	WorktreePath string
+	// Entries are the top-level working tree entries that move.
+	Entries     []string
+	Stashes     int
+	Uncommitted bool
+}
+
+// inProgressMarkers are files in .git that mean an operation is half done.
+var inProgressMarkers = map[string]string{
+	"MERGE_HEAD":       "merge",
+	"CHERRY_PICK_HEAD": "cherry-pick",
+	"REVERT_HEAD":      "revert",
+	"rebase-merge":     "rebase",
+	"rebase-apply":     "rebase",
+	"BISECT_LOG":       "bisect",
+}
+
+// PlanConvertToBare checks that the regular clone at path can be converted
+// in place and returns what the conversion would change.
+func (g *gitManager) PlanConvertToBare(path string) (ConvertPlan, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return ConvertPlan{}, fmt.Errorf("failed to resolve path: %w", err)
+	}
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return fmt.Errorf("path does not exist: %s", path)
+		return ConvertPlan{}, fmt.Errorf("path does not exist: %s", path)
 	}
 
 	gitDir := filepath.Join(path, ".git")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		return fmt.Errorf("not a git repository: %s", path)
+	info, err := os.Stat(gitDir)
+	if os.IsNotExist(err) {
+		return ConvertPlan{}, fmt.Errorf("not a git repository: %s", path)
+	}
+	if err != nil {
+		return ConvertPlan{}, err
+	}
+	if !info.IsDir() {
+		return ConvertPlan{}, fmt.Errorf("%s is a linked worktree or submodule, not a clone", path)
+	}
+	if bare, _ := gitOutput(gitDir, "config", "--bool", "core.bare"); bare == "true" {
+		return ConvertPlan{}, fmt.Errorf("already a bare repository: %s", path)
+	}
+	if worktree, _ := gitOutput(gitDir, "config", "core.worktree"); worktree != "" {
+		return ConvertPlan{}, fmt.Errorf("core.worktree is set; convert does not support a detached working tree")
+	}
+	for marker, operation := range inProgressMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, marker)); err == nil {
+			return ConvertPlan{}, fmt.Errorf("a %s is in progress; finish or abort it first", operation)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "modules")); err == nil {
+		return ConvertPlan{}, fmt.Errorf("repository has submodules; their checkouts cannot be moved safely")
+	}
+
+	branch, err := gitOutput(path, "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil || branch == "" {
+		return ConvertPlan{}, fmt.Errorf("HEAD is detached; check out a branch first")
+	}
+	if _, err := gitOutput(path, "rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return ConvertPlan{}, fmt.Errorf("branch %s has no commits yet", branch)
 	}
 
-	parentDir := filepath.Dir(path)
-	tempBareDir, err := os.MkdirTemp(parentDir, "."+filepath.Base(path)+".bare-*")
+	plan := ConvertPlan{Branch: branch, WorktreePath: filepath.Join(path, branch)}
+	items, err := os.ReadDir(path)
 	if err != nil {
-		return fmt.Errorf("failed to create temporary directory: %w", err)
+		return ConvertPlan{}, fmt.Errorf("failed to read working tree: %w", err)
+	}
+	for _, item := range items {
+		if item.Name() != ".git" {
+			plan.Entries = append(plan.Entries, item.Name())
+		}
+	}
+
+	stashes, err := gitOutput(path, "stash", "list")
+	if err != nil {
+		return ConvertPlan{}, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	if stashes != "" {
+		plan.Stashes = len(strings.Split(stashes, "\n"))
 	}
-	if err := os.Remove(tempBareDir); err != nil {
-		return fmt.Errorf("failed to prepare temporary bare directory: %w", err)
+	status, err := gitOutput(path, "status", "--porcelain")
+	if err != nil {
+		return ConvertPlan{}, fmt.Errorf("failed to read status: %w", err)
 	}
-	cleanupTemp := true
+	plan.Uncommitted = status != ""
+	return plan, nil
+}
+
+// ConvertToBare turns the regular clone at path into the bare .git +
+// worktrees layout in place. The existing .git is kept, so stashes, hooks,
+// config and unreachable objects survive, and the working tree (including
+// uncommitted, untracked and ignored files) moves into a worktree for the
+// checked-out branch. Any failure undoes the steps taken so far.
+func (g *gitManager) ConvertToBare(path string) (err error) {
+	plan, err := g.PlanConvertToBare(path)
+	if err != nil {
+		return err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	gitDir := filepath.Join(path, ".git")
+
+	stagingDir, err := os.MkdirTemp(path, ".ezgit-convert-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	var tx rollback
 	defer func() {
-		if cleanupTemp {
-			_ = os.RemoveAll(tempBareDir)
+		if err != nil {
+			err = tx.undo(err)
+			_ = os.Remove(stagingDir)
 		}
 	}()
 
-	cloneArgs := []string{"clone", "--bare", path, tempBareDir}
-	cmd := exec.Command("git", cloneArgs...)
+	for _, entry := range plan.Entries {
+		if err := tx.move(filepath.Join(path, entry), filepath.Join(stagingDir, entry)); err != nil {
+			return err
+		}
+	}
+
+	if err := runGitCommand(gitDir, "config", "core.bare", "true"); err != nil {
+		return fmt.Errorf("failed to set core.bare: %w", err)
+	}
+	tx.add(func() error { return runGitCommand(gitDir, "config", "core.bare", "false") })
+
+	// Add the worktree without a checkout: its files are the ones being
+	// moved, and runWorktreeAdd would apply sparse patterns on top of them.
+	cmd := exec.Command("git", "worktree", "add", "--no-checkout", plan.WorktreePath, plan.Branch)
+	cmd.Dir = gitDir
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to create bare clone: %w\n%s", err, string(output))
+		return fmt.Errorf("failed to create worktree: %w\n%s", err, string(output))
 	}
+	tx.add(func() error {
+		if err := removeDirAndEmptyParents(plan.WorktreePath, path); err != nil {
+			return err
+		}
+		return g.PruneWorktrees(gitDir)
+	})
 
-	items, err := os.ReadDir(path)
+	adminDir, err := gitOutput(plan.WorktreePath, "rev-parse", "--absolute-git-dir")
 	if err != nil {
-		return fmt.Errorf("failed to read original repository: %w", err)
+		return fmt.Errorf("failed to locate worktree metadata: %w", err)
+	}
+	// The index carries staged changes; sparse-checkout patterns are per
+	// worktree and live next to it.
+	for _, name := range []string{"index", filepath.Join("info", "sparse-checkout")} {
+		src := filepath.Join(gitDir, name)
+		if _, statErr := os.Stat(src); statErr != nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(adminDir, name)), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(filepath.Join(adminDir, name)), err)
+		}
+		if err := tx.move(src, filepath.Join(adminDir, name)); err != nil {
+			return err
+		}
 	}
 
-	for _, item := range items {
-		itemPath := filepath.Join(path, item.Name())
-		if err := os.RemoveAll(itemPath); err != nil {
-			return fmt.Errorf("failed to remove %s: %w", itemPath, err)
+	for _, entry := range plan.Entries {
+		if err := tx.move(filepath.Join(stagingDir, entry), filepath.Join(plan.WorktreePath, entry)); err != nil {
+			return err
 		}
 	}
 
-	targetGitDir := filepath.Join(path, ".git")
-	if err := os.Rename(tempBareDir, targetGitDir); err != nil {
-		return fmt.Errorf("failed to move bare repository into %s: %w", targetGitDir, err)
+	if err := os.Remove(stagingDir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", stagingDir, err)
+	}
+	return nil
+}
+
+// rollback records the inverse of each step of a multi-step filesystem
+// change so a failure part way through can be undone.
+type rollback struct {
+	steps []func() error
+}
+
+func (r *rollback) add(undo func() error) {
+	r.steps = append(r.steps, undo)
+}
+
+// move renames from to to and records the reverse rename.
+func (r *rollback) move(from, to string) error {
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to move %s: %w", from, err)
+	}
+	r.add(func() error { return os.Rename(to, from) })
+	return nil
+}
+
+// undo runs the recorded steps in reverse and returns cause joined with any
+// step that failed.
+func (r *rollback) undo(cause error) error {
+	for i := len(r.steps) - 1; i >= 0; i-- {
+		if err := r.steps[i](); err != nil {
+			cause = errors.Join(cause, fmt.Errorf("rollback failed: %w", err))
+		}
 	}
-	cleanupTemp = false
+	r.steps = nil
+	return cause
+}
 
+// removeDirAndEmptyParents removes dir and then each parent left empty, up to
+// but not including stop. Branches with slashes nest worktree directories.
+func removeDirAndEmptyParents(dir, stop string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for parent := filepath.Dir(dir); parent != stop && strings.HasPrefix(parent, stop+string(filepath.Separator)); parent = filepath.Dir(parent) {
+		if err := os.Remove(parent); err != nil {
+			break
+		}
+	}
 	return nil
 }
 
@@ -133,7 +304,21 @@ func (g *gitManager) runWorktreeAdd(barePath, worktreePath string, prePathArgs [
 		return nil
 	}
 
-	gitArgs := []string{"worktree", "add"}
+	sparse, err := g.SparsePatterns(barePath)
+	if err != nil {
+		return err
+	}
+
+	var gitArgs []string
+	if len(prePathArgs) > 0 && prePathArgs[0] == "-b" {
+		// Bare repos keep no reflogs by default. One for the new branch
+		// records where it started, which IsBranchMerged relies on.
+		gitArgs = append(gitArgs, "-c", "core.logAllRefUpdates=true")
+	}
+	gitArgs = append(gitArgs, "worktree", "add")
+	if len(sparse) > 0 {
+		gitArgs = append(gitArgs, "--no-checkout")
+	}
 	gitArgs = append(gitArgs, prePathArgs...)
 	gitArgs = append(gitArgs, absWorktreePath)
 	gitArgs = append(gitArgs, postPathArgs...)
@@ -151,9 +336,36 @@ func (g *gitManager) runWorktreeAdd(barePath, worktreePath string, prePathArgs [
 		return fmt.Errorf("failed to create worktree: %w\n%s", err, string(output))
 	}
 
+	if err := g.populateWorktree(barePath, absWorktreePath, sparse); err != nil {
+		// Later runs take a registered worktree as finished and would never
+		// fetch its submodules or LFS content, so drop it and its branch.
+		if removeErr := g.RemoveWorktree(barePath, absWorktreePath, true); removeErr != nil {
+			return fmt.Errorf("%w\nremoving the incomplete worktree also failed: %v", err, removeErr)
+		}
+		if len(prePathArgs) == 2 && prePathArgs[0] == "-b" {
+			_ = runGitCommand(barePath, "branch", "-D", prePathArgs[1])
+		}
+		return err
+	}
 	return nil
 }
 
+// populateWorktree applies the repo's sparse patterns and checks out
+// submodules and LFS objects in a freshly added worktree.
+func (g *gitManager) populateWorktree(barePath, worktreePath string, sparse []string) error {
+	if len(sparse) > 0 {
+		if err := checkoutSparse(worktreePath, sparse); err != nil {
+			return err
+		}
+	}
+
+	extras, err := g.RepoCheckoutExtras(barePath)
+	if err != nil {
+		return err
+	}
+	return populateCheckout(worktreePath, extras)
+}
+
 func (g *gitManager) isWorktreeRegistered(barePath, worktreePath string) (bool, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
 	cmd.Dir = barePath
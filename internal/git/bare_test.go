@@ -402,3 +402,93 @@ func TestCreateDetachedWorktreeFailsWhenPathExistsButNotRegistered(t *testing.T)
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestConvertToBarePreservesUncommittedWork(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+
+	runGit(t, "", "init", "-b", "main", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "test")
+	runGit(t, repoDir, "remote", "add", "origin", "git@github.com:acme/widgets.git")
+	makeCommit(t, repoDir, ".gitignore", "build/\n", "ignore build")
+	makeCommit(t, repoDir, "README.md", "hello\n", "initial commit")
+	runGit(t, repoDir, "checkout", "-b", "feature/x")
+
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("stashed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repoDir, "stash")
+	if err := os.WriteFile(filepath.Join(repoDir, "staged.txt"), []byte("staged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repoDir, "add", "staged.txt")
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("modified\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "untracked.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, "build"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "build", "cache"), []byte("cache\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gitMgr := New()
+	plan, err := gitMgr.PlanConvertToBare(repoDir)
+	if err != nil {
+		t.Fatalf("PlanConvertToBare() error = %v", err)
+	}
+	wantEntries := []string{".gitignore", "README.md", "build", "staged.txt", "untracked.txt"}
+	if plan.Branch != "feature/x" || plan.Stashes != 1 || !plan.Uncommitted || strings.Join(plan.Entries, ",") != strings.Join(wantEntries, ",") {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	if err := gitMgr.ConvertToBare(repoDir); err != nil {
+		t.Fatalf("ConvertToBare() error = %v", err)
+	}
+
+	worktreeDir := filepath.Join(repoDir, "feature", "x")
+	if got := strings.TrimSpace(runGit(t, worktreeDir, "status", "--porcelain")); got != "M README.md\nA  staged.txt\n?? untracked.txt" {
+		t.Fatalf("status = %q", got)
+	}
+	if data, err := os.ReadFile(filepath.Join(worktreeDir, "build", "cache")); err != nil || string(data) != "cache\n" {
+		t.Fatalf("ignored file not moved: %q, %v", data, err)
+	}
+	if got := strings.TrimSpace(runGit(t, worktreeDir, "stash", "list")); !strings.Contains(got, "stash@{0}") {
+		t.Fatalf("stash lost: %q", got)
+	}
+	if got := strings.TrimSpace(runGit(t, worktreeDir, "remote", "get-url", "origin")); got != "git@github.com:acme/widgets.git" {
+		t.Fatalf("origin = %q", got)
+	}
+	if out := strings.TrimSpace(runGit(t, filepath.Join(repoDir, ".git"), "rev-parse", "--is-bare-repository")); out != "true" {
+		t.Fatalf("is-bare-repository = %q, want true", out)
+	}
+	entries, err := os.ReadDir(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("repo root should only hold .git and feature/, got %d entries", len(entries))
+	}
+}
+
+func TestConvertToBareRefusesDetachedHead(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoDir := filepath.Join(tmpDir, "repo")
+
+	runGit(t, "", "init", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "test")
+	makeCommit(t, repoDir, "README.md", "hello\n", "initial commit")
+	runGit(t, repoDir, "checkout", "--detach")
+
+	if err := New().ConvertToBare(repoDir); err == nil || !strings.Contains(err.Error(), "detached") {
+		t.Fatalf("expected detached HEAD error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "README.md")); err != nil {
+		t.Fatalf("working tree was touched: %v", err)
+	}
+}
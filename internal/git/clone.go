@@ -2,17 +2,55 @@ package git
 
 import (
 	"fmt"
+	"net/url"
+	"os"
 	"os/exec"
 	"strings"
 )
 
 func (g *gitManager) Clone(url, path string, opts CloneOptions) error {
-	var args []string
+	if opts.SSHKeyPath != "" {
+		if err := g.ValidateSSHKey(opts.SSHKeyPath); err != nil {
+			return fmt.Errorf("invalid SSH key: %w", err)
+		}
+	}
+	args, err := cloneArgs(url, path, opts)
+	if err != nil {
+		return err
+	}
 
+	cmd := exec.Command("git", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("git clone failed: %w\n%s", err, string(output))
+		}
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	if len(opts.Sparse) > 0 {
+		if err := recordSparsePatterns(path, opts.Sparse); err != nil {
+			return err
+		}
+		// A bare clone has no checkout; its worktrees apply the patterns
+		// when they are added.
+		if !opts.Bare {
+			if err := checkoutSparse(path, opts.Sparse); err != nil {
+				return err
+			}
+		}
+	}
+	if !opts.Bare {
+		return populateCheckout(path, opts.CheckoutExtras)
+	}
+	return nil
+}
+
+// cloneArgs returns the git arguments Clone runs for url, path and opts.
+func cloneArgs(url, path string, opts CloneOptions) ([]string, error) {
+	args := []string{"clone"}
 	if opts.Bare {
-		args = append(args, "clone", "--bare")
-	} else {
-		args = append(args, "clone")
+		args = append(args, "--bare")
 	}
 
 	if opts.Branch != "" {
@@ -23,46 +61,152 @@ func (g *gitManager) Clone(url, path string, opts CloneOptions) error {
 		args = append(args, "--depth", fmt.Sprintf("%d", opts.Depth))
 	}
 
+	if opts.Filter != "" {
+		if err := ValidateCloneFilter(opts.Filter); err != nil {
+			return nil, err
+		}
+		args = append(args, "--filter="+opts.Filter)
+	}
+
+	if len(opts.Sparse) > 0 && !opts.Bare {
+		args = append(args, "--sparse")
+	}
+
 	if opts.Quiet {
 		args = append(args, "--quiet")
 	}
 
 	if opts.SSHKeyPath != "" {
-		if err := g.ValidateSSHKey(opts.SSHKeyPath); err != nil {
-			return fmt.Errorf("invalid SSH key: %w", err)
-		}
-		args = append(args, "--config", fmt.Sprintf("core.sshCommand=ssh -i %s -o IdentitiesOnly=yes", opts.SSHKeyPath))
+		args = append(args, "--config", "core.sshCommand="+SSHCommand(opts.SSHKeyPath))
 	}
 
-	args = append(args, url, path)
+	if err := ValidateCheckoutExtras(opts.CheckoutExtras); err != nil {
+		return nil, err
+	}
+	for _, setting := range checkoutExtrasConfig(opts.CheckoutExtras) {
+		args = append(args, "--config", setting)
+	}
 
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		if len(output) > 0 {
-			return fmt.Errorf("git clone failed: %w\n%s", err, string(output))
-		}
-		return fmt.Errorf("git clone failed: %w", err)
+	for _, setting := range opts.Config {
+		args = append(args, "--config", setting)
 	}
-	return nil
+
+	return append(args, url, path), nil
 }
 
+// ParseRepoURL returns the clone URL for a full URL, or for owner/repo on
+// github.com over SSH.
 func ParseRepoURL(input string) (string, error) {
+	return RepoURL(input, false)
+}
+
+// RepoURL is ParseRepoURL with owner/repo and host/owner/repo cloned over
+// HTTPS when useHTTPS is set. Full URLs keep their own protocol.
+func RepoURL(input string, useHTTPS bool) (string, error) {
 	input = strings.TrimSpace(input)
 
-	if strings.HasPrefix(input, "git@") || strings.HasPrefix(input, "https://") || strings.HasPrefix(input, "http://") {
+	if strings.HasPrefix(input, "git@") || strings.Contains(input, "://") {
 		if !strings.HasSuffix(input, ".git") {
 			input += ".git"
 		}
 		return input, nil
 	}
 
-	if strings.Contains(input, "/") {
-		parts := strings.Split(input, "/")
-		if len(parts) == 2 {
-			return fmt.Sprintf("git@github.com:%s/%s.git", parts[0], parts[1]), nil
-		}
+	if ref, ok := ParseRepoRef(input); ok {
+		return ref.URL(useHTTPS), nil
 	}
 
 	return "", fmt.Errorf("invalid repo format: %s (expected owner/repo or full URL)", input)
 }
+
+// RepoRef is a repo input split into the host it lives on and owner/name.
+type RepoRef struct {
+	Host     string
+	FullName string
+}
+
+// ParseRepoRef parses owner/repo (on github.com), host/owner/repo,
+// git@host:owner/repo and http(s):// or ssh:// URLs whose path is
+// owner/repo. ok is false for anything else, such as URLs with a port or
+// nested groups, which are only usable as given.
+func ParseRepoRef(input string) (RepoRef, bool) {
+	input = strings.TrimSpace(input)
+	host, repoPath := "", ""
+	switch {
+	case strings.Contains(input, "://"):
+		parsed, err := url.Parse(input)
+		if err != nil || parsed.Port() != "" {
+			return RepoRef{}, false
+		}
+		switch parsed.Scheme {
+		case "https", "http", "ssh":
+		default:
+			return RepoRef{}, false
+		}
+		host, repoPath = parsed.Hostname(), strings.TrimPrefix(parsed.Path, "/")
+	case strings.HasPrefix(input, "git@"):
+		hostPart, pathPart, found := strings.Cut(strings.TrimPrefix(input, "git@"), ":")
+		if !found {
+			return RepoRef{}, false
+		}
+		host, repoPath = hostPart, pathPart
+	default:
+		repoPath = input
+		if first, rest, found := strings.Cut(input, "/"); found && strings.Contains(first, ".") {
+			host, repoPath = first, rest
+		}
+	}
+	if host == "" {
+		host = "github.com"
+	}
+
+	repoPath = strings.TrimSuffix(strings.TrimSuffix(repoPath, "/"), ".git")
+	parts := strings.Split(repoPath, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.ContainsAny(host, "/:@") {
+		return RepoRef{}, false
+	}
+	return RepoRef{Host: strings.ToLower(host), FullName: parts[0] + "/" + parts[1]}, true
+}
+
+// URL returns the clone URL of the repo over HTTPS or SSH.
+func (r RepoRef) URL(useHTTPS bool) string {
+	if useHTTPS {
+		return fmt.Sprintf("https://%s/%s.git", r.Host, r.FullName)
+	}
+	return fmt.Sprintf("git@%s:%s.git", r.Host, r.FullName)
+}
+
+// HTTPSHost returns the host of an http(s) remote URL; ok is false for
+// other transports.
+func HTTPSHost(remoteURL string) (host string, ok bool) {
+	parsed, err := url.Parse(strings.TrimSpace(remoteURL))
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Hostname() == "" {
+		return "", false
+	}
+	return parsed.Hostname(), true
+}
+
+// ProbeHTTPS runs 'git ls-remote' against repoURL with the given "key=value"
+// git config, as stored in an HTTPS clone, and never prompts for
+// credentials. The error carries the first line git printed.
+func ProbeHTTPS(repoURL string, config []string) error {
+	var args []string
+	for _, setting := range config {
+		args = append(args, "-c", setting)
+	}
+	args = append(args, "ls-remote", repoURL, "HEAD")
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_HTTP_LOW_SPEED_LIMIT=1", "GIT_HTTP_LOW_SPEED_TIME=10")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	message := strings.TrimSpace(string(output))
+	if line, _, found := strings.Cut(message, "\n"); found {
+		message = line
+	}
+	if message == "" {
+		message = err.Error()
+	}
+	return fmt.Errorf("git ls-remote %s failed: %s", repoURL, message)
+}
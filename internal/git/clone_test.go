@@ -56,3 +56,45 @@ func TestParseRepoURL(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoURLAndHTTPSHost(t *testing.T) {
+	if got, err := RepoURL("acme/api", true); err != nil || got != "https://github.com/acme/api.git" {
+		t.Fatalf("RepoURL(https) = %q, %v", got, err)
+	}
+	if got, err := RepoURL("acme/api", false); err != nil || got != "git@github.com:acme/api.git" {
+		t.Fatalf("RepoURL(ssh) = %q, %v", got, err)
+	}
+	if host, ok := HTTPSHost("https://ghe.example.com/acme/api.git"); !ok || host != "ghe.example.com" {
+		t.Fatalf("HTTPSHost() = %q, %v", host, ok)
+	}
+	if _, ok := HTTPSHost("git@github.com:acme/api.git"); ok {
+		t.Fatal("HTTPSHost() accepted an scp-style ssh URL")
+	}
+}
+
+func TestParseRepoRef(t *testing.T) {
+	tests := []struct {
+		input string
+		want  RepoRef
+		ok    bool
+	}{
+		{input: "acme/api", want: RepoRef{Host: "github.com", FullName: "acme/api"}, ok: true},
+		{input: "GHE.example.com/acme/api", want: RepoRef{Host: "ghe.example.com", FullName: "acme/api"}, ok: true},
+		{input: "git@ghe.example.com:acme/api.git", want: RepoRef{Host: "ghe.example.com", FullName: "acme/api"}, ok: true},
+		{input: "https://github.com/acme/api/", want: RepoRef{Host: "github.com", FullName: "acme/api"}, ok: true},
+		{input: "ssh://git@ghe.example.com/acme/api.git", want: RepoRef{Host: "ghe.example.com", FullName: "acme/api"}, ok: true},
+		{input: "https://git.example.com:8443/acme/api.git", ok: false},
+		{input: "https://gitlab.com/group/sub/api.git", ok: false},
+		{input: "file:///srv/acme/api.git", ok: false},
+		{input: "api", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRepoRef(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseRepoRef(%q) = %+v, %v; want %+v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+	if got := (RepoRef{Host: "ghe.example.com", FullName: "acme/api"}).URL(false); got != "git@ghe.example.com:acme/api.git" {
+		t.Fatalf("RepoRef.URL(ssh) = %q", got)
+	}
+}
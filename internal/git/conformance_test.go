@@ -0,0 +1,130 @@
+package git
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// conformanceBackends lists every GitManager implementation; each read query
+// is checked against all of them so they stay interchangeable.
+var conformanceBackends = map[string]func() GitManager{
+	BackendExec:   New,
+	BackendNative: NewNative,
+}
+
+type conformanceFixture struct {
+	root     string
+	bareRoot string
+	regular  string
+}
+
+func setupConformanceFixture(t *testing.T) conformanceFixture {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+	runGit(t, "", "init", "--bare", originDir)
+	runGit(t, "", "clone", originDir, seedDir)
+	runGit(t, seedDir, "config", "user.email", "test@example.com")
+	runGit(t, seedDir, "config", "user.name", "test")
+	makeCommit(t, seedDir, "README.md", "hello\n", "initial commit")
+	runGit(t, seedDir, "push", "origin", "HEAD:main")
+	runGit(t, seedDir, "checkout", "-b", "feature-a")
+	makeCommit(t, seedDir, "a.txt", "a\n", "feature-a commit")
+	runGit(t, seedDir, "push", "origin", "feature-a")
+	runGit(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	// Bare + worktree layout, with refs packed to exercise packed-refs.
+	bareRoot := filepath.Join(tmpDir, "bare")
+	metadataPath := filepath.Join(bareRoot, ".git")
+	runGit(t, "", "clone", "--bare", originDir, metadataPath)
+	if err := New().ConfigureBareRemote(metadataPath, "main"); err != nil {
+		t.Fatalf("ConfigureBareRemote() error = %v", err)
+	}
+	runGit(t, metadataPath, "pack-refs", "--all")
+	runGit(t, metadataPath, "worktree", "add", filepath.Join(bareRoot, "main"), "main")
+	runGit(t, metadataPath, "worktree", "add", "--detach", filepath.Join(bareRoot, "review"), "main")
+	runGit(t, metadataPath, "worktree", "add", "-b", "nested/feature", filepath.Join(bareRoot, "nested", "feature"), "main")
+
+	// Regular clone with one linked worktree outside the repo root.
+	regular := filepath.Join(tmpDir, "regular")
+	runGit(t, "", "clone", originDir, regular)
+	runGit(t, regular, "worktree", "add", "-b", "side", filepath.Join(tmpDir, "side"), "origin/feature-a")
+
+	return conformanceFixture{root: tmpDir, bareRoot: bareRoot, regular: regular}
+}
+
+func TestBackendConformance(t *testing.T) {
+	fixture := setupConformanceFixture(t)
+
+	for name, newBackend := range conformanceBackends {
+		t.Run(name, func(t *testing.T) {
+			gitMgr := newBackend()
+
+			assertSortedEqual(t, "ListWorktrees(bare root)", mustList(t, gitMgr.ListWorktrees, fixture.bareRoot), []string{"main", "nested/feature", "review"})
+			assertSortedEqual(t, "ListWorktrees(bare metadata)", mustList(t, gitMgr.ListWorktrees, filepath.Join(fixture.bareRoot, ".git")), []string{"bare", "feature", "main", "review"})
+			assertSortedEqual(t, "ListWorktrees(regular)", mustList(t, gitMgr.ListWorktrees, fixture.regular), []string{"side"})
+			assertSortedEqual(t, "ListWorktrees(linked worktree)", mustList(t, gitMgr.ListWorktrees, filepath.Join(fixture.root, "side")), []string{"regular"})
+
+			hasWorktrees, err := gitMgr.HasWorktrees(fixture.bareRoot)
+			if err != nil || !hasWorktrees {
+				t.Fatalf("HasWorktrees(bare root) = %v, %v; want true", hasWorktrees, err)
+			}
+
+			assertSortedEqual(t, "ListBranches(bare metadata)", mustList(t, gitMgr.ListBranches, filepath.Join(fixture.bareRoot, ".git")), []string{"feature-a", "main", "nested/feature"})
+			assertSortedEqual(t, "ListBranches(regular)", mustList(t, gitMgr.ListBranches, fixture.regular), []string{"feature-a", "main", "side"})
+
+			for path, want := range map[string]string{
+				filepath.Join(fixture.bareRoot, "main"):              "main",
+				filepath.Join(fixture.bareRoot, "review"):            "",
+				filepath.Join(fixture.bareRoot, "nested", "feature"): "nested/feature",
+				fixture.regular:                     "main",
+				filepath.Join(fixture.root, "side"): "side",
+			} {
+				got, err := gitMgr.CurrentBranch(path)
+				if err != nil {
+					t.Fatalf("CurrentBranch(%s) error = %v", path, err)
+				}
+				if got != want {
+					t.Fatalf("CurrentBranch(%s) = %q, want %q", path, got, want)
+				}
+			}
+
+			if _, err := gitMgr.ListWorktrees(t.TempDir()); err == nil {
+				t.Fatal("ListWorktrees(non-repo) expected error")
+			}
+		})
+	}
+}
+
+func TestNewWithBackend(t *testing.T) {
+	if _, ok := NewWithBackend("native").(*nativeGitManager); !ok {
+		t.Fatal("NewWithBackend(native) did not return the native backend")
+	}
+	for _, name := range []string{"", "exec", "unknown"} {
+		if _, ok := NewWithBackend(name).(*gitManager); !ok {
+			t.Fatalf("NewWithBackend(%q) did not return the exec backend", name)
+		}
+	}
+}
+
+func mustList(t *testing.T, list func(string) ([]string, error), path string) []string {
+	t.Helper()
+	values, err := list(path)
+	if err != nil {
+		t.Fatalf("list %s error = %v", path, err)
+	}
+	return values
+}
+
+func assertSortedEqual(t *testing.T, label string, got, want []string) {
+	t.Helper()
+	got = append([]string(nil), got...)
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s = %v, want %v", label, got, want)
+	}
+}
@@ -0,0 +1,439 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kirksw/ezgit/internal/journal"
+	"github.com/kirksw/ezgit/internal/utils"
+)
+
+// DryRun is a GitManager that prints and records the commands each mutating
+// call would run instead of running them. Reads go to the wrapped manager,
+// except for clones and worktrees that so far only exist in the plan.
+type DryRun struct {
+	GitManager
+	out io.Writer
+
+	mu        sync.Mutex
+	steps     []string
+	clones    []string
+	sparse    map[string][]string
+	extras    map[string]CheckoutExtras
+	worktrees []plannedWorktree
+}
+
+type plannedWorktree struct {
+	// root is the repo directory the worktree is listed under.
+	root   string
+	path   string
+	branch string
+}
+
+// NewDryRun returns a DryRun that reads through gitMgr and prints each
+// planned step to out.
+func NewDryRun(gitMgr GitManager, out io.Writer) *DryRun {
+	return &DryRun{GitManager: gitMgr, out: out, sparse: make(map[string][]string), extras: make(map[string]CheckoutExtras)}
+}
+
+// Note records a planned step that is not a git command, such as creating
+// a directory or running the open command.
+func (d *DryRun) Note(step string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.steps = append(d.steps, step)
+	fmt.Fprintf(d.out, "[dry-run] %s\n", step)
+}
+
+// Steps returns every step planned so far, in order.
+func (d *DryRun) Steps() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.steps...)
+}
+
+func (d *DryRun) run(dir string, args ...string) {
+	if dir != "" {
+		args = append([]string{"-C", absPath(dir)}, args...)
+	}
+	d.Note("git " + utils.ShellJoin(args...))
+}
+
+func (d *DryRun) Clone(url, path string, opts CloneOptions) error {
+	if opts.SSHKeyPath != "" {
+		if err := d.GitManager.ValidateSSHKey(opts.SSHKeyPath); err != nil {
+			return fmt.Errorf("invalid SSH key: %w", err)
+		}
+	}
+	args, err := cloneArgs(url, absPath(path), opts)
+	if err != nil {
+		return err
+	}
+	d.run("", args...)
+
+	d.mu.Lock()
+	d.clones = append(d.clones, absPath(path))
+	if len(opts.Sparse) > 0 {
+		d.sparse[plannedRepoRoot(path)] = opts.Sparse
+	}
+	d.extras[plannedRepoRoot(path)] = opts.CheckoutExtras
+	d.mu.Unlock()
+
+	for _, pattern := range opts.Sparse {
+		d.run(path, "config", "--add", sparseConfigKey, pattern)
+	}
+	if len(opts.Sparse) > 0 && !opts.Bare {
+		d.run(path, append([]string{"sparse-checkout", "set", "--cone", "--"}, opts.Sparse...)...)
+		d.run(path, "checkout", "--quiet")
+	}
+	if !opts.Bare {
+		for _, args := range checkoutExtrasSteps(opts.CheckoutExtras) {
+			d.run(path, args...)
+		}
+	}
+	return nil
+}
+
+func (d *DryRun) ConfigureBareRemote(barePath, defaultBranch string) error {
+	d.run(barePath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	d.run(barePath, "fetch", "origin")
+
+	if d.plannedOnly(barePath) {
+		d.Note(fmt.Sprintf("delete every local branch of %s except %s", absPath(barePath), defaultBranch))
+		return nil
+	}
+	heads, err := branchHeads(barePath)
+	if err != nil {
+		return fmt.Errorf("failed to list local branches: %w", err)
+	}
+	for _, branch := range sortedKeys(heads) {
+		if branch != defaultBranch {
+			d.run(barePath, "branch", "-D", branch)
+		}
+	}
+	return nil
+}
+
+func (d *DryRun) ConfigureSSHKey(path, keyPath string) error {
+	if err := d.GitManager.ValidateSSHKey(keyPath); err != nil {
+		return fmt.Errorf("invalid SSH key: %w", err)
+	}
+	d.run(path, "config", "core.sshCommand", SSHCommand(keyPath))
+	return nil
+}
+
+func (d *DryRun) ConvertToBare(path string) error {
+	plan, err := d.GitManager.PlanConvertToBare(path)
+	if err != nil {
+		return err
+	}
+	root := absPath(path)
+	gitDir := filepath.Join(root, ".git")
+	d.run(gitDir, "config", "core.bare", "true")
+	d.run(gitDir, "worktree", "add", "--no-checkout", plan.WorktreePath, plan.Branch)
+	for _, entry := range plan.Entries {
+		d.Note("mv " + utils.ShellJoin(filepath.Join(root, entry), filepath.Join(plan.WorktreePath, entry)))
+	}
+
+	d.mu.Lock()
+	d.worktrees = append(d.worktrees, plannedWorktree{root: root, path: absPath(plan.WorktreePath), branch: plan.Branch})
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *DryRun) ConvertToRegular(path, worktreePath string) error {
+	d.Note(fmt.Sprintf("convert %s to a regular clone with %s checked out, deleting its other worktrees", absPath(path), absPath(worktreePath)))
+	return nil
+}
+
+func (d *DryRun) CreateWorktree(barePath, worktreePath, branch string) error {
+	return d.addWorktree(barePath, worktreePath, branch, nil, []string{branch})
+}
+
+func (d *DryRun) CreateDetachedWorktree(barePath, worktreePath, startPoint string) error {
+	return d.addWorktree(barePath, worktreePath, "", []string{"--detach"}, []string{startPoint})
+}
+
+func (d *DryRun) CreateFeatureWorktree(barePath, worktreePath, featureBranch, baseBranch string) error {
+	return d.addWorktree(barePath, worktreePath, featureBranch, []string{"-b", featureBranch}, []string{baseBranch})
+}
+
+// addWorktree mirrors runWorktreeAdd, including its no-op for a worktree
+// that is already registered.
+func (d *DryRun) addWorktree(barePath, worktreePath, branch string, prePathArgs, postPathArgs []string) error {
+	path := absPath(worktreePath)
+	if d.plannedWorktree(path) != nil {
+		return nil
+	}
+	if !d.plannedOnly(barePath) {
+		registered, err := (&gitManager{}).isWorktreeRegistered(barePath, path)
+		if err != nil {
+			return fmt.Errorf("failed to inspect existing worktrees: %w", err)
+		}
+		if registered {
+			return nil
+		}
+	}
+
+	sparse, err := d.SparsePatterns(barePath)
+	if err != nil {
+		return err
+	}
+	args := []string{"worktree", "add"}
+	if len(sparse) > 0 {
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, prePathArgs...)
+	args = append(args, path)
+	args = append(args, postPathArgs...)
+	d.run(barePath, args...)
+	if len(sparse) > 0 {
+		d.run(path, append([]string{"sparse-checkout", "set", "--cone", "--"}, sparse...)...)
+		d.run(path, "checkout", "--quiet")
+	}
+	extras, err := d.RepoCheckoutExtras(barePath)
+	if err != nil {
+		return err
+	}
+	for _, args := range checkoutExtrasSteps(extras) {
+		d.run(path, args...)
+	}
+
+	d.mu.Lock()
+	d.worktrees = append(d.worktrees, plannedWorktree{root: plannedRepoRoot(barePath), path: path, branch: branch})
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *DryRun) RemoveWorktree(barePath, worktreePath string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force", "--force")
+	}
+	d.run(barePath, append(args, absPath(worktreePath))...)
+
+	d.mu.Lock()
+	path := absPath(worktreePath)
+	kept := d.worktrees[:0]
+	for _, wt := range d.worktrees {
+		if wt.path != path {
+			kept = append(kept, wt)
+		}
+	}
+	d.worktrees = kept
+	d.mu.Unlock()
+
+	return d.PruneWorktrees(barePath)
+}
+
+func (d *DryRun) MoveWorktree(barePath, worktreePath, newPath string) error {
+	oldPath, newPath := absPath(worktreePath), absPath(newPath)
+	d.run(barePath, "worktree", "move", oldPath, newPath)
+
+	d.mu.Lock()
+	for i := range d.worktrees {
+		if d.worktrees[i].path == oldPath {
+			d.worktrees[i].path = newPath
+		}
+	}
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *DryRun) PruneWorktrees(path string) error {
+	d.run(path, "worktree", "prune")
+	return nil
+}
+
+func (d *DryRun) DeleteBranch(path, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	d.run(path, "branch", flag, branch)
+	return nil
+}
+
+func (d *DryRun) RenameBranch(path, branch, newBranch string) error {
+	d.run(path, "branch", "-m", branch, newBranch)
+	return nil
+}
+
+func (d *DryRun) FetchPrune(path string) error {
+	d.run(path, "fetch", "--prune", "origin")
+	return nil
+}
+
+func (d *DryRun) FastForward(worktreePath string) error {
+	d.run(worktreePath, "merge", "--ff-only", "--quiet", "@{upstream}")
+	return nil
+}
+
+func (d *DryRun) FetchPullRequest(path string, number int) (string, error) {
+	ref := PullRequestRef(number)
+	d.run(path, "fetch", "--quiet", "origin", "+"+ref+":"+ref)
+	return ref, nil
+}
+
+func (d *DryRun) CheckoutDetached(worktreePath, ref string) error {
+	d.run(worktreePath, "checkout", "--quiet", "--detach", ref)
+	return nil
+}
+
+func (d *DryRun) Unshallow(path string) error {
+	d.run(path, "fetch", "--unshallow", "--quiet", "origin")
+	return nil
+}
+
+func (d *DryRun) Deepen(path string, depth int, since string) error {
+	args, err := deepenArgs(depth, since)
+	if err != nil {
+		return err
+	}
+	d.run(path, append([]string{"fetch"}, args...)...)
+	return nil
+}
+
+func (d *DryRun) UndoAction(action journal.Action) error {
+	d.Note("undo: " + action.Describe())
+	return nil
+}
+
+func (d *DryRun) HasWorktrees(path string) (bool, error) {
+	worktrees, err := d.ListWorktrees(path)
+	if err != nil {
+		return false, err
+	}
+	return len(worktrees) > 0, nil
+}
+
+// ListWorktrees adds the planned worktrees of path to the existing ones.
+func (d *DryRun) ListWorktrees(path string) ([]string, error) {
+	var worktrees []string
+	if !d.plannedOnly(path) {
+		existing, err := d.GitManager.ListWorktrees(path)
+		if err != nil {
+			return nil, err
+		}
+		worktrees = existing
+	}
+
+	root := plannedRepoRoot(path)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, wt := range d.worktrees {
+		if wt.root != root {
+			continue
+		}
+		if name, ok := worktreeNameForPath(absPath(path), wt.path); ok && !containsName(worktrees, name) {
+			worktrees = append(worktrees, name)
+		}
+	}
+	return worktrees, nil
+}
+
+func (d *DryRun) ListBranches(path string) ([]string, error) {
+	if d.plannedOnly(path) {
+		return nil, nil
+	}
+	return d.GitManager.ListBranches(path)
+}
+
+func (d *DryRun) CurrentBranch(path string) (string, error) {
+	if wt := d.plannedWorktree(absPath(path)); wt != nil {
+		return wt.branch, nil
+	}
+	return d.GitManager.CurrentBranch(path)
+}
+
+func (d *DryRun) RefExists(path, ref string) bool {
+	if d.plannedOnly(path) {
+		return false
+	}
+	return d.GitManager.RefExists(path, ref)
+}
+
+func (d *DryRun) SparsePatterns(path string) ([]string, error) {
+	if d.plannedOnly(path) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.sparse[plannedRepoRoot(path)], nil
+	}
+	return d.GitManager.SparsePatterns(path)
+}
+
+func (d *DryRun) RepoCheckoutExtras(path string) (CheckoutExtras, error) {
+	if d.plannedOnly(path) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.extras[plannedRepoRoot(path)], nil
+	}
+	return d.GitManager.RepoCheckoutExtras(path)
+}
+
+func (d *DryRun) IsWorktreeDirty(worktreePath string) (bool, error) {
+	if d.plannedWorktree(absPath(worktreePath)) != nil {
+		return false, nil
+	}
+	return d.GitManager.IsWorktreeDirty(worktreePath)
+}
+
+func (d *DryRun) plannedWorktree(path string) *plannedWorktree {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range d.worktrees {
+		if d.worktrees[i].path == path {
+			return &d.worktrees[i]
+		}
+	}
+	return nil
+}
+
+// plannedOnly reports whether path does not exist yet because an earlier
+// step only planned it, in which case reads answer from the plan.
+func (d *DryRun) plannedOnly(path string) bool {
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return false
+	}
+	path = absPath(path)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	planned := append([]string(nil), d.clones...)
+	for _, wt := range d.worktrees {
+		planned = append(planned, wt.path)
+	}
+	for _, p := range planned {
+		if p == path || isWithin(p, path) || isWithin(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// plannedRepoRoot returns the repo directory for a repo or bare metadata
+// path: worktrees of <root>/.git are listed under <root>.
+func plannedRepoRoot(path string) string {
+	path = absPath(path)
+	if filepath.Base(path) == ".git" {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+func isWithin(path, dir string) bool {
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func containsName(names []string, want string) bool {
+	for _, name := range names {
+		if name == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package git
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDryRunPlansCloneAndWorktreesWithoutTouchingDisk(t *testing.T) {
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoDir := filepath.Join(tmpDir, "acme", "widgets")
+	metadataPath := filepath.Join(repoDir, ".git")
+
+	var out bytes.Buffer
+	d := NewDryRun(New(), &out)
+	if err := d.Clone("git@github.com:acme/widgets.git", metadataPath, CloneOptions{Bare: true, Sparse: []string{"docs"}}); err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if err := d.ConfigureBareRemote(metadataPath, "main"); err != nil {
+		t.Fatalf("ConfigureBareRemote() error = %v", err)
+	}
+	if err := d.CreateWorktree(metadataPath, filepath.Join(repoDir, "main"), "main"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	if err := d.CreateFeatureWorktree(metadataPath, filepath.Join(repoDir, "feature-x"), "feature-x", "main"); err != nil {
+		t.Fatalf("CreateFeatureWorktree() error = %v", err)
+	}
+	// Adding a planned worktree again is a no-op, like for a registered one.
+	if err := d.CreateWorktree(metadataPath, filepath.Join(repoDir, "main"), "main"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	if _, err := os.Stat(repoDir); !os.IsNotExist(err) {
+		t.Fatalf("dry run created %s", repoDir)
+	}
+
+	mainPath := filepath.Join(repoDir, "main")
+	featurePath := filepath.Join(repoDir, "feature-x")
+	want := []string{
+		"git clone --bare git@github.com:acme/widgets.git " + metadataPath,
+		"git -C " + metadataPath + " config --add ezgit.sparse docs",
+		"git -C " + metadataPath + " config remote.origin.fetch '+refs/heads/*:refs/remotes/origin/*'",
+		"git -C " + metadataPath + " fetch origin",
+		"delete every local branch of " + metadataPath + " except main",
+		"git -C " + metadataPath + " worktree add --no-checkout " + mainPath + " main",
+		"git -C " + mainPath + " sparse-checkout set --cone -- docs",
+		"git -C " + mainPath + " checkout --quiet",
+		"git -C " + metadataPath + " worktree add --no-checkout -b feature-x " + featurePath + " main",
+		"git -C " + featurePath + " sparse-checkout set --cone -- docs",
+		"git -C " + featurePath + " checkout --quiet",
+	}
+	if got := d.Steps(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Steps() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.HasPrefix(out.String(), "[dry-run] git clone --bare ") {
+		t.Fatalf("output = %q, want [dry-run] prefixed steps", out.String())
+	}
+
+	worktrees, err := d.ListWorktrees(repoDir)
+	if err != nil {
+		t.Fatalf("ListWorktrees() error = %v", err)
+	}
+	if want := []string{"main", "feature-x"}; !reflect.DeepEqual(worktrees, want) {
+		t.Fatalf("ListWorktrees() = %v, want %v", worktrees, want)
+	}
+	if branch, err := d.CurrentBranch(featurePath); err != nil || branch != "feature-x" {
+		t.Fatalf("CurrentBranch() = %q, %v; want feature-x", branch, err)
+	}
+}
+
+func TestDryRunConvertToBareLeavesRepositoryUntouched(t *testing.T) {
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoDir := filepath.Join(tmpDir, "repo")
+	runGit(t, "", "init", "-b", "main", repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "test")
+	makeCommit(t, repoDir, "README.md", "hello\n", "initial commit")
+
+	d := NewDryRun(New(), &bytes.Buffer{})
+	if err := d.ConvertToBare(repoDir); err != nil {
+		t.Fatalf("ConvertToBare() error = %v", err)
+	}
+
+	gitDir := filepath.Join(repoDir, ".git")
+	mainPath := filepath.Join(repoDir, "main")
+	want := []string{
+		"git -C " + gitDir + " config core.bare true",
+		"git -C " + gitDir + " worktree add --no-checkout " + mainPath + " main",
+		"mv " + filepath.Join(repoDir, "README.md") + " " + filepath.Join(mainPath, "README.md"),
+	}
+	if got := d.Steps(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Steps() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "--is-bare-repository")); got != "false" {
+		t.Fatalf("is-bare-repository = %q, want false", got)
+	}
+	if worktrees, err := d.ListWorktrees(repoDir); err != nil || !reflect.DeepEqual(worktrees, []string{"main"}) {
+		t.Fatalf("ListWorktrees() = %v, %v; want [main]", worktrees, err)
+	}
+}
@@ -1,30 +1,76 @@
 package git
 
 import (
+	"errors"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/kirksw/ezgit/internal/journal"
 )
 
 type CloneOptions struct {
-	Bare       bool
-	Branch     string
-	Depth      int
-	Quiet      bool
+	Bare   bool
+	Branch string
+	Depth  int
+	Quiet  bool
+	// SSHKeyPath is validated and pinned in the clone's core.sshCommand.
+	// When empty, ssh picks the identity (agent, ~/.ssh/config, default key).
 	SSHKeyPath string
+	// Filter makes a partial clone, e.g. FilterBlobless.
+	Filter string
+	// Sparse lists sparse-checkout cone directories applied to the clone and
+	// to every worktree created in it later.
+	Sparse []string
+	// Config holds key=value settings stored in the new repo's config and
+	// used by the clone itself, e.g. an HTTPS credential helper.
+	Config []string
+	// CheckoutExtras are applied to the clone's checkout and recorded for
+	// every worktree added later.
+	CheckoutExtras
 }
 
 type GitManager interface {
 	Clone(url, path string, opts CloneOptions) error
 	ConfigureBareRemote(barePath, defaultBranch string) error
+	PlanConvertToBare(path string) (ConvertPlan, error)
 	ConvertToBare(path string) error
+	ConvertToRegular(path, worktreePath string) error
 	CreateWorktree(barePath, worktreePath, branch string) error
 	CreateDetachedWorktree(barePath, worktreePath, startPoint string) error
 	CreateFeatureWorktree(barePath, worktreePath, featureBranch, baseBranch string) error
 	ListBranches(path string) ([]string, error)
 	ValidateSSHKey(path string) error
+	ConfigureSSHKey(path, keyPath string) error
 	HasWorktrees(path string) (bool, error)
 	ListWorktrees(path string) ([]string, error)
+	ListWorktreeInfo(path string) ([]WorktreeInfo, error)
+	WorktreeStatus(path string) (WorktreeInfo, error)
+	StaleBranches(path string) ([]string, error)
+	CurrentBranch(path string) (string, error)
+	RemoveWorktree(barePath, worktreePath string, force bool) error
+	MoveWorktree(barePath, worktreePath, newPath string) error
+	PruneWorktrees(path string) error
+	DeleteBranch(path, branch string, force bool) error
+	RenameBranch(path, branch, newBranch string) error
+	IsWorktreeDirty(worktreePath string) (bool, error)
+	UnpushedCommits(worktreePath string) (int, error)
+	IsBranchMerged(path, branch, target string) (bool, error)
+	IsBranchUpstreamGone(path, branch string) (bool, error)
+	RefExists(path, ref string) bool
+	FetchPrune(path string) error
+	FastForward(worktreePath string) error
+	FetchPullRequest(path string, number int) (string, error)
+	CheckoutDetached(worktreePath, ref string) error
+	SparsePatterns(path string) ([]string, error)
+	RepoCheckoutExtras(path string) (CheckoutExtras, error)
+	SubmoduleStatus(worktreePath string) (SubmoduleInfo, error)
+	LFSStatus(worktreePath string) (LFSInfo, error)
+	ShallowInfo(path string) (ShallowInfo, error)
+	Unshallow(path string) error
+	Deepen(path string, depth int, since string) error
+	Grep(worktreePath string, opts GrepOptions, onMatch func(GrepMatch)) error
+	UndoAction(action journal.Action) error
 }
 
 type gitManager struct{}
@@ -49,6 +95,22 @@ func (g *gitManager) HasWorktrees(path string) (bool, error) {
 	return len(worktrees) > 0, nil
 }
 
+// CurrentBranch returns the branch checked out at path, or an empty string
+// when HEAD is detached.
+func (g *gitManager) CurrentBranch(path string) (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 func (g *gitManager) ListWorktrees(path string) ([]string, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
 	cmd.Dir = path
@@ -61,7 +123,6 @@ func (g *gitManager) ListWorktrees(path string) ([]string, error) {
 	if abs, err := filepath.Abs(repoPath); err == nil {
 		repoPath = abs
 	}
-	metadataPath := filepath.Join(repoPath, ".git")
 
 	lines := strings.Split(string(output), "\n")
 	var worktrees []string
@@ -73,24 +134,10 @@ func (g *gitManager) ListWorktrees(path string) ([]string, error) {
 			return
 		}
 
-		cleanPath := filepath.Clean(strings.TrimSpace(p))
-		if abs, err := filepath.Abs(cleanPath); err == nil {
-			cleanPath = abs
-		}
-
-		if cleanPath == repoPath || cleanPath == metadataPath {
+		name, ok := worktreeNameForPath(repoPath, p)
+		if !ok {
 			return
 		}
-
-		name := filepath.Base(cleanPath)
-		if strings.HasPrefix(cleanPath, repoPath+string(filepath.Separator)) {
-			name = strings.TrimPrefix(cleanPath, repoPath+string(filepath.Separator))
-		}
-		name = strings.TrimSpace(name)
-		if name == "" || name == ".git" {
-			return
-		}
-
 		if _, ok := seen[name]; ok {
 			return
 		}
@@ -117,3 +164,27 @@ func (g *gitManager) ListWorktrees(path string) ([]string, error) {
 
 	return worktrees, nil
 }
+
+// worktreeNameForPath names a worktree by its path relative to the repo root
+// (or its base name when it lives elsewhere). The repo root and its .git
+// metadata directory are not worktrees.
+func worktreeNameForPath(repoPath, worktreePath string) (string, bool) {
+	cleanPath := filepath.Clean(strings.TrimSpace(worktreePath))
+	if abs, err := filepath.Abs(cleanPath); err == nil {
+		cleanPath = abs
+	}
+
+	if cleanPath == repoPath || cleanPath == filepath.Join(repoPath, ".git") {
+		return "", false
+	}
+
+	name := filepath.Base(cleanPath)
+	if strings.HasPrefix(cleanPath, repoPath+string(filepath.Separator)) {
+		name = strings.TrimPrefix(cleanPath, repoPath+string(filepath.Separator))
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || name == ".git" {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type GrepOptions struct {
+	Pattern      string
+	IgnoreCase   bool
+	FixedStrings bool
+	// Pathspecs limits the search to matching tracked files (e.g. "*.go").
+	Pathspecs []string
+}
+
+type GrepMatch struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// Grep runs git grep over the tracked files of a worktree and calls onMatch
+// for each hit as it is read. Binary files are skipped. No matches is not an
+// error.
+func (g *gitManager) Grep(worktreePath string, opts GrepOptions, onMatch func(GrepMatch)) error {
+	args := []string{"grep", "--line-number", "--null", "-I", "--no-color"}
+	if opts.IgnoreCase {
+		args = append(args, "--ignore-case")
+	}
+	if opts.FixedStrings {
+		args = append(args, "--fixed-strings")
+	} else {
+		args = append(args, "--extended-regexp")
+	}
+	args = append(args, "-e", opts.Pattern)
+	if len(opts.Pathspecs) > 0 {
+		args = append(args, "--")
+		args = append(args, opts.Pathspecs...)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = worktreePath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run git grep: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run git grep: %w", err)
+	}
+
+	// A reader rather than a scanner: matched lines (minified files) can be
+	// any length, and stopping early would leave git blocked on the pipe.
+	reader := bufio.NewReader(stdout)
+	var readErr error
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if match, ok := parseGrepLine(strings.TrimSuffix(line, "\n")); ok {
+				onMatch(match)
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+				_, _ = io.Copy(io.Discard, stdout)
+			}
+			break
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		// git grep exits 1 when nothing matched.
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+			return readErr
+		}
+		return fmt.Errorf("git grep failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return readErr
+}
+
+// parseGrepLine parses one "path\x00line\x00text" record from
+// git grep --null --line-number.
+func parseGrepLine(line string) (GrepMatch, bool) {
+	path, rest, ok := strings.Cut(line, "\x00")
+	if !ok {
+		return GrepMatch{}, false
+	}
+	lineNumber, text, ok := strings.Cut(rest, "\x00")
+	if !ok {
+		return GrepMatch{}, false
+	}
+	n, err := strconv.Atoi(lineNumber)
+	if err != nil {
+		return GrepMatch{}, false
+	}
+	return GrepMatch{Path: path, Line: n, Text: text}, true
+}
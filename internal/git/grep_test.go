@@ -0,0 +1,74 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGrep(t *testing.T) {
+	repoDir := setupRemovalFixture(t)
+	makeCommit(t, repoDir, "main.go", "package main\n\n// TODO: wire up\nfunc main() {}\n", "add main")
+	makeCommit(t, repoDir, "notes.md", "todo: docs\n", "add notes")
+	if err := os.WriteFile(filepath.Join(repoDir, "untracked.go"), []byte("// TODO: ignored\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	grep := func(opts GrepOptions) []GrepMatch {
+		t.Helper()
+		var matches []GrepMatch
+		if err := New().Grep(repoDir, opts, func(m GrepMatch) { matches = append(matches, m) }); err != nil {
+			t.Fatalf("Grep(%+v) error = %v", opts, err)
+		}
+		return matches
+	}
+
+	want := []GrepMatch{{Path: "main.go", Line: 3, Text: "// TODO: wire up"}}
+	if got := grep(GrepOptions{Pattern: "TODO"}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Grep(TODO) = %+v, want %+v", got, want)
+	}
+	if got := grep(GrepOptions{Pattern: "todo", IgnoreCase: true}); len(got) != 2 {
+		t.Fatalf("Grep(todo, -i) = %+v, want 2 matches", got)
+	}
+	if got := grep(GrepOptions{Pattern: "todo", IgnoreCase: true, Pathspecs: []string{"*.md"}}); len(got) != 1 || got[0].Path != "notes.md" {
+		t.Fatalf("Grep(todo, *.md) = %+v", got)
+	}
+	if got := grep(GrepOptions{Pattern: "func main()", FixedStrings: true}); len(got) != 1 || got[0].Line != 4 {
+		t.Fatalf("Grep(fixed) = %+v", got)
+	}
+	if got := grep(GrepOptions{Pattern: "no such text"}); len(got) != 0 {
+		t.Fatalf("Grep(no match) = %+v", got)
+	}
+
+	if err := New().Grep(repoDir, GrepOptions{Pattern: "("}, func(GrepMatch) {}); err == nil {
+		t.Fatal("Grep() expected error for invalid regexp")
+	}
+}
+
+func TestGrepLongLine(t *testing.T) {
+	repoDir := setupRemovalFixture(t)
+	long := "TODO " + strings.Repeat("x", 2*1024*1024)
+	makeCommit(t, repoDir, "bundle.min.js", long+"\n", "add bundle")
+	makeCommit(t, repoDir, "main.go", "// TODO: after\n", "add main")
+
+	done := make(chan error, 1)
+	var matches []GrepMatch
+	go func() {
+		done <- New().Grep(repoDir, GrepOptions{Pattern: "TODO"}, func(m GrepMatch) { matches = append(matches, m) })
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Grep() error = %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("Grep() hung on a line over 1MB")
+	}
+
+	if len(matches) != 2 || matches[0].Text != long || matches[1].Path != "main.go" {
+		t.Fatalf("Grep() returned %d matches, want the long line and main.go", len(matches))
+	}
+}
@@ -0,0 +1,332 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kirksw/ezgit/internal/journal"
+)
+
+// Recorder receives a journal action for each mutating GitManager call.
+type Recorder interface {
+	Record(action journal.Action)
+}
+
+// journaledGitManager records the before-state of every mutating call so
+// `ezgit undo` can revert it. Reads pass straight through.
+type journaledGitManager struct {
+	GitManager
+	rec Recorder
+}
+
+// WithJournal wraps gitMgr so its mutating calls are recorded in rec.
+func WithJournal(gitMgr GitManager, rec Recorder) GitManager {
+	return &journaledGitManager{GitManager: gitMgr, rec: rec}
+}
+
+func (j *journaledGitManager) ConfigureBareRemote(barePath, defaultBranch string) error {
+	gitDir := absPath(barePath)
+	before, _ := branchHeads(barePath)
+	fetchRefspec, fetchErr := gitOutput(barePath, "config", "--get", "remote.origin.fetch")
+
+	err := j.GitManager.ConfigureBareRemote(barePath, defaultBranch)
+
+	j.rec.Record(journal.Action{
+		Kind:    journal.ConfigChanged,
+		GitDir:  gitDir,
+		Key:     "remote.origin.fetch",
+		Value:   fetchRefspec,
+		Existed: fetchErr == nil,
+	})
+	after, _ := branchHeads(barePath)
+	for _, branch := range sortedKeys(before) {
+		if _, ok := after[branch]; !ok {
+			j.rec.Record(journal.Action{Kind: journal.BranchDeleted, GitDir: gitDir, Branch: branch, SHA: before[branch]})
+		}
+	}
+	return err
+}
+
+func (j *journaledGitManager) ConfigureSSHKey(path, keyPath string) error {
+	previous, getErr := gitOutput(path, "config", "--get", "core.sshCommand")
+	if err := j.GitManager.ConfigureSSHKey(path, keyPath); err != nil {
+		return err
+	}
+	j.rec.Record(journal.Action{
+		Kind:    journal.ConfigChanged,
+		GitDir:  absPath(path),
+		Key:     "core.sshCommand",
+		Value:   previous,
+		Existed: getErr == nil,
+	})
+	return nil
+}
+
+func (j *journaledGitManager) ConvertToBare(path string) error {
+	plan, err := j.GitManager.PlanConvertToBare(path)
+	if err != nil {
+		return err
+	}
+	if err := j.GitManager.ConvertToBare(path); err != nil {
+		return err
+	}
+	j.rec.Record(journal.Action{
+		Kind:     journal.ConvertedToBare,
+		GitDir:   filepath.Join(absPath(path), ".git"),
+		Path:     absPath(path),
+		Worktree: absPath(plan.WorktreePath),
+		Branch:   plan.Branch,
+	})
+	return nil
+}
+
+func (j *journaledGitManager) ConvertToRegular(path, worktreePath string) error {
+	root := absPath(path)
+	kept := absPath(worktreePath)
+	gitDir := filepath.Join(root, ".git")
+	heads, _ := worktreeHeads(gitDir)
+
+	if err := j.GitManager.ConvertToRegular(path, worktreePath); err != nil {
+		return err
+	}
+
+	// Removed worktrees are recorded first so undo re-creates them after
+	// converting back to bare.
+	for _, head := range heads {
+		if head.Path == kept || !strings.HasPrefix(head.Path, root+string(filepath.Separator)) {
+			continue
+		}
+		j.rec.Record(journal.Action{Kind: journal.WorktreeRemoved, GitDir: gitDir, Path: head.Path, Branch: head.Branch, SHA: head.SHA})
+	}
+	j.rec.Record(journal.Action{Kind: journal.ConvertedToRegular, GitDir: gitDir, Path: root, Worktree: kept})
+	return nil
+}
+
+func (j *journaledGitManager) CreateWorktree(barePath, worktreePath, branch string) error {
+	return j.recordWorktreeAdd(barePath, worktreePath, "", func() error {
+		return j.GitManager.CreateWorktree(barePath, worktreePath, branch)
+	})
+}
+
+func (j *journaledGitManager) CreateDetachedWorktree(barePath, worktreePath, startPoint string) error {
+	return j.recordWorktreeAdd(barePath, worktreePath, "", func() error {
+		return j.GitManager.CreateDetachedWorktree(barePath, worktreePath, startPoint)
+	})
+}
+
+func (j *journaledGitManager) CreateFeatureWorktree(barePath, worktreePath, featureBranch, baseBranch string) error {
+	newBranch := ""
+	if !j.GitManager.RefExists(barePath, "refs/heads/"+featureBranch) {
+		newBranch = featureBranch
+	}
+	return j.recordWorktreeAdd(barePath, worktreePath, newBranch, func() error {
+		return j.GitManager.CreateFeatureWorktree(barePath, worktreePath, featureBranch, baseBranch)
+	})
+}
+
+// recordWorktreeAdd runs add and records the worktree unless it was already
+// registered, in which case add is a no-op.
+func (j *journaledGitManager) recordWorktreeAdd(barePath, worktreePath, newBranch string, add func() error) error {
+	registered, _ := (&gitManager{}).isWorktreeRegistered(barePath, worktreePath)
+	if err := add(); err != nil || registered {
+		return err
+	}
+
+	action := journal.Action{Kind: journal.WorktreeAdded, GitDir: absPath(barePath), Path: absPath(worktreePath)}
+	if newBranch != "" {
+		action.Branch = newBranch
+		action.CreatedBranch = true
+		action.SHA, _ = gitOutput(barePath, "rev-parse", "refs/heads/"+newBranch)
+	}
+	j.rec.Record(action)
+	return nil
+}
+
+func (j *journaledGitManager) RemoveWorktree(barePath, worktreePath string, force bool) error {
+	sha, _ := gitOutput(worktreePath, "rev-parse", "HEAD")
+	branch, _ := j.GitManager.CurrentBranch(worktreePath)
+
+	if err := j.GitManager.RemoveWorktree(barePath, worktreePath, force); err != nil {
+		return err
+	}
+	j.rec.Record(journal.Action{Kind: journal.WorktreeRemoved, GitDir: absPath(barePath), Path: absPath(worktreePath), Branch: branch, SHA: sha})
+	return nil
+}
+
+func (j *journaledGitManager) MoveWorktree(barePath, worktreePath, newPath string) error {
+	if err := j.GitManager.MoveWorktree(barePath, worktreePath, newPath); err != nil {
+		return err
+	}
+	j.rec.Record(journal.Action{Kind: journal.WorktreeMoved, GitDir: absPath(barePath), Path: absPath(worktreePath), To: absPath(newPath)})
+	return nil
+}
+
+func (j *journaledGitManager) RenameBranch(path, branch, newBranch string) error {
+	if err := j.GitManager.RenameBranch(path, branch, newBranch); err != nil {
+		return err
+	}
+	j.rec.Record(journal.Action{Kind: journal.BranchRenamed, GitDir: absPath(path), Branch: branch, To: newBranch})
+	return nil
+}
+
+func (j *journaledGitManager) DeleteBranch(path, branch string, force bool) error {
+	sha, _ := gitOutput(path, "rev-parse", "refs/heads/"+branch)
+
+	if err := j.GitManager.DeleteBranch(path, branch, force); err != nil {
+		return err
+	}
+	j.rec.Record(journal.Action{Kind: journal.BranchDeleted, GitDir: absPath(path), Branch: branch, SHA: sha})
+	return nil
+}
+
+// UndoAction reverts one journal action using the recorded before-state.
+func (g *gitManager) UndoAction(action journal.Action) error {
+	switch action.Kind {
+	case journal.BranchDeleted:
+		if existing, err := gitOutput(action.GitDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+action.Branch); err == nil {
+			if existing == action.SHA {
+				return nil
+			}
+			return fmt.Errorf("branch %s already exists at a different commit", action.Branch)
+		}
+		if err := runGitCommand(action.GitDir, "branch", action.Branch, action.SHA); err != nil {
+			return fmt.Errorf("failed to restore branch %s at %s (the commit may have been garbage collected): %w", action.Branch, action.SHA, err)
+		}
+		return nil
+
+	case journal.WorktreeAdded:
+		if _, err := os.Stat(action.Path); err == nil {
+			if err := g.RemoveWorktree(action.GitDir, action.Path, false); err != nil {
+				return err
+			}
+		}
+		// Keep a created branch that gained commits since; deleting it
+		// would lose work.
+		if action.CreatedBranch {
+			if tip, err := gitOutput(action.GitDir, "rev-parse", "refs/heads/"+action.Branch); err == nil && tip == action.SHA {
+				return g.DeleteBranch(action.GitDir, action.Branch, true)
+			}
+		}
+		return nil
+
+	case journal.WorktreeRemoved:
+		if _, err := os.Stat(action.Path); err == nil {
+			if registered, _ := g.isWorktreeRegistered(action.GitDir, action.Path); registered {
+				return nil
+			}
+			return fmt.Errorf("cannot restore worktree: %s already exists", action.Path)
+		}
+		if action.Branch != "" && g.RefExists(action.GitDir, "refs/heads/"+action.Branch) {
+			return g.CreateWorktree(action.GitDir, action.Path, action.Branch)
+		}
+		return g.CreateDetachedWorktree(action.GitDir, action.Path, action.SHA)
+
+	case journal.WorktreeMoved:
+		if _, err := os.Stat(action.Path); err == nil {
+			return fmt.Errorf("cannot move worktree back: %s already exists", action.Path)
+		}
+		return g.MoveWorktree(action.GitDir, action.To, action.Path)
+
+	case journal.BranchRenamed:
+		if g.RefExists(action.GitDir, "refs/heads/"+action.Branch) {
+			return fmt.Errorf("cannot rename branch %s back: %s already exists", action.To, action.Branch)
+		}
+		return g.RenameBranch(action.GitDir, action.To, action.Branch)
+
+	case journal.ConvertedToBare:
+		heads, err := worktreeHeads(action.GitDir)
+		if err != nil {
+			return err
+		}
+		for _, head := range heads {
+			if head.Path == action.Worktree || !strings.HasPrefix(head.Path, action.Path+string(filepath.Separator)) {
+				continue
+			}
+			if dirty, err := g.IsWorktreeDirty(head.Path); err != nil || dirty {
+				return fmt.Errorf("worktree %s has uncommitted changes; commit or remove it first", head.Path)
+			}
+		}
+		return g.ConvertToRegular(action.Path, action.Worktree)
+
+	case journal.ConvertedToRegular:
+		return g.ConvertToBare(action.Path)
+
+	case journal.ConfigChanged:
+		if action.Existed {
+			return runGitCommand(action.GitDir, "config", action.Key, action.Value)
+		}
+		// Exit status 5 means the key was not set, which is the goal.
+		_ = runGitCommand(action.GitDir, "config", "--unset", action.Key)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown journal action %q", action.Kind)
+	}
+}
+
+type worktreeHead struct {
+	Path   string
+	Branch string
+	SHA    string
+}
+
+// worktreeHeads lists the linked worktrees of gitDir with their HEADs.
+func worktreeHeads(gitDir string) ([]worktreeHead, error) {
+	output, err := gitOutput(gitDir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var heads []worktreeHead
+	for _, block := range strings.Split(output, "\n\n") {
+		var head worktreeHead
+		bare := false
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "worktree "):
+				head.Path = normalizePathForCompare(strings.TrimPrefix(line, "worktree "))
+			case strings.HasPrefix(line, "HEAD "):
+				head.SHA = strings.TrimPrefix(line, "HEAD ")
+			case strings.HasPrefix(line, "branch "):
+				head.Branch = strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+			case line == "bare":
+				bare = true
+			}
+		}
+		if head.Path != "" && !bare {
+			heads = append(heads, head)
+		}
+	}
+	return heads, nil
+}
+
+// branchHeads maps each local branch of path to its commit.
+func branchHeads(path string) (map[string]string, error) {
+	output, err := gitOutput(path, "for-each-ref", "--format=%(refname:short) %(objectname)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	heads := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		if name, sha, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			heads[name] = sha
+		}
+	}
+	return heads, nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func absPath(path string) string {
+	return normalizePathForCompare(path)
+}
@@ -0,0 +1,136 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirksw/ezgit/internal/journal"
+)
+
+type memoryRecorder struct {
+	actions []journal.Action
+}
+
+func (r *memoryRecorder) Record(action journal.Action) {
+	r.actions = append(r.actions, action)
+}
+
+func undoAll(t *testing.T, gitMgr GitManager, actions []journal.Action) {
+	t.Helper()
+	for i := len(actions) - 1; i >= 0; i-- {
+		if err := gitMgr.UndoAction(actions[i]); err != nil {
+			t.Fatalf("UndoAction(%s) error = %v", actions[i].Describe(), err)
+		}
+	}
+}
+
+func TestJournalUndoRestoresRemovedWorktreeAndBranch(t *testing.T) {
+	repoDir := setupUnconvertFixture(t)
+	metadataPath := filepath.Join(repoDir, ".git")
+	featureDir := filepath.Join(repoDir, "feature-a")
+	featureSHA := strings.TrimSpace(runGit(t, metadataPath, "rev-parse", "refs/heads/feature-a"))
+
+	rec := &memoryRecorder{}
+	gitMgr := WithJournal(New(), rec)
+	if err := gitMgr.RemoveWorktree(metadataPath, featureDir, false); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if err := gitMgr.DeleteBranch(metadataPath, "feature-a", true); err != nil {
+		t.Fatalf("DeleteBranch() error = %v", err)
+	}
+	if len(rec.actions) != 2 {
+		t.Fatalf("recorded %d actions, want 2: %+v", len(rec.actions), rec.actions)
+	}
+
+	undoAll(t, New(), rec.actions)
+
+	if got := strings.TrimSpace(runGit(t, featureDir, "rev-parse", "--abbrev-ref", "HEAD")); got != "feature-a" {
+		t.Fatalf("restored worktree HEAD = %q, want feature-a", got)
+	}
+	if got := strings.TrimSpace(runGit(t, metadataPath, "rev-parse", "refs/heads/feature-a")); got != featureSHA {
+		t.Fatalf("restored branch at %s, want %s", got, featureSHA)
+	}
+}
+
+func TestJournalUndoReversesWorktreeMoveAndBranchRename(t *testing.T) {
+	repoDir := setupUnconvertFixture(t)
+	metadataPath := filepath.Join(repoDir, ".git")
+	featureDir := filepath.Join(repoDir, "feature-a")
+	movedDir := filepath.Join(repoDir, "feature", "b")
+
+	rec := &memoryRecorder{}
+	gitMgr := WithJournal(New(), rec)
+	if err := gitMgr.MoveWorktree(metadataPath, featureDir, movedDir); err != nil {
+		t.Fatalf("MoveWorktree() error = %v", err)
+	}
+	if err := gitMgr.RenameBranch(metadataPath, "feature-a", "feature/b"); err != nil {
+		t.Fatalf("RenameBranch() error = %v", err)
+	}
+	if got := strings.TrimSpace(runGit(t, movedDir, "rev-parse", "--abbrev-ref", "HEAD")); got != "feature/b" {
+		t.Fatalf("moved worktree HEAD = %q, want feature/b", got)
+	}
+	if _, err := os.Stat(featureDir); !os.IsNotExist(err) {
+		t.Fatalf("old worktree path still exists (err=%v)", err)
+	}
+
+	undoAll(t, New(), rec.actions)
+
+	if got := strings.TrimSpace(runGit(t, featureDir, "rev-parse", "--abbrev-ref", "HEAD")); got != "feature-a" {
+		t.Fatalf("restored worktree HEAD = %q, want feature-a", got)
+	}
+	// The emptied feature/ directory is cleaned up on the way back.
+	if _, err := os.Stat(filepath.Join(repoDir, "feature")); !os.IsNotExist(err) {
+		t.Fatalf("empty parent directory left behind (err=%v)", err)
+	}
+}
+
+func TestJournalUndoRemovesCreatedFeatureWorktree(t *testing.T) {
+	repoDir := setupUnconvertFixture(t)
+	metadataPath := filepath.Join(repoDir, ".git")
+	scratchDir := filepath.Join(repoDir, "scratch")
+
+	rec := &memoryRecorder{}
+	gitMgr := WithJournal(New(), rec)
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, scratchDir, "scratch", "main"); err != nil {
+		t.Fatalf("CreateFeatureWorktree() error = %v", err)
+	}
+	// Re-creating a registered worktree is a no-op and is not journaled.
+	if err := gitMgr.CreateFeatureWorktree(metadataPath, scratchDir, "scratch", "main"); err != nil {
+		t.Fatalf("CreateFeatureWorktree(again) error = %v", err)
+	}
+	if len(rec.actions) != 1 || !rec.actions[0].CreatedBranch {
+		t.Fatalf("unexpected actions: %+v", rec.actions)
+	}
+
+	undoAll(t, New(), rec.actions)
+
+	if _, err := os.Stat(scratchDir); !os.IsNotExist(err) {
+		t.Fatalf("worktree still exists (err=%v)", err)
+	}
+	if New().RefExists(metadataPath, "refs/heads/scratch") {
+		t.Fatal("created branch was not deleted")
+	}
+}
+
+func TestJournalUndoReversesUnconvert(t *testing.T) {
+	repoDir := setupUnconvertFixture(t)
+
+	rec := &memoryRecorder{}
+	gitMgr := WithJournal(New(), rec)
+	if err := gitMgr.ConvertToRegular(repoDir, filepath.Join(repoDir, "main")); err != nil {
+		t.Fatalf("ConvertToRegular() error = %v", err)
+	}
+
+	undoAll(t, New(), rec.actions)
+
+	if got := strings.TrimSpace(runGit(t, filepath.Join(repoDir, ".git"), "rev-parse", "--is-bare-repository")); got != "true" {
+		t.Fatalf("is-bare-repository = %q, want true", got)
+	}
+	for _, name := range []string{"main", "feature-a"} {
+		if got := strings.TrimSpace(runGit(t, filepath.Join(repoDir, name), "rev-parse", "--abbrev-ref", "HEAD")); got != name {
+			t.Fatalf("worktree %s HEAD = %q", name, got)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// MoveWorktree moves a linked worktree to newPath with `git worktree move`,
+// creating newPath's parent directories and removing the empty ones the old
+// path leaves behind inside the repo (e.g. feature/ after moving feature/x).
+func (g *gitManager) MoveWorktree(barePath, worktreePath, newPath string) error {
+	oldPath := absPath(worktreePath)
+	newPath = absPath(newPath)
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	cmd := exec.Command("git", "worktree", "move", oldPath, newPath)
+	cmd.Dir = barePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to move worktree: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+
+	root := plannedRepoRoot(barePath)
+	for dir := filepath.Dir(oldPath); isWithin(dir, root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// RenameBranch renames a local branch with `git branch -m`. Worktrees that
+// have it checked out follow the new name, and its upstream settings move
+// with it.
+func (g *gitManager) RenameBranch(path, branch, newBranch string) error {
+	cmd := exec.Command("git", "branch", "-m", branch, newBranch)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to rename branch %s to %s: %w\n%s", branch, newBranch, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
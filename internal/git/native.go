@@ -0,0 +1,321 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	BackendExec   = "exec"
+	BackendNative = "native"
+)
+
+// nativeGitManager answers read-only queries (worktrees, branches, HEAD) by
+// reading .git metadata directly instead of forking git. Writes and anything
+// it cannot read natively (e.g. reftable repositories) fall back to the
+// embedded exec backend.
+type nativeGitManager struct {
+	*gitManager
+}
+
+func NewNative() GitManager {
+	return &nativeGitManager{gitManager: &gitManager{}}
+}
+
+// NewWithBackend returns the GitManager for a config backend name. Unknown or
+// empty names use the exec backend.
+func NewWithBackend(backend string) GitManager {
+	switch strings.TrimSpace(strings.ToLower(backend)) {
+	case BackendNative:
+		return NewNative()
+	default:
+		return New()
+	}
+}
+
+func (g *nativeGitManager) HasWorktrees(path string) (bool, error) {
+	worktrees, err := g.ListWorktrees(path)
+	if err != nil {
+		return false, err
+	}
+	return len(worktrees) > 0, nil
+}
+
+func (g *nativeGitManager) ListWorktrees(path string) ([]string, error) {
+	dirs, err := discoverGitDirs(path)
+	if err != nil {
+		return nil, err
+	}
+	if dirs.reftable {
+		return g.gitManager.ListWorktrees(path)
+	}
+
+	repoPath := filepath.Clean(path)
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		repoPath = abs
+	}
+	worktreePaths, err := linkedWorktreePaths(dirs.commonDir)
+	if err != nil {
+		return nil, err
+	}
+	// Like git, report the main worktree as the common dir minus any
+	// trailing /.git.
+	mainPath := strings.TrimSuffix(dirs.commonDir, string(filepath.Separator)+".git")
+	worktreePaths = append([]string{mainPath}, worktreePaths...)
+
+	var worktrees []string
+	seen := make(map[string]struct{})
+	for _, wtPath := range worktreePaths {
+		name, ok := worktreeNameForPath(repoPath, wtPath)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		worktrees = append(worktrees, name)
+	}
+
+	return worktrees, nil
+}
+
+func (g *nativeGitManager) ListBranches(path string) ([]string, error) {
+	dirs, err := discoverGitDirs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	if dirs.reftable {
+		return g.gitManager.ListBranches(path)
+	}
+
+	refs, err := readRefNames(dirs.commonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	for _, ref := range refs {
+		var branch string
+		switch {
+		case strings.HasPrefix(ref, "refs/heads/"):
+			branch = strings.TrimPrefix(ref, "refs/heads/")
+		case strings.HasPrefix(ref, "refs/remotes/"):
+			branch = strings.TrimPrefix(ref, "refs/remotes/")
+			if strings.HasSuffix(branch, "/HEAD") {
+				continue
+			}
+			branch = strings.TrimPrefix(branch, "origin/")
+		default:
+			continue
+		}
+		if branch == "" || branch == "HEAD" {
+			continue
+		}
+		seen[branch] = struct{}{}
+	}
+
+	branches := make([]string, 0, len(seen))
+	for branch := range seen {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+
+	return branches, nil
+}
+
+func (g *nativeGitManager) CurrentBranch(path string) (string, error) {
+	dirs, err := discoverGitDirs(path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dirs.gitDir, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref: "); ok {
+		return strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/"), nil
+	}
+	return "", nil
+}
+
+type gitDirs struct {
+	// gitDir holds the per-worktree state (HEAD, index).
+	gitDir string
+	// commonDir holds the shared state (refs, packed-refs, worktrees/).
+	commonDir string
+	reftable  bool
+}
+
+// discoverGitDirs finds the git directory for path the same way git does:
+// walking up from path until it finds a .git directory, a .git file pointing
+// at a linked worktree, or a bare repository.
+func discoverGitDirs(path string) (gitDirs, error) {
+	start, err := filepath.Abs(path)
+	if err != nil {
+		return gitDirs{}, err
+	}
+
+	for dir := start; ; dir = filepath.Dir(dir) {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			if info.IsDir() {
+				return resolveGitDirs(dotGit)
+			}
+			gitDir, err := readGitDirFile(dotGit)
+			if err != nil {
+				return gitDirs{}, err
+			}
+			return resolveGitDirs(gitDir)
+		}
+
+		if isBareRepoDir(dir) {
+			return resolveGitDirs(dir)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return gitDirs{}, fmt.Errorf("not a git repository: %s", path)
+		}
+	}
+}
+
+func resolveGitDirs(gitDir string) (gitDirs, error) {
+	dirs := gitDirs{gitDir: gitDir, commonDir: gitDir}
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		dirs.commonDir = filepath.Clean(commonDir)
+	}
+	if info, err := os.Stat(filepath.Join(dirs.commonDir, "reftable")); err == nil && info.IsDir() {
+		dirs.reftable = true
+	}
+	return dirs, nil
+}
+
+func readGitDirFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	value, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("invalid gitdir file: %s", path)
+	}
+	gitDir := strings.TrimSpace(value)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return filepath.Clean(gitDir), nil
+}
+
+func isBareRepoDir(dir string) bool {
+	for _, name := range []string{"HEAD", "config", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// linkedWorktreePaths returns the checkout path of every linked worktree
+// registered under commonDir/worktrees, sorted by path.
+func linkedWorktreePaths(commonDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(commonDir, "worktrees"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		gitdirFile := filepath.Join(commonDir, "worktrees", entry.Name(), "gitdir")
+		data, err := os.ReadFile(gitdirFile)
+		if err != nil {
+			continue
+		}
+		dotGit := strings.TrimSpace(string(data))
+		if dotGit == "" {
+			continue
+		}
+		if !filepath.IsAbs(dotGit) {
+			dotGit = filepath.Join(filepath.Dir(gitdirFile), dotGit)
+		}
+		paths = append(paths, filepath.Dir(filepath.Clean(dotGit)))
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// readRefNames lists ref names from loose refs and packed-refs.
+func readRefNames(commonDir string) ([]string, error) {
+	seen := make(map[string]struct{})
+
+	for _, root := range []string{"refs/heads", "refs/remotes"} {
+		base := filepath.Join(commonDir, filepath.FromSlash(root))
+		err := filepath.WalkDir(base, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(commonDir, p)
+			if err != nil {
+				return err
+			}
+			seen[filepath.ToSlash(rel)] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	packed, err := os.Open(filepath.Join(commonDir, "packed-refs"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		defer packed.Close()
+		scanner := bufio.NewScanner(packed)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" || line[0] == '#' || line[0] == '^' {
+				continue
+			}
+			_, ref, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			seen[strings.TrimSpace(ref)] = struct{}{}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	refs := make([]string, 0, len(seen))
+	for ref := range seen {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
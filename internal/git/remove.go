@@ -0,0 +1,129 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// RemoveWorktree unregisters and deletes a linked worktree, then prunes any
+// stale worktree metadata left behind.
+func (g *gitManager) RemoveWorktree(barePath, worktreePath string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force", "--force")
+	}
+	args = append(args, worktreePath)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = barePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove worktree: %w\n%s", err, string(output))
+	}
+
+	return g.PruneWorktrees(barePath)
+}
+
+func (g *gitManager) PruneWorktrees(path string) error {
+	if err := runGitCommand(path, "worktree", "prune"); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	return nil
+}
+
+func (g *gitManager) DeleteBranch(path, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+
+	cmd := exec.Command("git", "branch", flag, branch)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w\n%s", branch, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// IsWorktreeDirty reports whether the worktree has staged, unstaged or
+// untracked changes.
+func (g *gitManager) IsWorktreeDirty(worktreePath string) (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read worktree status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// UnpushedCommits counts commits on HEAD that are not reachable from any
+// remote-tracking branch.
+func (g *gitManager) UnpushedCommits(worktreePath string) (int, error) {
+	cmd := exec.Command("git", "rev-list", "--count", "HEAD", "--not", "--remotes")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unpushed commits: %w", err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse unpushed commit count: %w", err)
+	}
+	return count, nil
+}
+
+// IsBranchMerged reports whether branch has commits of its own and all of
+// them are reachable from target. Its own commits are those after the point
+// it was created at, the oldest entry of its reflog: a branch just cut from
+// an older commit of target is an ancestor of target too, but has nothing
+// merged. Without a reflog (branches made outside ezgit in a bare repo) the
+// start point is unknown and the branch does not count as merged.
+func (g *gitManager) IsBranchMerged(path, branch, target string) (bool, error) {
+	ref := "refs/heads/" + branch
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ref, target)
+	cmd.Dir = path
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check whether %s is merged into %s: %w", branch, target, err)
+	}
+
+	tip, err := gitOutput(path, "rev-parse", ref)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", branch, err)
+	}
+	// Reflogs list the newest entry first, so the last one is where the
+	// branch was created.
+	reflog, err := gitOutput(path, "reflog", "show", "--format=%H", ref, "--")
+	if err != nil || reflog == "" {
+		return false, nil
+	}
+	entries := strings.Split(reflog, "\n")
+	base, err := gitOutput(path, "merge-base", tip, entries[len(entries)-1])
+	if err != nil {
+		return false, nil
+	}
+	return base != tip, nil
+}
+
+// IsBranchUpstreamGone reports whether branch tracks a remote branch that no
+// longer exists (typically deleted after its pull request was merged).
+func (g *gitManager) IsBranchUpstreamGone(path, branch string) (bool, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(upstream:track)", "refs/heads/"+branch)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read upstream of %s: %w", branch, err)
+	}
+	return strings.TrimSpace(string(output)) == "[gone]", nil
+}
+
+// RefExists reports whether ref resolves to a commit.
+func (g *gitManager) RefExists(path, ref string) bool {
+	return runGitCommand(path, "rev-parse", "--verify", "--quiet", ref+"^{commit}") == nil
+}
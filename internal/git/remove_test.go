@@ -0,0 +1,169 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupRemovalFixture clones an origin with a main branch and returns the
+// clone path.
+func setupRemovalFixture(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+	repoDir := filepath.Join(tmpDir, "repo")
+
+	runGit(t, "", "init", "--bare", originDir)
+	runGit(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGit(t, "", "clone", originDir, seedDir)
+	runGit(t, seedDir, "config", "user.email", "test@example.com")
+	runGit(t, seedDir, "config", "user.name", "test")
+	makeCommit(t, seedDir, "README.md", "hello\n", "initial commit")
+	runGit(t, seedDir, "push", "origin", "HEAD:main")
+
+	runGit(t, "", "clone", originDir, repoDir)
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "test")
+	return repoDir
+}
+
+func TestWorktreeRemovalChecks(t *testing.T) {
+	repoDir := setupRemovalFixture(t)
+	gitMgr := New()
+
+	mergedPath := filepath.Join(filepath.Dir(repoDir), "merged")
+	runGit(t, repoDir, "worktree", "add", "-b", "merged", mergedPath, "main")
+	makeCommit(t, mergedPath, "merged.txt", "merged\n", "merged commit")
+	runGit(t, mergedPath, "push", "origin", "HEAD:main")
+	runGit(t, repoDir, "fetch", "origin")
+
+	// A branch just created from the target has nothing of its own merged.
+	freshPath := filepath.Join(filepath.Dir(repoDir), "fresh")
+	runGit(t, repoDir, "worktree", "add", "-b", "fresh", freshPath, "origin/main")
+
+	workPath := filepath.Join(filepath.Dir(repoDir), "work")
+	runGit(t, repoDir, "worktree", "add", "-b", "work", workPath, "main")
+	makeCommit(t, workPath, "work.txt", "work\n", "work commit")
+
+	// Cut from the stale local main: behind origin/main, yet nothing merged.
+	stalePath := filepath.Join(filepath.Dir(repoDir), "stale")
+	runGit(t, repoDir, "worktree", "add", "-b", "stale", stalePath, "main")
+
+	if merged, err := gitMgr.IsBranchMerged(repoDir, "merged", "origin/main"); err != nil || !merged {
+		t.Fatalf("IsBranchMerged(merged) = %v, %v; want true", merged, err)
+	}
+	if merged, err := gitMgr.IsBranchMerged(repoDir, "work", "origin/main"); err != nil || merged {
+		t.Fatalf("IsBranchMerged(work) = %v, %v; want false", merged, err)
+	}
+	if merged, err := gitMgr.IsBranchMerged(repoDir, "fresh", "origin/main"); err != nil || merged {
+		t.Fatalf("IsBranchMerged(fresh) = %v, %v; want false", merged, err)
+	}
+	if merged, err := gitMgr.IsBranchMerged(repoDir, "stale", "origin/main"); err != nil || merged {
+		t.Fatalf("IsBranchMerged(stale) = %v, %v; want false", merged, err)
+	}
+
+	if count, err := gitMgr.UnpushedCommits(workPath); err != nil || count != 1 {
+		t.Fatalf("UnpushedCommits(work) = %d, %v; want 1", count, err)
+	}
+	if count, err := gitMgr.UnpushedCommits(mergedPath); err != nil || count != 0 {
+		t.Fatalf("UnpushedCommits(merged) = %d, %v; want 0", count, err)
+	}
+
+	if dirty, err := gitMgr.IsWorktreeDirty(mergedPath); err != nil || dirty {
+		t.Fatalf("IsWorktreeDirty(merged) = %v, %v; want false", dirty, err)
+	}
+	if err := os.WriteFile(filepath.Join(mergedPath, "scratch.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if dirty, err := gitMgr.IsWorktreeDirty(mergedPath); err != nil || !dirty {
+		t.Fatalf("IsWorktreeDirty(merged) = %v, %v; want true", dirty, err)
+	}
+
+	if !gitMgr.RefExists(repoDir, "origin/main") {
+		t.Fatal("RefExists(origin/main) = false")
+	}
+	if gitMgr.RefExists(repoDir, "origin/missing") {
+		t.Fatal("RefExists(origin/missing) = true")
+	}
+}
+
+func TestIsBranchMergedInBareRepo(t *testing.T) {
+	repoDir := setupRemovalFixture(t)
+	originDir := filepath.Join(filepath.Dir(repoDir), "origin.git")
+	bareDir := filepath.Join(t.TempDir(), ".git")
+	gitMgr := New()
+	if err := gitMgr.Clone(originDir, bareDir, CloneOptions{Bare: true, Quiet: true}); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, bareDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	runGit(t, bareDir, "fetch", "--quiet", "origin")
+
+	featurePath := filepath.Join(filepath.Dir(bareDir), "feature")
+	if err := gitMgr.CreateFeatureWorktree(bareDir, featurePath, "feature", "origin/main"); err != nil {
+		t.Fatal(err)
+	}
+	if merged, err := gitMgr.IsBranchMerged(bareDir, "feature", "origin/main"); err != nil || merged {
+		t.Fatalf("IsBranchMerged(new feature) = %v, %v; want false", merged, err)
+	}
+
+	runGit(t, featurePath, "config", "user.email", "test@example.com")
+	runGit(t, featurePath, "config", "user.name", "test")
+	makeCommit(t, featurePath, "feature.txt", "feature\n", "feature commit")
+	runGit(t, featurePath, "push", "--quiet", "origin", "HEAD:main")
+	runGit(t, bareDir, "fetch", "--quiet", "origin")
+	if merged, err := gitMgr.IsBranchMerged(bareDir, "feature", "origin/main"); err != nil || !merged {
+		t.Fatalf("IsBranchMerged(pushed feature) = %v, %v; want true", merged, err)
+	}
+}
+
+func TestIsBranchUpstreamGoneAfterRemoteDelete(t *testing.T) {
+	repoDir := setupRemovalFixture(t)
+	gitMgr := New()
+
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	runGit(t, repoDir, "push", "-u", "origin", "feature")
+	runGit(t, repoDir, "checkout", "main")
+
+	if gone, err := gitMgr.IsBranchUpstreamGone(repoDir, "feature"); err != nil || gone {
+		t.Fatalf("IsBranchUpstreamGone() before delete = %v, %v; want false", gone, err)
+	}
+
+	runGit(t, repoDir, "push", "origin", "--delete", "feature")
+	if err := gitMgr.FetchPrune(repoDir); err != nil {
+		t.Fatalf("FetchPrune() error = %v", err)
+	}
+
+	if gone, err := gitMgr.IsBranchUpstreamGone(repoDir, "feature"); err != nil || !gone {
+		t.Fatalf("IsBranchUpstreamGone() after delete = %v, %v; want true", gone, err)
+	}
+}
+
+func TestRemoveWorktreeAndDeleteBranch(t *testing.T) {
+	repoDir := setupRemovalFixture(t)
+	gitMgr := New()
+
+	wtPath := filepath.Join(filepath.Dir(repoDir), "feature")
+	runGit(t, repoDir, "worktree", "add", "-b", "feature", wtPath, "main")
+	if err := os.WriteFile(filepath.Join(wtPath, "scratch.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gitMgr.RemoveWorktree(repoDir, wtPath, false); err == nil {
+		t.Fatal("RemoveWorktree() on dirty worktree without force succeeded")
+	}
+	if err := gitMgr.RemoveWorktree(repoDir, wtPath, true); err != nil {
+		t.Fatalf("RemoveWorktree(force) error = %v", err)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Fatalf("worktree path still exists: %v", err)
+	}
+
+	if err := gitMgr.DeleteBranch(repoDir, "feature", false); err != nil {
+		t.Fatalf("DeleteBranch() error = %v", err)
+	}
+	if gitMgr.RefExists(repoDir, "refs/heads/feature") {
+		t.Fatal("branch feature still exists after DeleteBranch()")
+	}
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ShallowInfo describes the history depth of a clone. Depth is the number of
+// commits reachable from HEAD and is only set for shallow clones.
+type ShallowInfo struct {
+	Shallow bool `json:"shallow"`
+	Depth   int  `json:"depth,omitempty"`
+}
+
+// ShallowInfo reports whether the repo at path (a regular clone or bare
+// metadata) is shallow and how deep its HEAD history goes.
+func (g *gitManager) ShallowInfo(path string) (ShallowInfo, error) {
+	output, err := gitOutput(path, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return ShallowInfo{}, fmt.Errorf("failed to inspect shallow state: %w", err)
+	}
+	if output != "true" {
+		return ShallowInfo{}, nil
+	}
+
+	output, err = gitOutput(path, "rev-list", "--count", "HEAD")
+	if err != nil {
+		return ShallowInfo{Shallow: true}, fmt.Errorf("failed to count commits: %w", err)
+	}
+	depth, err := strconv.Atoi(output)
+	if err != nil {
+		return ShallowInfo{Shallow: true}, fmt.Errorf("failed to parse commit count %q: %w", output, err)
+	}
+	return ShallowInfo{Shallow: true, Depth: depth}, nil
+}
+
+// Unshallow fetches the full history of a shallow clone. Shallow clones are
+// single-branch, so a fetch refspec limited to one branch is widened to all
+// branches first.
+func (g *gitManager) Unshallow(path string) error {
+	if err := widenFetchRefspec(path); err != nil {
+		return err
+	}
+	return runFetch(path, "--unshallow", "--quiet", "origin")
+}
+
+// Deepen fetches more history for a shallow clone: depth more commits, or
+// everything newer than since (any date git understands).
+func (g *gitManager) Deepen(path string, depth int, since string) error {
+	args, err := deepenArgs(depth, since)
+	if err != nil {
+		return err
+	}
+	return runFetch(path, args...)
+}
+
+func deepenArgs(depth int, since string) ([]string, error) {
+	switch {
+	case depth > 0 && since != "":
+		return nil, fmt.Errorf("deepen by either depth or date, not both")
+	case depth > 0:
+		return []string{fmt.Sprintf("--deepen=%d", depth), "--quiet", "origin"}, nil
+	case since != "":
+		return []string{"--shallow-since=" + since, "--quiet", "origin"}, nil
+	default:
+		return nil, fmt.Errorf("deepen requires a depth or a date")
+	}
+}
+
+func widenFetchRefspec(path string) error {
+	output, err := gitOutput(path, "config", "--get-all", "remote.origin.fetch")
+	if err != nil {
+		// An unset refspec is left alone; fetch will report a missing remote.
+		return nil
+	}
+	for _, refspec := range strings.Split(output, "\n") {
+		if strings.Contains(refspec, "*") {
+			return nil
+		}
+	}
+	if err := runGitCommand(path, "config", "--replace-all", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return fmt.Errorf("failed to widen fetch refspec: %w", err)
+	}
+	return nil
+}
+
+func runFetch(path string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"fetch"}, args...)...)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fetch: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func gitOutput(path string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShallowInfoDeepenAndUnshallow(t *testing.T) {
+	tmpDir := t.TempDir()
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+
+	runGit(t, "", "init", "--bare", originDir)
+	runGit(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGit(t, "", "clone", originDir, seedDir)
+	runGit(t, seedDir, "config", "user.email", "test@example.com")
+	runGit(t, seedDir, "config", "user.name", "test")
+	for i := 0; i < 5; i++ {
+		makeCommit(t, seedDir, "file.txt", strings.Repeat("x", i+1), "commit")
+	}
+	runGit(t, seedDir, "push", "origin", "HEAD:main", "HEAD:feature")
+
+	originURL := "file://" + originDir
+	gitMgr := New()
+
+	regularDir := filepath.Join(tmpDir, "regular")
+	bareDir := filepath.Join(tmpDir, "repo", ".git")
+	if err := gitMgr.Clone(originURL, regularDir, CloneOptions{Depth: 1, Quiet: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitMgr.Clone(originURL, bareDir, CloneOptions{Bare: true, Depth: 2, Quiet: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitMgr.ConfigureBareRemote(bareDir, "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	info := func(path string) ShallowInfo {
+		t.Helper()
+		got, err := gitMgr.ShallowInfo(path)
+		if err != nil {
+			t.Fatalf("ShallowInfo(%s) error = %v", path, err)
+		}
+		return got
+	}
+
+	if got := info(regularDir); got != (ShallowInfo{Shallow: true, Depth: 1}) {
+		t.Fatalf("ShallowInfo(regular) = %+v", got)
+	}
+	if got := info(bareDir); got != (ShallowInfo{Shallow: true, Depth: 2}) {
+		t.Fatalf("ShallowInfo(bare) = %+v", got)
+	}
+
+	if err := gitMgr.Deepen(bareDir, 2, ""); err != nil {
+		t.Fatalf("Deepen() error = %v", err)
+	}
+	if got := info(bareDir); got != (ShallowInfo{Shallow: true, Depth: 4}) {
+		t.Fatalf("ShallowInfo(bare) after deepen = %+v", got)
+	}
+	if err := gitMgr.Deepen(bareDir, 1, "2001-01-01"); err == nil {
+		t.Fatal("Deepen() expected error for depth and date")
+	}
+	if err := gitMgr.Deepen(bareDir, 0, ""); err == nil {
+		t.Fatal("Deepen() expected error without depth or date")
+	}
+
+	for _, path := range []string{regularDir, bareDir} {
+		if err := gitMgr.Unshallow(path); err != nil {
+			t.Fatalf("Unshallow(%s) error = %v", path, err)
+		}
+		if got := info(path); got.Shallow {
+			t.Fatalf("ShallowInfo(%s) after unshallow = %+v", path, got)
+		}
+	}
+	if !gitMgr.RefExists(regularDir, "refs/remotes/origin/feature") {
+		t.Fatal("unshallowed regular clone should fetch every branch")
+	}
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// sparseConfigKey records a repo's sparse-checkout cone patterns in its git
+// config at clone time so every worktree added later gets the same patterns.
+const sparseConfigKey = "ezgit.sparse"
+
+const (
+	FilterBlobless = "blob:none"
+	FilterTreeless = "tree:0"
+)
+
+// ValidateCloneFilter accepts the partial clone filters ezgit offers; an
+// empty filter means a full clone.
+func ValidateCloneFilter(filter string) error {
+	switch filter {
+	case "", FilterBlobless, FilterTreeless:
+		return nil
+	default:
+		return fmt.Errorf("unsupported partial clone filter %q (use %s or %s)", filter, FilterBlobless, FilterTreeless)
+	}
+}
+
+// SparsePatterns returns the sparse-checkout patterns recorded for the repo
+// at path, or nil when the repo uses full checkouts.
+func (g *gitManager) SparsePatterns(path string) ([]string, error) {
+	cmd := exec.Command("git", "config", "--get-all", sparseConfigKey)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		// git config exits 1 when the key is unset.
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sparse patterns: %w", err)
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns, nil
+}
+
+func recordSparsePatterns(path string, patterns []string) error {
+	for _, pattern := range patterns {
+		if err := runGitCommand(path, "config", "--add", sparseConfigKey, pattern); err != nil {
+			return fmt.Errorf("failed to record sparse pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// checkoutSparse limits a worktree added with --no-checkout to the cone
+// patterns and then populates it, so blobs outside the cone are never
+// fetched in a partial clone.
+func checkoutSparse(worktreePath string, patterns []string) error {
+	args := append([]string{"sparse-checkout", "set", "--cone", "--"}, patterns...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set sparse-checkout patterns: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+
+	cmd = exec.Command("git", "checkout", "--quiet")
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to check out sparse worktree: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSparsePartialCloneAppliesPatternsToWorktrees(t *testing.T) {
+	tmpDir := t.TempDir()
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+
+	runGit(t, "", "init", "--bare", originDir)
+	runGit(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+	runGit(t, originDir, "config", "uploadpack.allowFilter", "true")
+	runGit(t, "", "clone", originDir, seedDir)
+	runGit(t, seedDir, "config", "user.email", "test@example.com")
+	runGit(t, seedDir, "config", "user.name", "test")
+	for _, dir := range []string{"services/api", "services/web", "libs"} {
+		if err := os.MkdirAll(filepath.Join(seedDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		makeCommit(t, seedDir, filepath.Join(dir, "main.go"), "package main\n", "add "+dir)
+	}
+	makeCommit(t, seedDir, "README.md", "hello\n", "add readme")
+	runGit(t, seedDir, "push", "origin", "HEAD:main")
+
+	originURL := "file://" + originDir
+	gitMgr := New()
+	sparse := []string{"services/api", "libs"}
+
+	assertCheckout := func(dir string, present, absent []string) {
+		t.Helper()
+		for _, path := range present {
+			if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+				t.Fatalf("%s missing from %s: %v", path, dir, err)
+			}
+		}
+		for _, path := range absent {
+			if _, err := os.Stat(filepath.Join(dir, path)); !os.IsNotExist(err) {
+				t.Fatalf("%s should not be checked out in %s", path, dir)
+			}
+		}
+	}
+
+	bareDir := filepath.Join(tmpDir, "repo", ".git")
+	if err := gitMgr.Clone(originURL, bareDir, CloneOptions{Bare: true, Filter: FilterBlobless, Sparse: sparse, Quiet: true}); err != nil {
+		t.Fatalf("Clone(bare) error = %v", err)
+	}
+	if got := strings.TrimSpace(runGit(t, bareDir, "config", "remote.origin.partialclonefilter")); got != FilterBlobless {
+		t.Fatalf("partialclonefilter = %q, want %q", got, FilterBlobless)
+	}
+	if got, err := gitMgr.SparsePatterns(bareDir); err != nil || !reflect.DeepEqual(got, sparse) {
+		t.Fatalf("SparsePatterns() = %v, %v; want %v", got, err, sparse)
+	}
+	if err := gitMgr.ConfigureBareRemote(bareDir, "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	mainPath := filepath.Join(tmpDir, "repo", "main")
+	if err := gitMgr.CreateWorktree(bareDir, mainPath, "main"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	assertCheckout(mainPath, []string{"README.md", "services/api/main.go", "libs/main.go"}, []string{"services/web"})
+	if dirty, err := gitMgr.IsWorktreeDirty(mainPath); err != nil || dirty {
+		t.Fatalf("IsWorktreeDirty() = %v, %v; want clean", dirty, err)
+	}
+
+	reviewPath := filepath.Join(tmpDir, "repo", "review")
+	if err := gitMgr.CreateDetachedWorktree(bareDir, reviewPath, "main"); err != nil {
+		t.Fatalf("CreateDetachedWorktree() error = %v", err)
+	}
+	assertCheckout(reviewPath, []string{"services/api/main.go"}, []string{"services/web"})
+
+	regularDir := filepath.Join(tmpDir, "regular")
+	if err := gitMgr.Clone(originURL, regularDir, CloneOptions{Filter: FilterTreeless, Sparse: []string{"services/web"}, Quiet: true}); err != nil {
+		t.Fatalf("Clone(regular) error = %v", err)
+	}
+	assertCheckout(regularDir, []string{"README.md", "services/web/main.go"}, []string{"services/api", "libs"})
+
+	plainDir := filepath.Join(tmpDir, "plain")
+	if err := gitMgr.Clone(originURL, plainDir, CloneOptions{Quiet: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := gitMgr.SparsePatterns(plainDir); err != nil || got != nil {
+		t.Fatalf("SparsePatterns(full clone) = %v, %v; want nil", got, err)
+	}
+
+	if err := gitMgr.Clone(originURL, filepath.Join(tmpDir, "bad"), CloneOptions{Filter: "blob:limit=1k"}); err == nil {
+		t.Fatal("Clone() expected error for unsupported filter")
+	}
+}
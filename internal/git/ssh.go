@@ -1,13 +1,156 @@
 package git
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
+
+	"github.com/kirksw/ezgit/internal/utils"
+)
+
+// Where ssh finds an identity when ezgit does not pin a key.
+const (
+	SSHSourceConfig     = "ssh_config"
+	SSHSourceAgent      = "agent"
+	SSHSourceDefaultKey = "default_key"
 )
 
+// defaultSSHKeys are the key files ssh tries by default, modern types first.
+var defaultSSHKeys = []string{"id_ed25519", "id_ed25519_sk", "id_ecdsa", "id_ecdsa_sk", "id_rsa"}
+
+// SSHIdentity is where ssh will find a key for a host. Path is the config
+// file, agent socket or key file.
+type SSHIdentity struct {
+	Source string
+	Path   string
+}
+
+// SSHCommand is the core.sshCommand that pins keyPath as the only identity.
+func SSHCommand(keyPath string) string {
+	return fmt.Sprintf("ssh -i %s -o IdentitiesOnly=yes", utils.ShellQuote(keyPath))
+}
+
+// SSHHost returns the host of an SSH remote URL (git@host:path or
+// ssh://host/path); ok is false for other transports.
+func SSHHost(remoteURL string) (host string, ok bool) {
+	remoteURL = strings.TrimSpace(remoteURL)
+	if strings.HasPrefix(remoteURL, "ssh://") {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil || parsed.Hostname() == "" {
+			return "", false
+		}
+		return parsed.Hostname(), true
+	}
+	if strings.Contains(remoteURL, "://") {
+		return "", false
+	}
+	// scp-like syntax: [user@]host:path, where host has no slash.
+	hostPart, _, found := strings.Cut(remoteURL, ":")
+	if !found || strings.Contains(hostPart, "/") {
+		return "", false
+	}
+	if _, after, ok := strings.Cut(hostPart, "@"); ok {
+		hostPart = after
+	}
+	return hostPart, hostPart != ""
+}
+
+// DiscoverSSHIdentity reports how ssh will authenticate to host without a
+// pinned key: an IdentityFile or IdentityAgent in ~/.ssh/config, then the
+// agent in SSH_AUTH_SOCK, then a default key file. ok is false when none is
+// found.
+func DiscoverSSHIdentity(host string) (identity SSHIdentity, ok bool) {
+	homeDir, _ := os.UserHomeDir()
+	sshDir := filepath.Join(homeDir, ".ssh")
+
+	configPath := filepath.Join(sshDir, "config")
+	if sshConfigHasIdentity(configPath, host) {
+		return SSHIdentity{Source: SSHSourceConfig, Path: configPath}, true
+	}
+	if sock := strings.TrimSpace(os.Getenv("SSH_AUTH_SOCK")); sock != "" {
+		if _, err := os.Stat(sock); err == nil {
+			return SSHIdentity{Source: SSHSourceAgent, Path: sock}, true
+		}
+	}
+	for _, name := range defaultSSHKeys {
+		keyPath := filepath.Join(sshDir, name)
+		if _, err := os.Stat(keyPath); err == nil {
+			return SSHIdentity{Source: SSHSourceDefaultKey, Path: keyPath}, true
+		}
+	}
+	return SSHIdentity{}, false
+}
+
+// sshConfigHasIdentity reports whether a Host block of the ssh config at
+// configPath that applies to host sets IdentityFile or IdentityAgent. Match
+// blocks and Include are not evaluated.
+func sshConfigHasIdentity(configPath, host string) bool {
+	file, err := os.Open(configPath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	host = strings.ToLower(host)
+	applies := true // options before the first Host apply to every host
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyword, args, _ := strings.Cut(strings.Replace(line, "=", " ", 1), " ")
+		args = strings.TrimSpace(args)
+		switch strings.ToLower(keyword) {
+		case "host":
+			applies = sshHostPatternsMatch(strings.Fields(args), host)
+		case "match":
+			applies = false
+		case "identityfile", "identityagent":
+			if applies && args != "" && !strings.EqualFold(args, "none") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sshHostPatternsMatch applies ssh's Host pattern rules: any pattern must
+// match and no negated (!) pattern may match.
+func sshHostPatternsMatch(patterns []string, host string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		negated := strings.HasPrefix(pattern, "!")
+		if ok, err := path.Match(strings.TrimPrefix(pattern, "!"), host); err != nil || !ok {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// ConfigureSSHKey pins keyPath as the SSH identity of the repo at path by
+// setting core.sshCommand, which its worktrees share.
+func (g *gitManager) ConfigureSSHKey(path, keyPath string) error {
+	if err := g.ValidateSSHKey(keyPath); err != nil {
+		return fmt.Errorf("invalid SSH key: %w", err)
+	}
+	if err := runGitCommand(path, "config", "core.sshCommand", SSHCommand(keyPath)); err != nil {
+		return fmt.Errorf("failed to set core.sshCommand: %w", err)
+	}
+	return nil
+}
+
 func (g *gitManager) ValidateSSHKey(path string) error {
 	if path == "" {
 		return fmt.Errorf("SSH key path is empty")
@@ -30,7 +173,9 @@ func (g *gitManager) ValidateSSHKey(path string) error {
 		return fmt.Errorf("SSH key path is a directory: %s", path)
 	}
 
-	if info.Mode().Perm()&0077 != 0 {
+	// Public keys select an agent-held key (e.g. 1Password) and are meant
+	// to be readable.
+	if !strings.HasSuffix(path, ".pub") && info.Mode().Perm()&0077 != 0 {
 		return fmt.Errorf("SSH key has too open permissions: %s", path)
 	}
 
@@ -41,3 +186,52 @@ func (g *gitManager) ValidateSSHKey(path string) error {
 
 	return nil
 }
+
+// ProbeSSH checks that ssh can authenticate to git@host without prompting.
+// Hosts greet a successful login differently and some exit with status 1,
+// so only a failure of ssh itself counts; see sshProbeResult.
+func ProbeSSH(host string) error {
+	return ProbeSSHKey(host, "")
+}
+
+// ProbeSSHKey is ProbeSSH with keyPath as the only identity, as pinned by
+// core.sshCommand; an empty keyPath lets ssh pick.
+func ProbeSSHKey(host, keyPath string) error {
+	args := []string{"-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=5"}
+	if keyPath != "" {
+		args = append(args, "-i", keyPath, "-o", "IdentitiesOnly=yes")
+	}
+	cmd := exec.Command("ssh", append(args, "git@"+host)...)
+	output, err := cmd.CombinedOutput()
+	return sshProbeResult(host, string(output), err)
+}
+
+// sshProbeResult reads the outcome of 'ssh -T git@host'. GitHub ("successfully
+// authenticated"), GitLab ("Welcome to GitLab") and Bitbucket ("authenticated
+// via") all greet differently, so any answer counts as a login unless ssh
+// failed: exit status 255 (connection or authentication failed), a rejected
+// key, or ssh not running at all.
+func sshProbeResult(host, output string, err error) error {
+	message := strings.TrimSpace(output)
+	failed := strings.Contains(message, "Permission denied")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		failed = failed || exitErr.ExitCode() == 255
+	} else if err != nil {
+		failed = true
+		if message == "" {
+			message = err.Error()
+		}
+	}
+	if !failed {
+		return nil
+	}
+
+	if line, _, found := strings.Cut(message, "\n"); found {
+		message = line
+	}
+	if message == "" {
+		message = "no response"
+	}
+	return fmt.Errorf("ssh to %s failed: %s", host, message)
+}
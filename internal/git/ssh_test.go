@@ -0,0 +1,143 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSSHHost(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{url: "git@github.com:acme/api.git", want: "github.com", wantOK: true},
+		{url: "ssh://git@ghe.example.com:2222/acme/api.git", want: "ghe.example.com", wantOK: true},
+		{url: "github-work:acme/api.git", want: "github-work", wantOK: true},
+		{url: "https://github.com/acme/api.git", wantOK: false},
+		{url: "/srv/git/api.git", wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := SSHHost(tt.url)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("SSHHost(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDiscoverSSHIdentity(t *testing.T) {
+	home := t.TempDir()
+	sshDir := filepath.Join(home, ".ssh")
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	if identity, ok := DiscoverSSHIdentity("github.com"); ok {
+		t.Fatalf("DiscoverSSHIdentity() = %+v, want none", identity)
+	}
+
+	keyPath := filepath.Join(sshDir, "id_ed25519")
+	if err := os.WriteFile(keyPath, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if identity, ok := DiscoverSSHIdentity("github.com"); !ok || identity != (SSHIdentity{Source: SSHSourceDefaultKey, Path: keyPath}) {
+		t.Fatalf("DiscoverSSHIdentity() = %+v, %v; want default key", identity, ok)
+	}
+
+	sock := filepath.Join(home, "agent.sock")
+	if err := os.WriteFile(sock, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SSH_AUTH_SOCK", sock)
+	if identity, ok := DiscoverSSHIdentity("github.com"); !ok || identity.Source != SSHSourceAgent {
+		t.Fatalf("DiscoverSSHIdentity() = %+v, %v; want agent", identity, ok)
+	}
+
+	configPath := filepath.Join(sshDir, "config")
+	config := `Host *.internal !build.internal
+  IdentityFile ~/.ssh/id_internal
+
+Host github.com
+  User git
+`
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if identity, ok := DiscoverSSHIdentity("git.internal"); !ok || identity != (SSHIdentity{Source: SSHSourceConfig, Path: configPath}) {
+		t.Fatalf("DiscoverSSHIdentity(git.internal) = %+v, %v; want ssh config", identity, ok)
+	}
+	// Negated and non-identity Host blocks fall through to the agent.
+	for _, host := range []string{"build.internal", "github.com"} {
+		if identity, ok := DiscoverSSHIdentity(host); !ok || identity.Source != SSHSourceAgent {
+			t.Fatalf("DiscoverSSHIdentity(%s) = %+v, %v; want agent", host, identity, ok)
+		}
+	}
+}
+
+func TestConfigureSSHKeyPinsPublicKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	keyPath := filepath.Join(tmpDir, "id_ed25519")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Skipf("ssh-keygen unavailable: %v\n%s", err, output)
+	}
+	repoDir := filepath.Join(tmpDir, "repo.git")
+	runGit(t, "", "init", "--bare", repoDir)
+
+	// A public key selects an agent-held key and may be world-readable.
+	pubPath := keyPath + ".pub"
+	if err := os.Chmod(pubPath, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().ConfigureSSHKey(repoDir, pubPath); err != nil {
+		t.Fatalf("ConfigureSSHKey() error = %v", err)
+	}
+	got := strings.TrimSpace(runGit(t, repoDir, "config", "core.sshCommand"))
+	if want := SSHCommand(pubPath); got != want {
+		t.Fatalf("core.sshCommand = %q, want %q", got, want)
+	}
+
+	if err := os.Chmod(keyPath, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().ConfigureSSHKey(repoDir, keyPath); err == nil {
+		t.Fatal("ConfigureSSHKey() accepted a world-readable private key")
+	}
+}
+
+func TestSSHProbeResult(t *testing.T) {
+	exit := func(code int) error {
+		return exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	}
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantErr string
+	}{
+		{name: "github", output: "Hi octocat! You've successfully authenticated, but GitHub does not provide shell access.\n", err: exit(1)},
+		{name: "gitlab", output: "Welcome to GitLab, @octocat!\n"},
+		{name: "bitbucket", output: "authenticated via ssh key.\n\nYou can use git to connect to Bitbucket. Shell access is disabled\n"},
+		{name: "rejected key", output: "git@example.com: Permission denied (publickey).\n", err: exit(255), wantErr: "Permission denied (publickey)."},
+		{name: "unreachable", output: "ssh: Could not resolve hostname example.com: Name or service not known\n", err: exit(255), wantErr: "Could not resolve hostname"},
+		{name: "silent failure", err: exit(255), wantErr: "no response"},
+		{name: "no ssh", err: exec.ErrNotFound, wantErr: "executable file not found"},
+	}
+	for _, tt := range tests {
+		err := sshProbeResult("example.com", tt.output, tt.err)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: sshProbeResult() error = %v, want authenticated", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: sshProbeResult() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
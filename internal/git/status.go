@@ -0,0 +1,278 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorktreeInfo describes the state of one linked worktree.
+type WorktreeInfo struct {
+	Name           string     `json:"name"`
+	Path           string     `json:"path"`
+	Branch         string     `json:"branch,omitempty"`
+	Head           string     `json:"head,omitempty"`
+	Detached       bool       `json:"detached"`
+	Subject        string     `json:"subject,omitempty"`
+	CommitDate     *time.Time `json:"commit_date,omitempty"`
+	Dirty          int        `json:"dirty"`
+	Untracked      int        `json:"untracked"`
+	Upstream       string     `json:"upstream,omitempty"`
+	UpstreamGone   bool       `json:"upstream_gone"`
+	Ahead          int        `json:"ahead"`
+	Behind         int        `json:"behind"`
+	Locked         bool       `json:"locked"`
+	LockReason     string     `json:"lock_reason,omitempty"`
+	Prunable       bool       `json:"prunable"`
+	PrunableReason string     `json:"prunable_reason,omitempty"`
+	Error          string     `json:"error,omitempty"`
+}
+
+// IsDirty reports whether the worktree has modified or untracked files.
+func (w WorktreeInfo) IsDirty() bool {
+	return w.Dirty > 0 || w.Untracked > 0
+}
+
+// ShortHead returns the abbreviated HEAD commit.
+func (w WorktreeInfo) ShortHead() string {
+	if len(w.Head) > 7 {
+		return w.Head[:7]
+	}
+	return w.Head
+}
+
+// Badges returns compact status markers: error/prunable/locked/gone flags, ~dirty and
+// ?untracked counts, and ↑ahead/↓behind versus upstream. A clean, up to date
+// worktree has none.
+func (w WorktreeInfo) Badges() []string {
+	var badges []string
+	if w.Error != "" {
+		badges = append(badges, "error")
+	}
+	if w.Prunable {
+		badges = append(badges, "prunable")
+	}
+	if w.Locked {
+		badges = append(badges, "locked")
+	}
+	if w.UpstreamGone {
+		badges = append(badges, "gone")
+	}
+	if w.Dirty > 0 {
+		badges = append(badges, fmt.Sprintf("~%d", w.Dirty))
+	}
+	if w.Untracked > 0 {
+		badges = append(badges, fmt.Sprintf("?%d", w.Untracked))
+	}
+	if w.Ahead > 0 {
+		badges = append(badges, fmt.Sprintf("↑%d", w.Ahead))
+	}
+	if w.Behind > 0 {
+		badges = append(badges, fmt.Sprintf("↓%d", w.Behind))
+	}
+	return badges
+}
+
+// ListWorktreeInfo returns the same worktrees as ListWorktrees with their
+// branch, HEAD commit, working tree and upstream state filled in. A worktree
+// whose state cannot be read is still listed, with Error set and its counts
+// unknown rather than clean.
+func (g *gitManager) ListWorktreeInfo(path string) ([]WorktreeInfo, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	repoPath := filepath.Clean(path)
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		repoPath = abs
+	}
+
+	var infos []WorktreeInfo
+	seen := make(map[string]struct{})
+	for _, info := range parseWorktreeList(string(output)) {
+		name, ok := worktreeNameForPath(repoPath, info.Path)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		info.Name = name
+
+		// Prunable worktrees point at a missing directory, so there is no
+		// working tree to inspect.
+		if !info.Prunable {
+			if err := readWorktreeStatus(&info); err != nil {
+				info.Error = err.Error()
+			}
+			readHeadCommit(&info)
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// parseWorktreeList parses `git worktree list --porcelain` output. Bare
+// entries are dropped.
+func parseWorktreeList(output string) []WorktreeInfo {
+	var infos []WorktreeInfo
+	var current *WorktreeInfo
+	bare := false
+
+	flush := func() {
+		if current != nil && !bare {
+			infos = append(infos, *current)
+		}
+		current = nil
+		bare = false
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			flush()
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			flush()
+			current = &WorktreeInfo{Path: value}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "detached":
+			current.Detached = true
+		case "bare":
+			bare = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+			current.PrunableReason = value
+		}
+	}
+	flush()
+
+	return infos
+}
+
+// WorktreeStatus returns the state of the working tree at path, which may be
+// a regular clone's root as well as a linked worktree.
+func (g *gitManager) WorktreeStatus(path string) (WorktreeInfo, error) {
+	info := WorktreeInfo{Path: path}
+	if err := readWorktreeStatus(&info); err != nil {
+		return info, err
+	}
+	readHeadCommit(&info)
+	return info, nil
+}
+
+// StaleBranches lists local branches whose upstream branch no longer exists
+// on the remote.
+func (g *gitManager) StaleBranches(path string) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)%00%(upstream:track)", "refs/heads")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var stale []string
+	for _, line := range strings.Split(string(output), "\n") {
+		branch, track, ok := strings.Cut(line, "\x00")
+		if ok && track == "[gone]" {
+			stale = append(stale, branch)
+		}
+	}
+	return stale, nil
+}
+
+func readWorktreeStatus(info *WorktreeInfo) error {
+	cmd := exec.Command("git", "status", "--porcelain=v2", "--branch")
+	cmd.Dir = info.Path
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to read status of %s: %w", info.Path, err)
+	}
+	parseStatusV2(string(output), info)
+	return nil
+}
+
+// parseStatusV2 fills branch, upstream, ahead/behind and change counts from
+// `git status --porcelain=v2 --branch` output. Git omits branch.ab when the
+// upstream is configured but its remote branch is gone.
+func parseStatusV2(output string, info *WorktreeInfo) {
+	hasAheadBehind := false
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.oid "):
+			if oid := strings.TrimPrefix(line, "# branch.oid "); oid != "(initial)" {
+				info.Head = oid
+			}
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); head == "(detached)" {
+				info.Branch = ""
+				info.Detached = true
+			} else {
+				info.Branch = head
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			info.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			hasAheadBehind = true
+			for _, field := range strings.Fields(strings.TrimPrefix(line, "# branch.ab ")) {
+				n, err := strconv.Atoi(field[1:])
+				if err != nil {
+					continue
+				}
+				if field[0] == '+' {
+					info.Ahead = n
+				} else {
+					info.Behind = n
+				}
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "), strings.HasPrefix(line, "u "):
+			info.Dirty++
+		case strings.HasPrefix(line, "? "):
+			info.Untracked++
+		}
+	}
+	info.UpstreamGone = info.Upstream != "" && !hasAheadBehind
+}
+
+// readHeadCommit fills the HEAD commit subject and date. Worktrees on an
+// unborn branch have no commit, so failures are ignored.
+func readHeadCommit(info *WorktreeInfo) {
+	cmd := exec.Command("git", "log", "-1", "--format=%s%x00%cI", "HEAD")
+	cmd.Dir = info.Path
+	output, err := cmd.Output()
+	if err != nil {
+		return
+	}
+
+	subject, date, ok := strings.Cut(strings.TrimSpace(string(output)), "\x00")
+	if !ok {
+		return
+	}
+	info.Subject = subject
+	if commitDate, err := time.Parse(time.RFC3339, date); err == nil {
+		info.CommitDate = &commitDate
+	}
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	output := `worktree /src/repo/.git
+bare
+
+worktree /src/repo/main
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /src/repo/review
+HEAD 2222222222222222222222222222222222222222
+detached
+locked reviewing
+
+worktree /src/repo/gone
+HEAD 3333333333333333333333333333333333333333
+branch refs/heads/feature/gone
+prunable gitdir file points to non-existent location
+`
+
+	got := parseWorktreeList(output)
+	want := []WorktreeInfo{
+		{Path: "/src/repo/main", Head: "1111111111111111111111111111111111111111", Branch: "main"},
+		{Path: "/src/repo/review", Head: "2222222222222222222222222222222222222222", Detached: true, Locked: true, LockReason: "reviewing"},
+		{Path: "/src/repo/gone", Head: "3333333333333333333333333333333333333333", Branch: "feature/gone", Prunable: true, PrunableReason: "gitdir file points to non-existent location"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseWorktreeList() = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseStatusV2(t *testing.T) {
+	output := `# branch.oid 1111111111111111111111111111111111111111
+# branch.head feature
+# branch.upstream origin/feature
+# branch.ab +2 -3
+1 .M N... 100644 100644 100644 abc abc a.txt
+2 R. N... 100644 100644 100644 abc abc R100 b.txt	c.txt
+u UU N... 100644 100644 100644 100644 abc abc abc d.txt
+? scratch.txt
+? notes.txt
+! ignored.txt
+`
+
+	var info WorktreeInfo
+	parseStatusV2(output, &info)
+
+	if info.Upstream != "origin/feature" || info.Ahead != 2 || info.Behind != 3 {
+		t.Fatalf("upstream state = %q +%d -%d", info.Upstream, info.Ahead, info.Behind)
+	}
+	if info.Dirty != 3 || info.Untracked != 2 {
+		t.Fatalf("Dirty=%d Untracked=%d, want 3 and 2", info.Dirty, info.Untracked)
+	}
+	if got := info.Badges(); !reflect.DeepEqual(got, []string{"~3", "?2", "↑2", "↓3"}) {
+		t.Fatalf("Badges() = %v", got)
+	}
+}
+
+func TestListWorktreeInfo(t *testing.T) {
+	repoDir := setupRemovalFixture(t)
+	gitMgr := New()
+
+	featurePath := filepath.Join(repoDir, "feature")
+	runGit(t, repoDir, "worktree", "add", "-b", "feature", featurePath, "origin/main")
+	makeCommit(t, featurePath, "feature.txt", "feature\n", "add feature")
+	if err := os.WriteFile(filepath.Join(featurePath, "scratch.txt"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reviewPath := filepath.Join(repoDir, "review")
+	runGit(t, repoDir, "worktree", "add", "--detach", reviewPath, "origin/main")
+	runGit(t, repoDir, "worktree", "lock", "--reason", "busy", reviewPath)
+
+	infos, err := gitMgr.ListWorktreeInfo(repoDir)
+	if err != nil {
+		t.Fatalf("ListWorktreeInfo() error = %v", err)
+	}
+	byName := make(map[string]WorktreeInfo, len(infos))
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+	if len(byName) != 2 {
+		t.Fatalf("ListWorktreeInfo() = %+v, want feature and review", infos)
+	}
+
+	feature := byName["feature"]
+	if feature.Branch != "feature" || feature.Detached {
+		t.Fatalf("feature branch = %q detached=%v", feature.Branch, feature.Detached)
+	}
+	if feature.Upstream != "origin/main" || feature.Ahead != 1 || feature.Behind != 0 {
+		t.Fatalf("feature upstream = %q +%d -%d", feature.Upstream, feature.Ahead, feature.Behind)
+	}
+	if feature.Untracked != 1 || !feature.IsDirty() {
+		t.Fatalf("feature Untracked=%d, want 1", feature.Untracked)
+	}
+	if feature.Subject != "add feature" || feature.CommitDate == nil {
+		t.Fatalf("feature last commit = %q %v", feature.Subject, feature.CommitDate)
+	}
+
+	review := byName["review"]
+	if !review.Detached || review.Branch != "" || len(review.ShortHead()) != 7 {
+		t.Fatalf("review = %+v, want detached", review)
+	}
+	if !review.Locked || review.LockReason != "busy" {
+		t.Fatalf("review locked=%v reason=%q", review.Locked, review.LockReason)
+	}
+}
+
+func TestParseStatusV2DetachedAndGoneUpstream(t *testing.T) {
+	var detached WorktreeInfo
+	parseStatusV2("# branch.oid 1111111111111111111111111111111111111111\n# branch.head (detached)\n", &detached)
+	if !detached.Detached || detached.Branch != "" || detached.ShortHead() != "1111111" {
+		t.Fatalf("detached = %+v", detached)
+	}
+
+	var gone WorktreeInfo
+	parseStatusV2("# branch.oid 1111111111111111111111111111111111111111\n# branch.head feature\n# branch.upstream origin/feature\n", &gone)
+	if gone.Branch != "feature" || !gone.UpstreamGone {
+		t.Fatalf("gone = %+v, want UpstreamGone", gone)
+	}
+}
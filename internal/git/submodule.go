@@ -0,0 +1,212 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// submodulesConfigKey and lfsConfigKey record a repo's checkout extras in
+// its git config at clone time so every worktree added later gets the same
+// treatment.
+const (
+	submodulesConfigKey = "ezgit.submodules"
+	lfsConfigKey        = "ezgit.lfs"
+)
+
+const (
+	SubmodulesRecursive = "recursive"
+	SubmodulesShallow   = "shallow"
+
+	LFSPull = "pull"
+	LFSSkip = "skip"
+)
+
+// CheckoutExtras says what to do in each checkout of a repo besides the
+// files themselves. Submodules is SubmodulesRecursive or SubmodulesShallow
+// (depth 1) to check out submodules. LFS is LFSPull to download LFS objects
+// or LFSSkip to leave pointer files; empty keeps git-lfs's own behaviour.
+type CheckoutExtras struct {
+	Submodules string
+	LFS        string
+}
+
+// ValidateCheckoutExtras accepts the submodule and LFS modes ezgit offers.
+func ValidateCheckoutExtras(extras CheckoutExtras) error {
+	switch extras.Submodules {
+	case "", SubmodulesRecursive, SubmodulesShallow:
+	default:
+		return fmt.Errorf("unsupported submodules mode %q (use %s or %s)", extras.Submodules, SubmodulesRecursive, SubmodulesShallow)
+	}
+	switch extras.LFS {
+	case "", LFSPull, LFSSkip:
+	default:
+		return fmt.Errorf("unsupported lfs mode %q (use %s or %s)", extras.LFS, LFSPull, LFSSkip)
+	}
+	return nil
+}
+
+// checkoutExtrasConfig returns the clone --config settings that record
+// extras. Skipping LFS also installs git-lfs's --skip filters in the repo, so
+// the initial checkout and every worktree leave pointer files.
+func checkoutExtrasConfig(extras CheckoutExtras) []string {
+	var settings []string
+	if extras.Submodules != "" {
+		settings = append(settings, submodulesConfigKey+"="+extras.Submodules)
+	}
+	if extras.LFS != "" {
+		settings = append(settings, lfsConfigKey+"="+extras.LFS)
+	}
+	if extras.LFS == LFSSkip {
+		settings = append(settings,
+			"filter.lfs.smudge=git-lfs smudge --skip -- %f",
+			"filter.lfs.process=git-lfs filter-process --skip",
+		)
+	}
+	return settings
+}
+
+// checkoutExtrasSteps returns the git commands run in a fresh checkout.
+func checkoutExtrasSteps(extras CheckoutExtras) [][]string {
+	var steps [][]string
+	switch extras.Submodules {
+	case SubmodulesRecursive:
+		steps = append(steps, []string{"submodule", "update", "--init", "--recursive"})
+	case SubmodulesShallow:
+		steps = append(steps, []string{"submodule", "update", "--init", "--recursive", "--depth", "1"})
+	}
+	if extras.LFS == LFSPull {
+		steps = append(steps, []string{"lfs", "pull"})
+	}
+	return steps
+}
+
+// RepoCheckoutExtras returns the checkout extras recorded for the repo at
+// path, or the zero value when none were.
+func (g *gitManager) RepoCheckoutExtras(path string) (CheckoutExtras, error) {
+	submodules, err := gitConfigValue(path, submodulesConfigKey)
+	if err != nil {
+		return CheckoutExtras{}, err
+	}
+	lfs, err := gitConfigValue(path, lfsConfigKey)
+	if err != nil {
+		return CheckoutExtras{}, err
+	}
+	return CheckoutExtras{Submodules: submodules, LFS: lfs}, nil
+}
+
+func gitConfigValue(path, key string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		// git config exits 1 when the key is unset.
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// populateCheckout checks out submodules and LFS objects in a new checkout.
+func populateCheckout(checkoutPath string, extras CheckoutExtras) error {
+	for _, args := range checkoutExtrasSteps(extras) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = checkoutPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			message := strings.TrimSpace(string(output))
+			if args[0] == "lfs" {
+				message += "\n(is git-lfs installed? skip LFS with --lfs skip or lfs = \"skip\" in [git].clone_rules)"
+			}
+			return fmt.Errorf("git %s failed in %s: %w\n%s", strings.Join(args, " "), checkoutPath, err, message)
+		}
+	}
+	return nil
+}
+
+// SubmoduleInfo counts the submodules of a checkout: Initialized are checked
+// out, OutOfDate are checked out at another commit than recorded.
+type SubmoduleInfo struct {
+	Mode        string `json:"mode,omitempty"`
+	Total       int    `json:"total"`
+	Initialized int    `json:"initialized"`
+	OutOfDate   int    `json:"out_of_date"`
+}
+
+// LFSInfo describes LFS use in a checkout. Tracked is set when
+// .gitattributes routes files through the lfs filter; Pointers counts LFS
+// files whose content has not been downloaded.
+type LFSInfo struct {
+	Mode      string `json:"mode,omitempty"`
+	Tracked   bool   `json:"tracked"`
+	Installed bool   `json:"installed"`
+	Files     int    `json:"files"`
+	Pointers  int    `json:"pointers"`
+}
+
+// SubmoduleStatus reports the submodules of the checkout at worktreePath.
+func (g *gitManager) SubmoduleStatus(worktreePath string) (SubmoduleInfo, error) {
+	var info SubmoduleInfo
+	if _, err := os.Stat(filepath.Join(worktreePath, ".gitmodules")); os.IsNotExist(err) {
+		return info, nil
+	}
+
+	cmd := exec.Command("git", "submodule", "status", "--recursive")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return info, fmt.Errorf("failed to read submodule status: %w", err)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line == "" {
+			continue
+		}
+		info.Total++
+		switch line[0] {
+		case '-':
+		case '+':
+			info.Initialized++
+			info.OutOfDate++
+		default:
+			info.Initialized++
+		}
+	}
+	return info, nil
+}
+
+// LFSStatus reports LFS use in the checkout at worktreePath.
+func (g *gitManager) LFSStatus(worktreePath string) (LFSInfo, error) {
+	var info LFSInfo
+	if data, err := os.ReadFile(filepath.Join(worktreePath, ".gitattributes")); err == nil {
+		info.Tracked = strings.Contains(string(data), "filter=lfs")
+	}
+	if _, err := exec.LookPath("git-lfs"); err != nil {
+		return info, nil
+	}
+	info.Installed = true
+	if !info.Tracked {
+		return info, nil
+	}
+
+	cmd := exec.Command("git", "lfs", "ls-files")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return info, fmt.Errorf("failed to list LFS files: %w", err)
+	}
+	// Lines look like "<oid> * <path>"; "-" instead of "*" marks a pointer.
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		info.Files++
+		if fields[1] == "-" {
+			info.Pointers++
+		}
+	}
+	return info, nil
+}
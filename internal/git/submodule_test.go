@@ -0,0 +1,103 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckoutExtrasInitSubmodulesInCloneAndWorktrees(t *testing.T) {
+	// Local submodule URLs are refused by default since git 2.38.1.
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	tmpDir := t.TempDir()
+	libDir := filepath.Join(tmpDir, "lib")
+	appDir := filepath.Join(tmpDir, "app")
+	for _, dir := range []string{libDir, appDir} {
+		runGit(t, "", "init", "-b", "main", dir)
+		runGit(t, dir, "config", "user.email", "test@example.com")
+		runGit(t, dir, "config", "user.name", "test")
+	}
+	makeCommit(t, libDir, "lib.txt", "lib\n", "add lib")
+	makeCommit(t, appDir, "README.md", "app\n", "add readme")
+	runGit(t, appDir, "submodule", "add", "--quiet", "file://"+libDir, "vendor/lib")
+	runGit(t, appDir, "commit", "--quiet", "-m", "add submodule")
+
+	gitMgr := New()
+	appURL := "file://" + appDir
+	extras := CheckoutExtras{Submodules: SubmodulesRecursive, LFS: LFSSkip}
+
+	bareDir := filepath.Join(tmpDir, "repo", ".git")
+	if err := gitMgr.Clone(appURL, bareDir, CloneOptions{Bare: true, Quiet: true, CheckoutExtras: extras}); err != nil {
+		t.Fatalf("Clone(bare) error = %v", err)
+	}
+	if got, err := gitMgr.RepoCheckoutExtras(bareDir); err != nil || got != extras {
+		t.Fatalf("RepoCheckoutExtras() = %+v, %v; want %+v", got, err, extras)
+	}
+	if got := strings.TrimSpace(runGit(t, bareDir, "config", "filter.lfs.smudge")); !strings.Contains(got, "--skip") {
+		t.Fatalf("filter.lfs.smudge = %q, want the --skip filter", got)
+	}
+
+	worktreePath := filepath.Join(tmpDir, "repo", "main")
+	if err := gitMgr.CreateWorktree(bareDir, worktreePath, "main"); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "vendor", "lib", "lib.txt")); err != nil {
+		t.Fatalf("submodule not checked out in worktree: %v", err)
+	}
+	if got, err := gitMgr.SubmoduleStatus(worktreePath); err != nil || got != (SubmoduleInfo{Total: 1, Initialized: 1}) {
+		t.Fatalf("SubmoduleStatus(worktree) = %+v, %v", got, err)
+	}
+
+	plainDir := filepath.Join(tmpDir, "plain")
+	if err := gitMgr.Clone(appURL, plainDir, CloneOptions{Quiet: true}); err != nil {
+		t.Fatalf("Clone(plain) error = %v", err)
+	}
+	if got, err := gitMgr.SubmoduleStatus(plainDir); err != nil || got != (SubmoduleInfo{Total: 1}) {
+		t.Fatalf("SubmoduleStatus(plain) = %+v, %v; want one uninitialized submodule", got, err)
+	}
+
+	shallowDir := filepath.Join(tmpDir, "shallow")
+	if err := gitMgr.Clone(appURL, shallowDir, CloneOptions{Quiet: true, CheckoutExtras: CheckoutExtras{Submodules: SubmodulesShallow}}); err != nil {
+		t.Fatalf("Clone(shallow submodules) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(shallowDir, "vendor", "lib", "lib.txt")); err != nil {
+		t.Fatalf("submodule not checked out in clone: %v", err)
+	}
+
+	if _, err := cloneArgs(appURL, plainDir, CloneOptions{CheckoutExtras: CheckoutExtras{LFS: "sometimes"}}); err == nil {
+		t.Fatal("cloneArgs() accepted an unknown lfs mode")
+	}
+
+	// A worktree whose submodules cannot be fetched is not left registered
+	// half-populated.
+	if err := os.Rename(libDir, libDir+".moved"); err != nil {
+		t.Fatal(err)
+	}
+	featurePath := filepath.Join(tmpDir, "repo", "feature")
+	if err := gitMgr.CreateFeatureWorktree(bareDir, featurePath, "feature", "main"); err == nil {
+		t.Fatal("CreateFeatureWorktree() succeeded without the submodule remote")
+	}
+	if _, err := os.Stat(featurePath); !os.IsNotExist(err) {
+		t.Fatalf("incomplete worktree left at %s: %v", featurePath, err)
+	}
+	if registered, err := gitMgr.(*gitManager).isWorktreeRegistered(bareDir, featurePath); err != nil || registered {
+		t.Fatalf("isWorktreeRegistered() = %v, %v; want the incomplete worktree unregistered", registered, err)
+	}
+	if gitMgr.RefExists(bareDir, "refs/heads/feature") {
+		t.Fatal("branch created for the incomplete worktree was kept")
+	}
+
+	if err := os.Rename(libDir+".moved", libDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitMgr.CreateFeatureWorktree(bareDir, featurePath, "feature", "main"); err != nil {
+		t.Fatalf("CreateFeatureWorktree() retry error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(featurePath, "vendor", "lib", "lib.txt")); err != nil {
+		t.Fatalf("submodule not checked out after retry: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func (g *gitManager) FetchPrune(path string) error {
+	cmd := exec.Command("git", "fetch", "--prune", "origin")
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fetch: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// FastForward moves the worktree's branch to its upstream. It fails rather
+// than creating a merge commit when the branches have diverged.
+func (g *gitManager) FastForward(worktreePath string) error {
+	cmd := exec.Command("git", "merge", "--ff-only", "--quiet", "@{upstream}")
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fast-forward: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PullRequestRef is the local ref FetchPullRequest stores a pull request
+// head in. It lives outside refs/remotes so 'fetch --prune' leaves it alone.
+func PullRequestRef(number int) string {
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
+// FetchPullRequest fetches a pull request head from origin, which also works
+// for pull requests opened from forks, and returns the local ref.
+func (g *gitManager) FetchPullRequest(path string, number int) (string, error) {
+	ref := PullRequestRef(number)
+	cmd := exec.Command("git", "fetch", "--quiet", "origin", "+"+ref+":"+ref)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to fetch pull request #%d: %w\n%s", number, err, strings.TrimSpace(string(output)))
+	}
+	return ref, nil
+}
+
+// CheckoutDetached moves a worktree to ref with a detached HEAD.
+func (g *gitManager) CheckoutDetached(worktreePath, ref string) error {
+	cmd := exec.Command("git", "checkout", "--quiet", "--detach", ref)
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to check out %s: %w\n%s", ref, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// worktreeStateFiles are copied from a linked worktree's admin directory into
+// .git when it becomes the main checkout, so its branch, staged changes and
+// sparse-checkout settings carry over.
+var worktreeStateFiles = []string{
+	"HEAD",
+	"index",
+	"config.worktree",
+	filepath.Join("info", "sparse-checkout"),
+}
+
+// ConvertToRegular turns the bare .git + worktrees layout at path back into a
+// regular clone whose working tree is the checkout at worktreePath. The other
+// worktree directories under path are deleted.
+//
+// The new layout is assembled in a temporary sibling directory and swapped in
+// with a rename; if any step before the swap fails every move is undone, so
+// path is either fully converted or left as it was.
+func (g *gitManager) ConvertToRegular(path, worktreePath string) (err error) {
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	worktreePath, err = filepath.Abs(worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve worktree path: %w", err)
+	}
+
+	metadataPath := filepath.Join(path, ".git")
+	if info, statErr := os.Stat(metadataPath); statErr != nil || !info.IsDir() {
+		return fmt.Errorf("not a worktree layout repository: %s", path)
+	}
+	if bare, _ := gitOutput(metadataPath, "config", "--bool", "core.bare"); bare != "true" {
+		return fmt.Errorf("not a bare repository: %s", metadataPath)
+	}
+	if !strings.HasPrefix(worktreePath, path+string(filepath.Separator)) {
+		return fmt.Errorf("worktree %s is not inside %s", worktreePath, path)
+	}
+	registered, err := g.isWorktreeRegistered(metadataPath, worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to inspect existing worktrees: %w", err)
+	}
+	if !registered {
+		return fmt.Errorf("not a worktree of %s: %s", path, worktreePath)
+	}
+
+	adminDir, err := gitOutput(worktreePath, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return fmt.Errorf("failed to locate worktree metadata: %w", err)
+	}
+	adminRel, err := filepath.Rel(normalizePathForCompare(metadataPath), normalizePathForCompare(adminDir))
+	if err != nil || !strings.HasPrefix(adminRel, "worktrees"+string(filepath.Separator)) {
+		return fmt.Errorf("unexpected worktree metadata location: %s", adminDir)
+	}
+
+	parentDir := filepath.Dir(path)
+	stagingDir, err := os.MkdirTemp(parentDir, "."+filepath.Base(path)+".regular-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	var tx rollback
+	defer func() {
+		if err != nil {
+			err = tx.undo(err)
+			_ = os.RemoveAll(stagingDir)
+		}
+	}()
+
+	stagedMetadata := filepath.Join(stagingDir, ".git")
+	if err := tx.move(metadataPath, stagedMetadata); err != nil {
+		return err
+	}
+
+	items, err := os.ReadDir(worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to read worktree: %w", err)
+	}
+	for _, item := range items {
+		if item.Name() == ".git" {
+			continue
+		}
+		if err := tx.move(filepath.Join(worktreePath, item.Name()), filepath.Join(stagingDir, item.Name())); err != nil {
+			return err
+		}
+	}
+
+	stagedAdmin := filepath.Join(stagedMetadata, adminRel)
+	for _, name := range worktreeStateFiles {
+		restore, copyErr := replaceFile(filepath.Join(stagedAdmin, name), filepath.Join(stagedMetadata, name))
+		if copyErr != nil {
+			return copyErr
+		}
+		if restore != nil {
+			tx.add(restore)
+		}
+	}
+
+	if err := runGitCommand(stagingDir, "config", "--file", filepath.Join(stagedMetadata, "config"), "core.bare", "false"); err != nil {
+		return fmt.Errorf("failed to set core.bare: %w", err)
+	}
+	tx.add(func() error {
+		return runGitCommand(stagingDir, "config", "--file", filepath.Join(stagedMetadata, "config"), "core.bare", "true")
+	})
+
+	oldDir, err := os.MkdirTemp(parentDir, "."+filepath.Base(path)+".worktrees-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := os.Remove(oldDir); err != nil {
+		return fmt.Errorf("failed to prepare temporary directory: %w", err)
+	}
+	if err := os.Rename(path, oldDir); err != nil {
+		return fmt.Errorf("failed to move %s aside: %w", path, err)
+	}
+	if err := os.Rename(stagingDir, path); err != nil {
+		if restoreErr := os.Rename(oldDir, path); restoreErr != nil {
+			return fmt.Errorf("failed to move converted repository into place: %w (original left at %s)", err, oldDir)
+		}
+		return fmt.Errorf("failed to move converted repository into place: %w", err)
+	}
+
+	// The conversion is committed; what follows is cleanup and must not
+	// trigger a rollback.
+	tx = rollback{}
+	if err := os.RemoveAll(oldDir); err != nil {
+		return fmt.Errorf("converted, but failed to remove old worktrees at %s: %w", oldDir, err)
+	}
+	_ = os.RemoveAll(filepath.Join(path, ".git", adminRel))
+	if err := g.PruneWorktrees(path); err != nil {
+		return fmt.Errorf("converted, but failed to prune worktree metadata: %w", err)
+	}
+	return nil
+}
+
+// replaceFile copies src over dst and returns a func restoring dst. A missing
+// src is not an error and returns no restore func.
+func replaceFile(src, dst string) (func() error, error) {
+	data, err := os.ReadFile(src)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	previous, readErr := os.ReadFile(dst)
+	existed := readErr == nil
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return nil, fmt.Errorf("failed to read %s: %w", dst, readErr)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+
+	return func() error {
+		if existed {
+			return os.WriteFile(dst, previous, 0644)
+		}
+		return os.Remove(dst)
+	}, nil
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupUnconvertFixture(t *testing.T) (repoDir string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	originDir := filepath.Join(tmpDir, "origin.git")
+	seedDir := filepath.Join(tmpDir, "seed")
+	runGit(t, "", "init", "--bare", originDir)
+	runGit(t, "", "clone", originDir, seedDir)
+	runGit(t, seedDir, "config", "user.email", "test@example.com")
+	runGit(t, seedDir, "config", "user.name", "test")
+	makeCommit(t, seedDir, "README.md", "hello\n", "initial commit")
+	runGit(t, seedDir, "push", "origin", "HEAD:main")
+	runGit(t, seedDir, "checkout", "-b", "feature-a")
+	makeCommit(t, seedDir, "a.txt", "a\n", "feature-a commit")
+	runGit(t, seedDir, "push", "origin", "feature-a")
+	runGit(t, originDir, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	repoDir = filepath.Join(tmpDir, "repo")
+	metadataPath := filepath.Join(repoDir, ".git")
+	gitMgr := New()
+	runGit(t, "", "clone", "--bare", originDir, metadataPath)
+	if err := gitMgr.ConfigureBareRemote(metadataPath, "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitMgr.CreateWorktree(metadataPath, filepath.Join(repoDir, "main"), "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitMgr.CreateWorktree(metadataPath, filepath.Join(repoDir, "feature-a"), "feature-a"); err != nil {
+		t.Fatal(err)
+	}
+	return repoDir
+}
+
+func TestConvertToRegularKeepsChosenWorktree(t *testing.T) {
+	repoDir := setupUnconvertFixture(t)
+	featureDir := filepath.Join(repoDir, "feature-a")
+
+	// Staged and untracked changes in the kept worktree must survive.
+	if err := os.WriteFile(filepath.Join(featureDir, "staged.txt"), []byte("staged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, featureDir, "add", "staged.txt")
+	if err := os.WriteFile(filepath.Join(featureDir, "untracked.txt"), []byte("untracked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := New().ConvertToRegular(repoDir, featureDir); err != nil {
+		t.Fatalf("ConvertToRegular() error = %v", err)
+	}
+
+	if got := strings.TrimSpace(runGit(t, repoDir, "config", "--bool", "core.bare")); got != "false" {
+		t.Fatalf("core.bare = %q, want false", got)
+	}
+	if got := strings.TrimSpace(runGit(t, repoDir, "rev-parse", "--abbrev-ref", "HEAD")); got != "feature-a" {
+		t.Fatalf("HEAD = %q, want feature-a", got)
+	}
+	if got := strings.TrimSpace(runGit(t, repoDir, "status", "--porcelain")); got != "A  staged.txt\n?? untracked.txt" {
+		t.Fatalf("status = %q", got)
+	}
+	if got := strings.TrimSpace(runGit(t, repoDir, "config", "--get", "remote.origin.fetch")); got != "+refs/heads/*:refs/remotes/origin/*" {
+		t.Fatalf("remote.origin.fetch = %q", got)
+	}
+	for _, name := range []string{"main", "feature-a"} {
+		if _, err := os.Stat(filepath.Join(repoDir, name)); !os.IsNotExist(err) {
+			t.Fatalf("worktree dir %s still exists (err=%v)", name, err)
+		}
+	}
+	if got := strings.Count(runGit(t, repoDir, "worktree", "list", "--porcelain"), "worktree "); got != 1 {
+		t.Fatalf("worktree count = %d, want 1", got)
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(repoDir), ".repo.*"))
+	if err != nil || len(leftovers) != 0 {
+		t.Fatalf("temporary directories left behind: %v (err=%v)", leftovers, err)
+	}
+}
+
+func TestConvertToRegularRejectsUnknownWorktree(t *testing.T) {
+	repoDir := setupUnconvertFixture(t)
+	stray := filepath.Join(repoDir, "stray")
+	if err := os.MkdirAll(stray, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := New().ConvertToRegular(repoDir, stray); err == nil {
+		t.Fatal("expected error for a directory that is not a worktree")
+	}
+	if got := strings.TrimSpace(runGit(t, filepath.Join(repoDir, ".git"), "config", "--bool", "core.bare")); got != "true" {
+		t.Fatalf("core.bare = %q, want true", got)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "main", "README.md")); err != nil {
+		t.Fatalf("main worktree was touched: %v", err)
+	}
+}
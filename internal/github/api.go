@@ -28,6 +28,9 @@ type Repo struct {
 	Description     string    `json:"description"`
 	Language        string    `json:"language"`
 	StargazersCount int       `json:"stargazers_count"`
+	Topics          []string  `json:"topics"`
+	Archived        bool      `json:"archived"`
+	Fork            bool      `json:"fork"`
 }
 
 func NewClient(token string) *GitHubClient {
@@ -61,26 +64,89 @@ func (g *GitHubClient) FetchPrivateReposCreatedAfter(createdAfter time.Time) ([]
 }
 
 func (g *GitHubClient) ValidateToken() error {
+	_, err := g.InspectToken()
+	return err
+}
+
+// TokenInfo describes the identity and OAuth scopes behind a token.
+// ScopesKnown is false for fine-grained and GitHub App tokens, which do not
+// report X-OAuth-Scopes.
+type TokenInfo struct {
+	Login       string
+	Scopes      []string
+	ScopesKnown bool
+}
+
+// impliedScopes lists the scopes that grant a narrower one.
+var impliedScopes = map[string][]string{
+	"read:org": {"write:org", "admin:org"},
+}
+
+func (t *TokenInfo) HasScope(scope string) bool {
+	if !t.ScopesKnown {
+		return true
+	}
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+		for _, implied := range impliedScopes[scope] {
+			if granted == implied {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *TokenInfo) MissingScopes(required ...string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !t.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+func (g *GitHubClient) InspectToken() (*TokenInfo, error) {
 	url := fmt.Sprintf("%s/user", g.baseURL)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	g.setAuth(req)
 
 	resp, err := g.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to validate token: %w", err)
+		return nil, fmt.Errorf("failed to validate token: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("invalid token: %s", string(body))
+		return nil, fmt.Errorf("invalid token: %s", string(body))
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	info := &TokenInfo{Login: user.Login}
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		info.ScopesKnown = true
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
 	}
 
-	return nil
+	return info, nil
 }
 
 func (g *GitHubClient) fetchAllRepos(url string) ([]Repo, error) {
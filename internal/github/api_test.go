@@ -50,3 +50,120 @@ func TestFetchReposCreatedAfterStopsAfterThreshold(t *testing.T) {
 		t.Fatalf("requests = %d, want 1 (should stop after threshold on page 1)", requests)
 	}
 }
+
+func TestInspectTokenReportsScopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, admin:org")
+		_, _ = w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+	info, err := client.InspectToken()
+	if err != nil {
+		t.Fatalf("InspectToken() error = %v", err)
+	}
+	if info.Login != "octocat" || !info.ScopesKnown {
+		t.Fatalf("InspectToken() = %+v, want octocat with known scopes", info)
+	}
+	if missing := info.MissingScopes("repo", "read:org"); len(missing) != 0 {
+		t.Fatalf("MissingScopes() = %v, want none (admin:org implies read:org)", missing)
+	}
+}
+
+func TestInspectTokenFlagsMissingRepoScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "read:org")
+		_, _ = w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+	info, err := client.InspectToken()
+	if err != nil {
+		t.Fatalf("InspectToken() error = %v", err)
+	}
+	missing := info.MissingScopes("repo", "read:org")
+	if len(missing) != 1 || missing[0] != "repo" {
+		t.Fatalf("MissingScopes() = %v, want [repo]", missing)
+	}
+}
+
+func TestGetPullRequestDetectsForks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/api/pulls/7":
+			_, _ = w.Write([]byte(`{"number":7,"title":"Fix it","user":{"login":"octocat"},
+				"head":{"ref":"fix","repo":{"full_name":"octocat/api"}},
+				"base":{"ref":"main","repo":{"full_name":"acme/api"}}}`))
+		case "/repos/acme/api/pulls/8":
+			_, _ = w.Write([]byte(`{"number":8,"head":{"ref":"feature","repo":{"full_name":"acme/api"}},
+				"base":{"ref":"main","repo":{"full_name":"acme/api"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+
+	fork, err := client.GetPullRequest("acme/api", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequest(7) error = %v", err)
+	}
+	if fork.Title != "Fix it" || fork.User.Login != "octocat" || fork.Head.Ref != "fix" || !fork.IsFork() {
+		t.Fatalf("GetPullRequest(7) = %+v, want fork by octocat", fork)
+	}
+
+	local, err := client.GetPullRequest("acme/api", 8)
+	if err != nil {
+		t.Fatalf("GetPullRequest(8) error = %v", err)
+	}
+	if local.IsFork() {
+		t.Fatal("GetPullRequest(8).IsFork() = true, want false")
+	}
+
+	if _, err := client.GetPullRequest("acme/api", 9); err == nil {
+		t.Fatal("GetPullRequest(9) expected error")
+	}
+}
+
+func TestGetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/api/issues/12":
+			_, _ = w.Write([]byte(`{"number":12,"title":"Login fails on Safari","state":"open","user":{"login":"octocat"}}`))
+		case "/repos/acme/api/issues/13":
+			_, _ = w.Write([]byte(`{"number":13,"title":"Fix login","pull_request":{"url":"x"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+
+	issue, err := client.GetIssue("acme/api", 12)
+	if err != nil {
+		t.Fatalf("GetIssue(12) error = %v", err)
+	}
+	if issue.Title != "Login fails on Safari" || issue.User.Login != "octocat" || issue.IsPullRequest() {
+		t.Fatalf("GetIssue(12) = %+v", issue)
+	}
+
+	pull, err := client.GetIssue("acme/api", 13)
+	if err != nil {
+		t.Fatalf("GetIssue(13) error = %v", err)
+	}
+	if !pull.IsPullRequest() {
+		t.Fatal("GetIssue(13).IsPullRequest() = false, want true")
+	}
+
+	if _, err := client.GetIssue("acme/api", 14); err == nil {
+		t.Fatal("GetIssue(14) expected error")
+	}
+}
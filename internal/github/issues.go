@@ -0,0 +1,50 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Issue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	// PullRequest is set when the issue is a pull request; the issues API
+	// returns both.
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+func (i *Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+func (g *GitHubClient) GetIssue(repoFullName string, number int) (*Issue, error) {
+	url := fmt.Sprintf("%s/repos/%s/issues/%d", g.baseURL, repoFullName, number)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	g.setAuth(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("issue #%d not found or access denied in %s", number, repoFullName)
+	}
+
+	var issue Issue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &issue, nil
+}
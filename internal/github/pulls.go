@@ -0,0 +1,105 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type PullRequest struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Draft     bool      `json:"draft"`
+	HTMLURL   string    `json:"html_url"`
+	UpdatedAt time.Time `json:"updated_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head PullRequestRef `json:"head"`
+	Base PullRequestRef `json:"base"`
+}
+
+// PullRequestRef is the head or base of a pull request. Repo is nil when the
+// head fork has been deleted.
+type PullRequestRef struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	SHA   string `json:"sha"`
+	Repo  *Repo  `json:"repo"`
+}
+
+// IsFork reports whether the pull request's head lives in another repository.
+func (p *PullRequest) IsFork() bool {
+	if p.Head.Repo == nil || p.Base.Repo == nil {
+		return true
+	}
+	return p.Head.Repo.FullName != p.Base.Repo.FullName
+}
+
+func (g *GitHubClient) GetPullRequest(repoFullName string, number int) (*PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d", g.baseURL, repoFullName, number)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	g.setAuth(req)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pull request #%d not found or access denied in %s", number, repoFullName)
+	}
+
+	var pr PullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &pr, nil
+}
+
+func (g *GitHubClient) FetchOpenPullRequests(repoFullName string) ([]PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls?state=open&per_page=100&sort=updated&direction=desc", g.baseURL, repoFullName)
+
+	var allPulls []PullRequest
+	for url != "" {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		g.setAuth(req)
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		}
+
+		var pulls []PullRequest
+		if err := json.NewDecoder(resp.Body).Decode(&pulls); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		resp.Body.Close()
+
+		allPulls = append(allPulls, pulls...)
+
+		linkHeader := resp.Header.Get("Link")
+		url = extractNextURL(linkHeader)
+	}
+
+	return allPulls, nil
+}
@@ -0,0 +1,251 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StateDir is where ezgit keeps state under the home directory when
+// XDG_STATE_HOME is not set.
+const StateDir = ".local/state/ezgit"
+
+// MaxEntries is how many journal entries are kept; older ones are removed
+// when a new entry is written.
+const MaxEntries = 200
+
+// Action kinds. Each records enough of the before-state to be reverted.
+const (
+	BranchDeleted      = "branch_deleted"
+	WorktreeAdded      = "worktree_added"
+	WorktreeRemoved    = "worktree_removed"
+	WorktreeMoved      = "worktree_moved"
+	BranchRenamed      = "branch_renamed"
+	ConvertedToBare    = "converted_to_bare"
+	ConvertedToRegular = "converted_to_regular"
+	ConfigChanged      = "config_changed"
+)
+
+// Action is one mutating step of an ezgit command.
+type Action struct {
+	Kind string `json:"kind"`
+	// GitDir is the repository the action ran against.
+	GitDir string `json:"git_dir,omitempty"`
+	// Path is the affected worktree, or the repo root for conversions.
+	Path string `json:"path,omitempty"`
+	// Worktree is the worktree a converted repo's files moved into.
+	Worktree string `json:"worktree,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	SHA      string `json:"sha,omitempty"`
+	// To is where a moved worktree (Path) or renamed branch (Branch) went.
+	To string `json:"to,omitempty"`
+	// CreatedBranch is set when adding a worktree also created Branch.
+	CreatedBranch bool   `json:"created_branch,omitempty"`
+	Key           string `json:"key,omitempty"`
+	Value         string `json:"value,omitempty"`
+	// Existed reports whether Key was set before the change.
+	Existed bool `json:"existed,omitempty"`
+}
+
+// Describe returns a one-line summary of the action.
+func (a Action) Describe() string {
+	switch a.Kind {
+	case BranchDeleted:
+		return fmt.Sprintf("deleted branch %s at %s", a.Branch, shortSHA(a.SHA))
+	case WorktreeAdded:
+		if a.CreatedBranch {
+			return fmt.Sprintf("added worktree %s with new branch %s", a.Path, a.Branch)
+		}
+		return fmt.Sprintf("added worktree %s", a.Path)
+	case WorktreeRemoved:
+		if a.Branch != "" {
+			return fmt.Sprintf("removed worktree %s (%s at %s)", a.Path, a.Branch, shortSHA(a.SHA))
+		}
+		return fmt.Sprintf("removed worktree %s (detached at %s)", a.Path, shortSHA(a.SHA))
+	case WorktreeMoved:
+		return fmt.Sprintf("moved worktree %s to %s", a.Path, a.To)
+	case BranchRenamed:
+		return fmt.Sprintf("renamed branch %s to %s", a.Branch, a.To)
+	case ConvertedToBare:
+		return fmt.Sprintf("converted %s to bare, files moved to %s", a.Path, a.Worktree)
+	case ConvertedToRegular:
+		return fmt.Sprintf("converted %s to a regular clone", a.Path)
+	case ConfigChanged:
+		return fmt.Sprintf("changed %s in %s", a.Key, a.GitDir)
+	default:
+		return a.Kind
+	}
+}
+
+// Entry is the journal record of one ezgit invocation.
+type Entry struct {
+	ID       string     `json:"id"`
+	Time     time.Time  `json:"time"`
+	Command  string     `json:"command"`
+	Actions  []Action   `json:"actions"`
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+}
+
+// Journal writes the actions of one ezgit invocation to a single entry file,
+// created on the first action, and reads back past entries.
+type Journal struct {
+	dir     string
+	command string
+
+	mu      sync.Mutex
+	current *Entry
+}
+
+// DefaultDir returns $XDG_STATE_HOME/ezgit/journal, falling back to
+// ~/.local/state/ezgit/journal.
+func DefaultDir() string {
+	if stateHome := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); stateHome != "" {
+		return filepath.Join(stateHome, "ezgit", "journal")
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, StateDir, "journal")
+}
+
+// New returns a journal stored in dir whose entries are labelled command.
+func New(dir, command string) *Journal {
+	return &Journal{dir: dir, command: command}
+}
+
+// Record appends action to this invocation's entry and saves it right away,
+// so a command that fails part way still leaves a usable record. Journal
+// failures only warn: the operation itself already happened.
+func (j *Journal) Record(action Action) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.current == nil {
+		now := time.Now()
+		j.current = &Entry{
+			ID:      fmt.Sprintf("%s-%d", now.Format("20060102-150405"), os.Getpid()),
+			Time:    now,
+			Command: j.command,
+		}
+		j.prune()
+	}
+	j.current.Actions = append(j.current.Actions, action)
+	if err := j.write(*j.current); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write journal: %v\n", err)
+	}
+}
+
+// List returns all entries, newest first.
+func (j *Journal) List() ([]Entry, error) {
+	files, err := j.entryFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(files))
+	for i := len(files) - 1; i >= 0; i-- {
+		entry, err := readEntry(files[i])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Get returns the entry with the given id.
+func (j *Journal) Get(id string) (Entry, error) {
+	entry, err := readEntry(j.entryPath(id))
+	if os.IsNotExist(err) {
+		return Entry{}, fmt.Errorf("no journal entry %s", id)
+	}
+	return entry, err
+}
+
+// MarkUndone records that the entry was reverted.
+func (j *Journal) MarkUndone(id string) error {
+	entry, err := j.Get(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	entry.UndoneAt = &now
+	return j.write(entry)
+}
+
+func (j *Journal) write(entry Entry) error {
+	if err := os.MkdirAll(j.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := j.entryPath(entry.ID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// prune removes the oldest entries so at most MaxEntries remain once the
+// current entry is written.
+func (j *Journal) prune() {
+	files, err := j.entryFiles()
+	if err != nil {
+		return
+	}
+	for len(files) >= MaxEntries {
+		_ = os.Remove(files[0])
+		files = files[1:]
+	}
+}
+
+// entryFiles returns the entry files oldest first; IDs start with a
+// timestamp so name order is time order.
+func (j *Journal) entryFiles() ([]string, error) {
+	items, err := os.ReadDir(j.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var files []string
+	for _, item := range items {
+		if !item.IsDir() && strings.HasSuffix(item.Name(), ".json") {
+			files = append(files, filepath.Join(j.dir, item.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (j *Journal) entryPath(id string) string {
+	return filepath.Join(j.dir, filepath.Base(id)+".json")
+}
+
+func readEntry(path string) (Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return entry, nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,72 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordListAndMarkUndone(t *testing.T) {
+	dir := t.TempDir()
+	older := New(dir, "ezgit old")
+	older.Record(Action{Kind: BranchDeleted, Branch: "old", SHA: "1111111111"})
+	// Rename so the second entry sorts after the first regardless of timing.
+	oldID := older.current.ID
+	if err := os.Rename(filepath.Join(dir, oldID+".json"), filepath.Join(dir, "00000000-000000-1.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	j := New(dir, "ezgit rm acme/api feature")
+	j.Record(Action{Kind: WorktreeRemoved, Path: "/src/acme/api/feature", Branch: "feature", SHA: "abcdef1234"})
+	j.Record(Action{Kind: BranchDeleted, Branch: "feature", SHA: "abcdef1234"})
+
+	entries, err := j.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+	newest := entries[0]
+	if newest.Command != "ezgit rm acme/api feature" || len(newest.Actions) != 2 {
+		t.Fatalf("unexpected newest entry: %+v", newest)
+	}
+	if got := newest.Actions[1].Describe(); got != "deleted branch feature at abcdef1" {
+		t.Fatalf("Describe() = %q", got)
+	}
+
+	if err := j.MarkUndone(newest.ID); err != nil {
+		t.Fatalf("MarkUndone() error = %v", err)
+	}
+	entry, err := j.Get(newest.ID)
+	if err != nil || entry.UndoneAt == nil {
+		t.Fatalf("Get() = %+v, %v; want undone entry", entry, err)
+	}
+	if _, err := j.Get("missing"); err == nil {
+		t.Fatal("expected error for missing entry")
+	}
+}
+
+func TestRecordPrunesOldestEntries(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < MaxEntries+5; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("00000000-%06d-1.json", i))
+		if err := os.WriteFile(name, []byte(`{"id":"x"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	New(dir, "ezgit rm").Record(Action{Kind: BranchDeleted})
+
+	files, err := New(dir, "").entryFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != MaxEntries {
+		t.Fatalf("entries = %d, want %d", len(files), MaxEntries)
+	}
+	if filepath.Base(files[0]) != "00000000-000006-1.json" {
+		t.Fatalf("oldest kept entry = %s", filepath.Base(files[0]))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kirksw/ezgit/internal/git"
 	"github.com/kirksw/ezgit/internal/github"
 )
 
@@ -94,10 +95,27 @@ type FuzzySearchResult struct {
 	SelectedWorktree string
 	CreateWorktree   bool
 	WorktreeBase     string
+	// CheckoutPullRequest is set when the user asked to check out one of the
+	// repo's pull requests instead of opening a worktree.
+	CheckoutPullRequest bool
 }
 
+const checkoutPullRequestOption = "+ Check out pull request"
+
 type RepoWorktreeLoader func(repo github.Repo) ([]string, error)
 
+// RepoWorktreeInfoLoader loads worktrees with their status so the worktree
+// pane can show badges next to each name. It runs in the background, once
+// per repo, and may be called for several repos at the same time.
+type RepoWorktreeInfoLoader func(repo github.Repo) ([]git.WorktreeInfo, error)
+
+// worktreeInfoLoadedMsg carries the result of a RepoWorktreeInfoLoader call.
+type worktreeInfoLoadedMsg struct {
+	repoFullName string
+	infos        []git.WorktreeInfo
+	err          error
+}
+
 type page int
 
 const (
@@ -121,7 +139,12 @@ type model struct {
 	openMode           bool
 	repoWorktrees      map[string][]string
 	worktreeLoader     RepoWorktreeLoader
+	worktreeInfoLoader RepoWorktreeInfoLoader
+	worktreeBadges     map[string]map[string][]string
 	loadedWorktrees    map[string]bool
+	loadingWorktrees   map[string]bool
+	worktreeLoadErrors map[string]string
+	initialLoad        tea.Cmd
 	worktreeSelection  map[string]int
 	focusWorktreePane  bool
 	creatingWorktree   bool
@@ -139,6 +162,8 @@ type model struct {
 	lastInput          string
 	allowLocalToggle   bool
 	allowSettingsPage  bool
+	allowPullRequests  bool
+	checkoutPR         bool
 }
 
 func buildSearchableRepos(repos []github.Repo) []searchableRepo {
@@ -188,25 +213,28 @@ func newModelWithControls(
 	l.SetHeight(12)
 
 	m := model{
-		searchableRepos:   buildSearchableRepos(repos),
-		textinput:         ti,
-		repoList:          l,
-		worktree:          worktree,
-		localRepos:        localRepos,
-		openedRepos:       map[string]bool{},
-		openedWorktrees:   map[string]map[string]bool{},
-		repoWorktrees:     map[string][]string{},
-		loadedWorktrees:   map[string]bool{},
-		worktreeSelection: map[string]int{},
-		openMode:          openMode,
-		localOnly:         false,
-		filterIndex:       0,
-		currentPage:       pageMain,
-		settingsIndex:     0,
-		width:             80,
-		height:            20,
-		allowLocalToggle:  allowLocalToggle,
-		allowSettingsPage: allowSettingsPage,
+		searchableRepos:    buildSearchableRepos(repos),
+		textinput:          ti,
+		repoList:           l,
+		worktree:           worktree,
+		localRepos:         localRepos,
+		openedRepos:        map[string]bool{},
+		openedWorktrees:    map[string]map[string]bool{},
+		repoWorktrees:      map[string][]string{},
+		worktreeBadges:     map[string]map[string][]string{},
+		loadedWorktrees:    map[string]bool{},
+		loadingWorktrees:   map[string]bool{},
+		worktreeLoadErrors: map[string]string{},
+		worktreeSelection:  map[string]int{},
+		openMode:           openMode,
+		localOnly:          false,
+		filterIndex:        0,
+		currentPage:        pageMain,
+		settingsIndex:      0,
+		width:              80,
+		height:             20,
+		allowLocalToggle:   allowLocalToggle,
+		allowSettingsPage:  allowSettingsPage,
 	}
 
 	wtInput := textinput.New()
@@ -243,29 +271,61 @@ func normalizeWorktreeNames(worktrees []string) []string {
 	return normalized
 }
 
-func (m *model) ensureRepoWorktreesLoaded(repo *github.Repo) {
-	if !m.openMode || repo == nil || m.worktreeLoader == nil {
-		return
+// ensureRepoWorktreesLoaded loads the worktrees of repo once. Status info is
+// loaded by the returned command so moving the cursor never waits on git.
+func (m *model) ensureRepoWorktreesLoaded(repo *github.Repo) tea.Cmd {
+	if !m.openMode || repo == nil || (m.worktreeLoader == nil && m.worktreeInfoLoader == nil) {
+		return nil
 	}
 	repoFullName := strings.TrimSpace(repo.FullName)
 	if repoFullName == "" || !m.localRepos[repoFullName] {
-		return
+		return nil
 	}
 	if m.loadedWorktrees[repoFullName] {
-		return
+		return nil
 	}
 
 	m.loadedWorktrees[repoFullName] = true
+	if m.worktreeInfoLoader != nil {
+		m.loadingWorktrees[repoFullName] = true
+		loader, loadRepo := m.worktreeInfoLoader, *repo
+		return func() tea.Msg {
+			infos, err := loader(loadRepo)
+			return worktreeInfoLoadedMsg{repoFullName: repoFullName, infos: infos, err: err}
+		}
+	}
 	worktrees, err := m.worktreeLoader(*repo)
 	if err != nil {
 		m.repoWorktrees[repoFullName] = nil
-		return
+		return nil
 	}
 	m.repoWorktrees[repoFullName] = normalizeWorktreeNames(worktrees)
+	return nil
 }
 
-func (m *model) ensureSelectedRepoWorktreesLoaded() {
-	m.ensureRepoWorktreesLoaded(m.selectedRepoFromList())
+func (m *model) applyWorktreeInfo(msg worktreeInfoLoadedMsg) {
+	delete(m.loadingWorktrees, msg.repoFullName)
+	if msg.err != nil {
+		m.repoWorktrees[msg.repoFullName] = nil
+		m.worktreeLoadErrors[msg.repoFullName] = msg.err.Error()
+		return
+	}
+
+	names := make([]string, 0, len(msg.infos))
+	badges := make(map[string][]string, len(msg.infos))
+	for _, info := range msg.infos {
+		name := strings.TrimSpace(info.Name)
+		names = append(names, name)
+		if b := info.Badges(); len(b) > 0 {
+			badges[name] = b
+		}
+	}
+	m.repoWorktrees[msg.repoFullName] = normalizeWorktreeNames(names)
+	m.worktreeBadges[msg.repoFullName] = badges
+}
+
+func (m *model) ensureSelectedRepoWorktreesLoaded() tea.Cmd {
+	return m.ensureRepoWorktreesLoaded(m.selectedRepoFromList())
 }
 
 func (m model) currentFilterLabel() string {
@@ -310,6 +370,9 @@ func (m model) worktreeOptionsForRepo(repo *github.Repo) []string {
 		options = append(options, trimmed)
 	}
 	options = append(options, "+ Create new worktree")
+	if m.allowPullRequests {
+		options = append(options, checkoutPullRequestOption)
+	}
 	return options
 }
 
@@ -327,11 +390,15 @@ func parseWorktreeInlineInput(input string) (name string, base string) {
 }
 
 func (m model) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.initialLoad)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case worktreeInfoLoadedMsg:
+		m.applyWorktreeInfo(msg)
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -357,8 +424,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.worktreeInput.Width < 20 {
 			m.worktreeInput.Width = 20
 		}
-		m.ensureSelectedRepoWorktreesLoaded()
-		return m, nil
+		loadCmd := m.ensureSelectedRepoWorktreesLoaded()
+		return m, loadCmd
 
 	case tea.KeyMsg:
 		if msg.Type == tea.KeyCtrlC {
@@ -419,6 +486,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.worktreeInput.Focus()
 						return m, nil
 					}
+					if selected == checkoutPullRequestOption {
+						m.selected = repo
+						m.checkoutPR = true
+						m.quitting = true
+						return m, tea.Quit
+					}
 
 					m.selected = repo
 					m.selectedWorktree = selected
@@ -442,8 +515,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 				m.cycleFilter()
-				m.ensureSelectedRepoWorktreesLoaded()
-				return m, nil
+				loadCmd := m.ensureSelectedRepoWorktreesLoaded()
+				return m, loadCmd
 
 			case tea.KeyDown, tea.KeyCtrlN:
 				if m.openMode && m.focusWorktreePane {
@@ -474,8 +547,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case tea.KeyRight:
 				if m.openMode {
 					m.focusWorktreePane = true
-					m.ensureSelectedRepoWorktreesLoaded()
-					return m, nil
+					loadCmd := m.ensureSelectedRepoWorktreesLoaded()
+					return m, loadCmd
 				}
 
 			case tea.KeyLeft:
@@ -556,9 +629,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.repoList.SetItems(m.filterRepos(current))
 		m.repoList.ResetSelected()
 	}
-	m.ensureSelectedRepoWorktreesLoaded()
 
-	return m, cmd
+	loadCmd := m.ensureSelectedRepoWorktreesLoaded()
+	return m, tea.Batch(cmd, loadCmd)
 }
 
 func (m model) View() string {
@@ -657,6 +730,7 @@ func (m model) renderMainPage() string {
 				Background(lipgloss.Color("236")).
 				Padding(0, 1)
 			rightMuted := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+			rightStatusBadge := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
 
 			var right strings.Builder
 			repo := m.selectedRepoFromList()
@@ -666,8 +740,15 @@ func (m model) renderMainPage() string {
 				right.WriteString(rightMuted.Render("Select a repository to view worktrees"))
 			} else {
 				options := m.worktreeOptionsForRepo(repo)
-				if len(options) == 1 && options[0] == "+ Create new worktree" {
-					right.WriteString(rightMuted.Render("No worktrees yet"))
+				if options[0] == "+ Create new worktree" {
+					switch {
+					case m.loadingWorktrees[repo.FullName]:
+						right.WriteString(rightMuted.Render("Loading worktrees..."))
+					case m.worktreeLoadErrors[repo.FullName] != "":
+						right.WriteString(rightMuted.Render("Failed to load worktrees: " + m.worktreeLoadErrors[repo.FullName]))
+					default:
+						right.WriteString(rightMuted.Render("No worktrees yet"))
+					}
 					right.WriteString("\n\n")
 				}
 				idx := m.worktreeSelection[repo.FullName]
@@ -676,7 +757,7 @@ func (m model) renderMainPage() string {
 				}
 				for i, option := range options {
 					isSelected := m.focusWorktreePane && i == idx
-					isCreate := option == "+ Create new worktree"
+					isCreate := option == "+ Create new worktree" || option == checkoutPullRequestOption
 					label := option
 					if !isCreate {
 						if openedByRepo, ok := m.openedWorktrees[repo.FullName]; ok {
@@ -684,6 +765,9 @@ func (m model) renderMainPage() string {
 								label = option + " " + rightOpenBadge.Render("[open]")
 							}
 						}
+						if badges := m.worktreeBadges[repo.FullName][option]; len(badges) > 0 {
+							label += " " + rightStatusBadge.Render(strings.Join(badges, " "))
+						}
 					}
 
 					line := "  " + label
@@ -1606,7 +1690,34 @@ func RunOpenFuzzySearchWithOpenedAndLoader(
 	worktreesByRepo map[string][]string,
 	worktreeLoader RepoWorktreeLoader,
 ) (*FuzzySearchResult, error) {
+	m := newOpenModel(repos, localRepos, openedRepos, openedWorktrees, worktreesByRepo)
+	m.worktreeLoader = worktreeLoader
+	return runOpenModel(m)
+}
+
+// RunOpenFuzzySearchWithOpenedAndInfoLoader is like
+// RunOpenFuzzySearchWithOpenedAndLoader but shows worktree status badges.
+func RunOpenFuzzySearchWithOpenedAndInfoLoader(
+	repos []github.Repo,
+	localRepos map[string]bool,
+	openedRepos map[string]bool,
+	openedWorktrees map[string]map[string]bool,
+	worktreeInfoLoader RepoWorktreeInfoLoader,
+) (*FuzzySearchResult, error) {
+	m := newOpenModel(repos, localRepos, openedRepos, openedWorktrees, nil)
+	m.worktreeInfoLoader = worktreeInfoLoader
+	return runOpenModel(m)
+}
+
+func newOpenModel(
+	repos []github.Repo,
+	localRepos map[string]bool,
+	openedRepos map[string]bool,
+	openedWorktrees map[string]map[string]bool,
+	worktreesByRepo map[string][]string,
+) model {
 	m := newModelWithControls(repos, false, localRepos, true, true, false)
+	m.allowPullRequests = true
 	if openedRepos != nil {
 		m.openedRepos = openedRepos
 	}
@@ -1619,9 +1730,12 @@ func RunOpenFuzzySearchWithOpenedAndLoader(
 			m.loadedWorktrees[repoFullName] = true
 		}
 	}
-	m.worktreeLoader = worktreeLoader
+	return m
+}
+
+func runOpenModel(m model) (*FuzzySearchResult, error) {
 	m.repoList.SetItems(m.filterRepos(m.textinput.Value()))
-	m.ensureSelectedRepoWorktreesLoaded()
+	m.initialLoad = m.ensureSelectedRepoWorktreesLoaded()
 
 	p := tea.NewProgram(
 		m,
@@ -1640,12 +1754,13 @@ func RunOpenFuzzySearchWithOpenedAndLoader(
 
 	if m.selected != nil {
 		return &FuzzySearchResult{
-			Repo:             m.selected,
-			Worktree:         m.worktree,
-			Action:           ActionOpen,
-			SelectedWorktree: m.selectedWorktree,
-			CreateWorktree:   m.createWorktree,
-			WorktreeBase:     m.createWorktreeBase,
+			Repo:                m.selected,
+			Worktree:            m.worktree,
+			Action:              ActionOpen,
+			SelectedWorktree:    m.selectedWorktree,
+			CreateWorktree:      m.createWorktree,
+			WorktreeBase:        m.createWorktreeBase,
+			CheckoutPullRequest: m.checkoutPR,
 		}, nil
 	}
 
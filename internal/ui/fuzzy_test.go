@@ -1,10 +1,12 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirksw/ezgit/internal/git"
 	"github.com/kirksw/ezgit/internal/github"
 )
 
@@ -305,6 +307,27 @@ func TestWorktreeOptionsForRepoHidesRepoRoot(t *testing.T) {
 	}
 }
 
+func TestOpenModelPullRequestOptionRequestsCheckout(t *testing.T) {
+	repo := github.Repo{Name: "foo", FullName: "org/foo"}
+	m := newOpenModel([]github.Repo{repo}, map[string]bool{"org/foo": true}, nil, nil, map[string][]string{"org/foo": {"main"}})
+
+	options := m.worktreeOptionsForRepo(&repo)
+	if len(options) != 3 || options[2] != "+ Check out pull request" {
+		t.Fatalf("options=%v, want pull request option last", options)
+	}
+
+	m.focusWorktreePane = true
+	m.worktreeSelection[repo.FullName] = 2
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if !m.checkoutPR || m.selected == nil || m.selected.FullName != repo.FullName {
+		t.Fatalf("checkoutPR=%v selected=%v, want pull request checkout for org/foo", m.checkoutPR, m.selected)
+	}
+	if m.selectedWorktree != "" {
+		t.Fatalf("selectedWorktree=%q, want empty", m.selectedWorktree)
+	}
+}
+
 func TestEnsureSelectedRepoWorktreesLoadedLoadsOnceAndNormalizes(t *testing.T) {
 	repo := github.Repo{Name: "foo", FullName: "org/foo"}
 	m := newModel([]github.Repo{repo}, false, map[string]bool{"org/foo": true}, true)
@@ -366,6 +389,56 @@ func TestOpenModeViewShowsOpenedWorktreeHighlight(t *testing.T) {
 	}
 }
 
+func TestOpenModeViewShowsWorktreeStatusBadges(t *testing.T) {
+	repo := github.Repo{Name: "foo", FullName: "org/foo"}
+	m := newModel([]github.Repo{repo}, false, map[string]bool{"org/foo": true}, true)
+	m.worktreeInfoLoader = func(repo github.Repo) ([]git.WorktreeInfo, error) {
+		return []git.WorktreeInfo{
+			{Name: "main", Branch: "main"},
+			{Name: "feature", Branch: "feature", Dirty: 2, Ahead: 1},
+			{Name: "broken", Error: "git status failed"},
+		}, nil
+	}
+	loadCmd := m.ensureSelectedRepoWorktreesLoaded()
+	if loadCmd == nil {
+		t.Fatal("expected worktree info to load in the background")
+	}
+	if !strings.Contains(m.View(), "Loading worktrees...") {
+		t.Fatalf("view missing loading hint, got: %q", m.View())
+	}
+	if m.ensureSelectedRepoWorktreesLoaded() != nil {
+		t.Fatal("worktree info loaded twice for the same repo")
+	}
+	updated, _ := m.Update(loadCmd())
+	m = updated.(model)
+
+	if got := m.repoWorktrees[repo.FullName]; len(got) != 3 || got[0] != "main" || got[1] != "feature" || got[2] != "broken" {
+		t.Fatalf("worktrees=%v, want [main feature broken]", got)
+	}
+	if _, ok := m.worktreeBadges[repo.FullName]["main"]; ok {
+		t.Fatal("clean worktree should have no badges")
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "~2 ↑1") || !strings.Contains(view, "broken") || !strings.Contains(view, "error") {
+		t.Fatalf("view missing status badges, got: %q", view)
+	}
+}
+
+func TestOpenModeViewShowsWorktreeLoadError(t *testing.T) {
+	repo := github.Repo{Name: "foo", FullName: "org/foo"}
+	m := newModel([]github.Repo{repo}, false, map[string]bool{"org/foo": true}, true)
+	m.worktreeInfoLoader = func(repo github.Repo) ([]git.WorktreeInfo, error) {
+		return nil, fmt.Errorf("not a git repository")
+	}
+	updated, _ := m.Update(m.ensureSelectedRepoWorktreesLoaded()())
+	m = updated.(model)
+
+	if view := m.View(); !strings.Contains(view, "Failed to load worktrees: not a git") {
+		t.Fatalf("view missing load error, got: %q", view)
+	}
+}
+
 func TestMainViewShowsScopeAndTabKeybind(t *testing.T) {
 	repo := github.Repo{Name: "foo", FullName: "org/foo"}
 	m := newModel([]github.Repo{repo}, false, map[string]bool{"org/foo": true}, true)
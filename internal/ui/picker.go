@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PickerLabels are the texts shown by a single-line picker.
+type PickerLabels struct {
+	Title       string
+	Placeholder string
+	Empty       string
+	Action      string
+}
+
+// RunPicker shows items in a filterable list, like the tmux session picker,
+// and returns the chosen one.
+func RunPicker(items []string, labels PickerLabels) (selected string, cancelled bool, err error) {
+	p := tea.NewProgram(
+		newTmuxSessionModelWithLabels(items, labels),
+		tea.WithAltScreen(),
+	)
+	finalModel, err := p.Run()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to run picker: %w", err)
+	}
+
+	m, ok := finalModel.(tmuxSessionModel)
+	if !ok {
+		return "", false, fmt.Errorf("unexpected model type")
+	}
+	if m.cancelled {
+		return "", true, nil
+	}
+	return strings.TrimSpace(m.selected), false, nil
+}
@@ -37,8 +37,18 @@ func (d tmuxSessionDelegate) Render(w io.Writer, m list.Model, index int, listIt
 	fmt.Fprint(w, style.Render(item.name))
 }
 
+// tmuxSessionLabels are the texts of the tmux session picker; RunPicker
+// reuses the model with its own.
+var tmuxSessionLabels = PickerLabels{
+	Title:       "Select tmux session",
+	Placeholder: "Search sessions...",
+	Empty:       "No sessions found",
+	Action:      "connect",
+}
+
 type tmuxSessionModel struct {
 	sessions  []string
+	labels    PickerLabels
 	list      list.Model
 	input     textinput.Model
 	selected  string
@@ -48,8 +58,12 @@ type tmuxSessionModel struct {
 }
 
 func newTmuxSessionModel(sessions []string) tmuxSessionModel {
+	return newTmuxSessionModelWithLabels(sessions, tmuxSessionLabels)
+}
+
+func newTmuxSessionModelWithLabels(sessions []string, labels PickerLabels) tmuxSessionModel {
 	input := textinput.New()
-	input.Placeholder = "Search sessions..."
+	input.Placeholder = labels.Placeholder
 	input.Focus()
 	input.CharLimit = 256
 	input.Width = 80
@@ -64,6 +78,7 @@ func newTmuxSessionModel(sessions []string) tmuxSessionModel {
 
 	m := tmuxSessionModel{
 		sessions: sessions,
+		labels:   labels,
 		list:     l,
 		input:    input,
 	}
@@ -133,7 +148,7 @@ func (m tmuxSessionModel) View() string {
 	instructionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
 
 	var b strings.Builder
-	b.WriteString(headerStyle.Render("Select tmux session"))
+	b.WriteString(headerStyle.Render(m.labels.Title))
 	b.WriteString("\n\n")
 	b.WriteString(m.input.View())
 	b.WriteString("\n\n")
@@ -141,11 +156,11 @@ func (m tmuxSessionModel) View() string {
 	if len(m.list.Items()) > 0 {
 		b.WriteString(m.list.View())
 	} else {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("No sessions found"))
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(m.labels.Empty))
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(instructionStyle.Render("up/down: navigate | enter: connect | esc: cancel"))
+	b.WriteString(instructionStyle.Render("up/down: navigate | enter: " + m.labels.Action + " | esc: cancel"))
 	return b.String()
 }
 
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CopyTree copies the file, directory or symlink at src to dst, which must
+// not exist yet. Regular files are cloned copy-on-write where the filesystem
+// supports it (reflinks on Btrfs and XFS, clonefile on APFS) and copied byte
+// by byte otherwise. Symlinks are recreated as they are and file modes kept;
+// sockets and devices are skipped.
+func CopyTree(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	case info.IsDir():
+		if err := os.Mkdir(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := CopyTree(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case info.Mode().IsRegular():
+		if cloneFile(src, dst, info.Mode().Perm()) == nil {
+			return nil
+		}
+		return copyFile(src, dst, info.Mode().Perm())
+	default:
+		return nil
+	}
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
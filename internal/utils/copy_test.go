@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyTreeCopiesFilesDirectoriesAndSymlinks(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	if err := os.MkdirAll(filepath.Join(src, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "settings.json"), []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "bin", "run"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("settings.json", filepath.Join(src, "current")); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	if err := CopyTree(src, dst); err != nil {
+		t.Fatalf("CopyTree() error = %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dst, "settings.json")); err != nil || string(data) != "{}\n" {
+		t.Fatalf("settings.json = %q, %v", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "bin", "run")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Fatalf("bin/run lost its executable bit: %v, %v", info, err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "current")); err != nil || target != "settings.json" {
+		t.Fatalf("current link = %q, %v", target, err)
+	}
+
+	// Copies never overwrite.
+	if err := CopyTree(filepath.Join(src, "settings.json"), filepath.Join(dst, "settings.json")); err == nil {
+		t.Fatal("CopyTree() onto an existing file succeeded, want an error")
+	}
+}
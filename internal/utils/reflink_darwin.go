@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile clones src to dst with clonefile(2), which only works within an
+// APFS volume. The clone keeps src's mode, so perm is unused.
+func cloneFile(src, dst string, perm os.FileMode) error {
+	return unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+}
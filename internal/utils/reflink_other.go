@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package utils
+
+import (
+	"errors"
+	"os"
+)
+
+// cloneFile has no copy-on-write support here; CopyTree copies instead.
+func cloneFile(src, dst string, perm os.FileMode) error {
+	return errors.ErrUnsupported
+}